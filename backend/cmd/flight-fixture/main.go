@@ -0,0 +1,35 @@
+// Command flight-fixture serves the flight API from a local fixture, for
+// running the flights transport provider without a real flight API:
+//
+//	go run ./cmd/flight-fixture
+//	FLIGHT_PROVIDER_BASE_URL=http://localhost:8091 TRANSPORT_PROVIDERS=flights go run ./cmd/server
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8091"
+	}
+	var data []byte
+	if path := os.Getenv("FLIGHT_FIXTURE_FILE"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("read flight fixture: %v", err)
+		}
+		data = raw
+	}
+	handler, err := provider.FlightFixtureHandler(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("flight fixture running on :%s", port)
+	log.Fatal(http.ListenAndServe(":"+port, handler))
+}
@@ -11,11 +11,17 @@ import (
 
 	"exchange-travel-planner/backend/internal/db"
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
 	"exchange-travel-planner/backend/internal/httpapi"
+	"exchange-travel-planner/backend/internal/jobs"
+	"exchange-travel-planner/backend/internal/provider"
 	"exchange-travel-planner/backend/internal/store"
 )
 
-var _ domain.DataStore = (*db.PgStore)(nil)
+var (
+	_ domain.DataStore     = (*db.PgStore)(nil)
+	_ provider.SearchCache = (*db.PgSearchCache)(nil)
+)
 
 func main() {
 	port := os.Getenv("PORT")
@@ -24,6 +30,7 @@ func main() {
 	}
 
 	var ds domain.DataStore
+	var cache provider.SearchCache
 
 	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
 		gormDB, err := db.Connect(dsn)
@@ -31,16 +38,40 @@ func main() {
 			log.Fatalf("postgres connect: %v", err)
 		}
 		ds = db.NewPgStore(gormDB)
+		cache = db.NewPgSearchCache(gormDB)
 		log.Println("using PostgreSQL store")
 	} else {
 		ds = store.New()
+		cache = provider.NewMemorySearchCache()
 		log.Println("using in-memory store (set DATABASE_URL for Postgres)")
 	}
 	defer ds.Close()
 
+	if path := os.Getenv("FX_RATES_FILE"); path != "" {
+		rates, err := fx.LoadFile(path)
+		if err != nil {
+			log.Fatalf("load fx rates: %v", err)
+		}
+		log.Printf("loaded %d fx rates from %s", ds.SaveFXRates(rates), path)
+	}
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	interval := time.Hour
+	if raw := os.Getenv("RECURRING_JOB_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid RECURRING_JOB_INTERVAL %q", raw)
+		}
+		interval = d
+	}
+	go jobs.RunRecurring(jobCtx, ds, interval, time.Now)
+
+	api := httpapi.NewServer(ds)
+	api.UseSearchCache(cache)
 	srv := &http.Server{
 		Addr:    ":" + port,
-		Handler: httpapi.NewServer(ds).Routes(),
+		Handler: api.Routes(),
 	}
 
 	go func() {
@@ -54,6 +85,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("shutting down...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,144 @@
+package bankimport
+
+import (
+	"os"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func parseFile(t *testing.T, name string, p Profile) []Transaction {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	txns, err := ParseCSV(f, p)
+	if err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+	return txns
+}
+
+func TestParseCSV_N26(t *testing.T) {
+	p, _ := LookupProfile("n26")
+	txns := parseFile(t, "n26.csv", p)
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(txns))
+	}
+	if tx := txns[2]; tx.Date != "2026-02-05" || tx.Amount != money.FromFloat(-19.99) || tx.Currency != "EUR" || tx.Description != "FlixBus · Berlin - Prague" || tx.Line != 4 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+}
+
+func TestParseCSV_INGSkipsPreambleAndReadsDecimalComma(t *testing.T) {
+	p, _ := LookupProfile("ing-de")
+	txns := parseFile(t, "ing-de.csv", p)
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(txns))
+	}
+	if tx := txns[0]; tx.Date != "2026-02-05" || tx.Amount != money.FromFloat(-420) || tx.Currency != "EUR" {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if txns[2].Error == "" {
+		t.Fatalf("expected 31.02. to be rejected, got %+v", txns[2])
+	}
+}
+
+func TestParseCSV_RevolutStatesAndFees(t *testing.T) {
+	p, _ := LookupProfile("revolut")
+	txns := parseFile(t, "revolut.csv", p)
+	if len(txns) != 2 {
+		t.Fatalf("expected pending payment to be dropped, got %d", len(txns))
+	}
+	if tx := txns[1]; tx.Amount != money.FromFloat(-40.5) || tx.Date != "2026-02-08" {
+		t.Fatalf("expected fee added to the payment, got %+v", tx)
+	}
+}
+
+func TestParseCSV_MissingColumns(t *testing.T) {
+	p, _ := LookupProfile("revolut")
+	f, _ := os.Open("testdata/n26.csv")
+	defer f.Close()
+	if _, err := ParseCSV(f, p); err == nil {
+		t.Fatalf("expected an error for a file without the profile's columns")
+	}
+}
+
+func TestParseCAMT053(t *testing.T) {
+	f, err := os.Open("testdata/camt053.xml")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	txns, err := ParseCAMT053(f)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(txns) != 2 {
+		t.Fatalf("expected pending entry to be dropped, got %d", len(txns))
+	}
+	if tx := txns[0]; tx.Amount != money.FromFloat(-12.8) || tx.Date != "2026-02-03" || tx.Description != "Deutsche Bahn AG · Ticket 4711" {
+		t.Fatalf("unexpected debit %+v", tx)
+	}
+	if txns[1].Amount != money.FromFloat(200) {
+		t.Fatalf("expected credit to stay positive, got %+v", txns[1])
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		raw   string
+		comma bool
+		want  money.Amount
+	}{
+		{"-1,234.56", false, money.Cents(-123456)},
+		{"-1.234,56", true, money.Cents(-123456)},
+		{"12.50 EUR", false, money.Cents(1250)},
+		{"−7,00", true, money.Cents(-700)},
+	}
+	for _, tc := range cases {
+		if got, err := ParseAmount(tc.raw, tc.comma); err != nil || got != tc.want {
+			t.Fatalf("ParseAmount(%q) = %v, %v; want %v", tc.raw, got, err, tc.want)
+		}
+	}
+}
+
+func TestPlan_DuplicatesRulesAndCredits(t *testing.T) {
+	txns := []Transaction{
+		{Line: 2, Date: "2026-02-03", Amount: money.FromFloat(-3.2), Currency: "EUR", Description: "Cafe Einstein"},
+		{Line: 3, Date: "2026-02-03", Amount: money.FromFloat(-3.2), Currency: "EUR", Description: "Cafe Einstein"},
+		{Line: 4, Date: "2026-02-04", Amount: money.FromFloat(-23.45), Currency: "eur", Description: "REWE Markt"},
+		{Line: 5, Date: "2026-02-04", Amount: money.FromFloat(450), Currency: "EUR", Description: "Salary"},
+		{Line: 6, Date: "2026-02-05", Amount: money.FromFloat(-10), Currency: "XYZ", Description: "Unknown"},
+		{Line: 7, Error: "invalid date"},
+	}
+	rows, summary := Plan(txns, PlanOptions{
+		Existing:  []domain.BudgetEntry{{Date: "2026-02-03", Amount: money.FromFloat(3.2), Currency: "EUR"}},
+		Rules:     []domain.CategoryRule{{Keyword: "einstein", Category: "coffee"}},
+		Supported: func(currency, _ string) bool { return currency != "XYZ" },
+	})
+	want := []RowStatus{RowDuplicate, RowNew, RowNew, RowIgnored, RowInvalid, RowInvalid}
+	for i, row := range rows {
+		if row.Status != want[i] {
+			t.Fatalf("row %d: expected %s, got %s (%s)", i, want[i], row.Status, row.Reason)
+		}
+	}
+	if summary != (Summary{New: 2, Duplicates: 1, Ignored: 1, Invalid: 2}) {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if rows[1].Category != "coffee" || rows[2].Category != "food" {
+		t.Fatalf("unexpected categories %q, %q", rows[1].Category, rows[2].Category)
+	}
+	if entry := rows[2].Entry("demo-user"); entry.Amount != money.FromFloat(23.45) || entry.Currency != "EUR" || entry.Note != "REWE Markt" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+}
+
+func TestCategorize_Fallback(t *testing.T) {
+	if got := Categorize("Some shop", DefaultRules); got != DefaultCategory {
+		t.Fatalf("expected %s, got %s", DefaultCategory, got)
+	}
+}
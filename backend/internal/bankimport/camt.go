@@ -0,0 +1,113 @@
+package bankimport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// camtDocument covers the parts of an ISO 20022 camt.053 statement we read.
+// Elements are matched by local name, so any camt.053.001.xx namespace works.
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	CreditDebit string `xml:"CdtDbtInd"`
+	// Sts is a bare code in older versions and <Sts><Cd> from .08 on.
+	Status struct {
+		Value string `xml:",chardata"`
+		Code  string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate string `xml:"BookgDt>Dt"`
+	BookingTime string `xml:"BookgDt>DtTm"`
+	ValueDate   string `xml:"ValDt>Dt"`
+	Info        string `xml:"AddtlNtryInf"`
+	Details     []struct {
+		Creditor    string   `xml:"RltdPties>Cdtr>Nm"`
+		CreditorPty string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+		Remittance  []string `xml:"RmtInf>Ustrd"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+// ParseCAMT053 reads booked entries from a camt.053 bank-to-customer
+// statement. Debits become negative amounts. Line is the entry's position
+// in the file, starting at 1.
+func ParseCAMT053(r io.Reader) ([]Transaction, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode camt.053: %w", err)
+	}
+	var out []Transaction
+	n := 0
+	for _, stmt := range doc.Statements {
+		for _, e := range stmt.Entries {
+			n++
+			status := strings.TrimSpace(e.Status.Code)
+			if status == "" {
+				status = strings.TrimSpace(e.Status.Value)
+			}
+			if status != "" && !strings.EqualFold(status, "BOOK") {
+				continue
+			}
+
+			tx := Transaction{Line: n, Currency: strings.TrimSpace(e.Amount.Currency), Description: camtDescription(e)}
+			date := strings.TrimSpace(e.BookingDate)
+			if date == "" && len(e.BookingTime) >= 10 {
+				date = e.BookingTime[:10]
+			}
+			if date == "" {
+				date = strings.TrimSpace(e.ValueDate)
+			}
+			if len(date) != 10 {
+				tx.Error = fmt.Sprintf("invalid booking date %q", date)
+				out = append(out, tx)
+				continue
+			}
+			tx.Date = date
+			amount, err := money.Parse(e.Amount.Value)
+			if err != nil {
+				tx.Error = fmt.Sprintf("invalid amount %q", e.Amount.Value)
+				out = append(out, tx)
+				continue
+			}
+			if strings.EqualFold(strings.TrimSpace(e.CreditDebit), "DBIT") {
+				amount = -amount
+			}
+			tx.Amount = amount
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+func camtDescription(e camtEntry) string {
+	var parts []string
+	for _, d := range e.Details {
+		for _, s := range []string{d.Creditor, d.CreditorPty} {
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		for _, s := range d.Remittance {
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		if info := strings.TrimSpace(e.Info); info != "" {
+			parts = append(parts, info)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
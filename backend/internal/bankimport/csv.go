@@ -0,0 +1,166 @@
+package bankimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// Transaction is one statement line. Amount is signed: negative amounts left
+// the account. Error is set when the line could not be read.
+type Transaction struct {
+	Line        int          `json:"line"`
+	Date        string       `json:"date,omitempty"`
+	Amount      money.Amount `json:"amount"`
+	Currency    string       `json:"currency,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// ParseCSV reads a CSV export using p. Lines before the header row (account
+// metadata many banks prepend) are skipped. Rows filtered out by the
+// profile's state column are dropped; unreadable rows are returned with Error set.
+func ParseCSV(r io.Reader, p Profile) ([]Transaction, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if p.Delimiter != "" {
+		delim, _ := utf8.DecodeRuneInString(p.Delimiter)
+		reader.Comma = delim
+	}
+	layout := p.DateLayout
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+
+	var columns map[string]int
+	for columns == nil {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no header row with %q and %q columns", p.DateColumn, p.AmountColumn)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		index := map[string]int{}
+		for i, name := range row {
+			name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+			if _, dup := index[name]; !dup {
+				index[name] = i
+			}
+		}
+		_, hasDate := index[strings.ToLower(p.DateColumn)]
+		_, hasAmount := index[strings.ToLower(p.AmountColumn)]
+		if hasDate && hasAmount {
+			columns = index
+		}
+	}
+	for _, name := range append([]string{p.CurrencyColumn, p.FeeColumn, p.StateColumn}, p.DescriptionColumns...) {
+		if _, ok := columns[strings.ToLower(name)]; name != "" && !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var out []Transaction
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(name string) string {
+			i, ok := columns[strings.ToLower(name)]
+			if name == "" || !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+		if p.StateColumn != "" && !containsFold(p.States, cell(p.StateColumn)) {
+			continue
+		}
+
+		tx := Transaction{Line: line, Currency: p.Currency}
+		if p.CurrencyColumn != "" {
+			tx.Currency = cell(p.CurrencyColumn)
+		}
+		parts := make([]string, 0, len(p.DescriptionColumns))
+		for _, name := range p.DescriptionColumns {
+			if v := cell(name); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		tx.Description = strings.Join(parts, " · ")
+
+		date, err := time.Parse(layout, cell(p.DateColumn))
+		if err != nil {
+			tx.Error = fmt.Sprintf("invalid date %q", cell(p.DateColumn))
+			out = append(out, tx)
+			continue
+		}
+		tx.Date = date.Format("2006-01-02")
+		amount, err := ParseAmount(cell(p.AmountColumn), p.DecimalComma)
+		if err != nil {
+			tx.Error = err.Error()
+			out = append(out, tx)
+			continue
+		}
+		if fee := cell(p.FeeColumn); fee != "" {
+			f, err := ParseAmount(fee, p.DecimalComma)
+			if err != nil {
+				tx.Error = err.Error()
+				out = append(out, tx)
+				continue
+			}
+			// Fees are reported as positive charges on top of the payment.
+			amount -= f
+		}
+		tx.Amount = amount
+		out = append(out, tx)
+	}
+	return out, nil
+}
+
+// ParseAmount reads amounts such as "-1,234.56", "-1.234,56" (decimalComma)
+// or "12.50 EUR".
+func ParseAmount(raw string, decimalComma bool) (money.Amount, error) {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',', r == '-':
+			return r
+		case r == '−': // Unicode minus
+			return '-'
+		}
+		return -1
+	}, raw)
+	if decimalComma {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	v, err := money.Parse(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", raw)
+	}
+	return v, nil
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
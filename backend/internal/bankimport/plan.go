@@ -0,0 +1,161 @@
+package bankimport
+
+import (
+	"fmt"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	// DefaultCategory is used when no rule matches a transaction.
+	DefaultCategory = "other"
+	maxNoteLength   = 200
+)
+
+// DefaultRules cover merchants common in student budgets across Europe.
+// User rules are checked first.
+var DefaultRules = []domain.CategoryRule{
+	{Keyword: "miete", Category: "living"},
+	{Keyword: "rent", Category: "living"},
+	{Keyword: "loyer", Category: "living"},
+	{Keyword: "vodafone", Category: "living"},
+	{Keyword: "telekom", Category: "living"},
+	{Keyword: "rewe", Category: "food"},
+	{Keyword: "lidl", Category: "food"},
+	{Keyword: "aldi", Category: "food"},
+	{Keyword: "edeka", Category: "food"},
+	{Keyword: "carrefour", Category: "food"},
+	{Keyword: "albert heijn", Category: "food"},
+	{Keyword: "tesco", Category: "food"},
+	{Keyword: "mensa", Category: "food"},
+	{Keyword: "deutsche bahn", Category: "travel"},
+	{Keyword: "db vertrieb", Category: "travel"},
+	{Keyword: "sncf", Category: "travel"},
+	{Keyword: "trenitalia", Category: "travel"},
+	{Keyword: "flixbus", Category: "travel"},
+	{Keyword: "ryanair", Category: "travel"},
+	{Keyword: "easyjet", Category: "travel"},
+	{Keyword: "hostelworld", Category: "travel"},
+	{Keyword: "booking.com", Category: "travel"},
+	{Keyword: "airbnb", Category: "travel"},
+}
+
+// Categorize returns the category of the first rule, across rule sets in
+// order, whose keyword appears in description.
+func Categorize(description string, ruleSets ...[]domain.CategoryRule) string {
+	lower := strings.ToLower(description)
+	for _, rules := range ruleSets {
+		for _, rule := range rules {
+			keyword := strings.ToLower(strings.TrimSpace(rule.Keyword))
+			if keyword != "" && strings.Contains(lower, keyword) {
+				return rule.Category
+			}
+		}
+	}
+	return DefaultCategory
+}
+
+type RowStatus string
+
+const (
+	RowNew       RowStatus = "new"
+	RowDuplicate RowStatus = "duplicate"
+	// RowIgnored rows are incoming payments, which are not spending.
+	RowIgnored RowStatus = "ignored"
+	RowInvalid RowStatus = "invalid"
+)
+
+// Row is one transaction in an import preview.
+type Row struct {
+	Transaction
+	Category string    `json:"category,omitempty"`
+	Status   RowStatus `json:"status"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type Summary struct {
+	New        int `json:"new"`
+	Duplicates int `json:"duplicates"`
+	Ignored    int `json:"ignored"`
+	Invalid    int `json:"invalid"`
+}
+
+// PlanOptions supplies what Plan checks transactions against.
+type PlanOptions struct {
+	// Existing entries are matched for duplicates.
+	Existing []domain.BudgetEntry
+	// Rules are checked before DefaultRules.
+	Rules []domain.CategoryRule
+	// Supported reports whether a currency can be converted; nil accepts all.
+	Supported func(currency, date string) bool
+}
+
+// Plan decides what importing txns would do. An outgoing payment is a
+// duplicate when an existing entry has the same date, amount and currency;
+// each existing entry absorbs at most one transaction, so two identical
+// coffees on one day still import twice the first time.
+func Plan(txns []Transaction, opts PlanOptions) ([]Row, Summary) {
+	seen := map[string]int{}
+	for _, entry := range opts.Existing {
+		seen[dedupeKey(entry.Date, entry.Amount, entry.Currency)]++
+	}
+
+	rows := make([]Row, 0, len(txns))
+	var summary Summary
+	for _, tx := range txns {
+		tx.Currency = fx.Normalize(tx.Currency)
+		row := Row{Transaction: tx}
+		switch {
+		case tx.Error != "":
+			row.Status, row.Reason = RowInvalid, tx.Error
+		case tx.Amount >= 0:
+			row.Status, row.Reason = RowIgnored, "incoming payment"
+		case opts.Supported != nil && !opts.Supported(tx.Currency, tx.Date):
+			row.Status, row.Reason = RowInvalid, "unsupported currency "+tx.Currency
+		default:
+			row.Category = Categorize(tx.Description, opts.Rules, DefaultRules)
+			key := dedupeKey(tx.Date, -tx.Amount, tx.Currency)
+			if seen[key] > 0 {
+				seen[key]--
+				row.Status, row.Reason = RowDuplicate, "matches an existing entry"
+			} else {
+				row.Status = RowNew
+			}
+		}
+		switch row.Status {
+		case RowNew:
+			summary.New++
+		case RowDuplicate:
+			summary.Duplicates++
+		case RowIgnored:
+			summary.Ignored++
+		case RowInvalid:
+			summary.Invalid++
+		}
+		rows = append(rows, row)
+	}
+	return rows, summary
+}
+
+// Entry converts a new row into a budget entry for userID.
+func (r Row) Entry(userID string) domain.BudgetEntry {
+	note := []rune(r.Description)
+	if len(note) > maxNoteLength {
+		note = note[:maxNoteLength]
+	}
+	return domain.BudgetEntry{
+		UserID:   userID,
+		Category: r.Category,
+		Amount:   r.Amount.Abs(),
+		Currency: r.Currency,
+		Date:     r.Date,
+		Note:     string(note),
+	}
+}
+
+func dedupeKey(date string, amount money.Amount, currency string) string {
+	return fmt.Sprintf("%s|%s|%d", date, fx.Normalize(currency), amount)
+}
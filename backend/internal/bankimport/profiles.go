@@ -0,0 +1,77 @@
+// Package bankimport turns bank statement exports (CSV and CAMT.053) into
+// budget entries: parsing, categorisation and duplicate detection.
+package bankimport
+
+import (
+	"sort"
+	"strings"
+)
+
+// Profile maps a bank's CSV export onto transactions. Column names are
+// matched case-insensitively against the header row.
+type Profile struct {
+	Name      string `json:"name"`
+	Delimiter string `json:"delimiter"`
+	// DateLayout is a Go time layout, e.g. "02.01.2006".
+	DateLayout string `json:"dateLayout"`
+	// DecimalComma marks amounts written as 1.234,56.
+	DecimalComma bool   `json:"decimalComma"`
+	DateColumn   string `json:"dateColumn"`
+	// AmountColumn holds signed amounts; money leaving the account is negative.
+	AmountColumn string `json:"amountColumn"`
+	// FeeColumn, when set, holds charges added on top of the amount.
+	FeeColumn      string `json:"feeColumn,omitempty"`
+	CurrencyColumn string `json:"currencyColumn,omitempty"`
+	// Currency is used when the export has no currency column.
+	Currency           string   `json:"currency,omitempty"`
+	DescriptionColumns []string `json:"descriptionColumns"`
+	// Rows whose StateColumn value is not one of States are ignored
+	// (e.g. pending or reverted card payments).
+	StateColumn string   `json:"stateColumn,omitempty"`
+	States      []string `json:"states,omitempty"`
+}
+
+var profiles = map[string]Profile{
+	"generic": {
+		Name: "generic", Delimiter: ",", DateLayout: "2006-01-02",
+		DateColumn: "date", AmountColumn: "amount", CurrencyColumn: "currency",
+		DescriptionColumns: []string{"description"},
+	},
+	"n26": {
+		Name: "n26", Delimiter: ",", DateLayout: "2006-01-02",
+		DateColumn: "Date", AmountColumn: "Amount (EUR)", Currency: "EUR",
+		DescriptionColumns: []string{"Payee", "Payment reference"},
+	},
+	"revolut": {
+		Name: "revolut", Delimiter: ",", DateLayout: "2006-01-02 15:04:05",
+		DateColumn: "Started Date", AmountColumn: "Amount", FeeColumn: "Fee", CurrencyColumn: "Currency",
+		DescriptionColumns: []string{"Description"},
+		StateColumn:        "State", States: []string{"COMPLETED"},
+	},
+	"ing-de": {
+		Name: "ing-de", Delimiter: ";", DateLayout: "02.01.2006", DecimalComma: true,
+		DateColumn: "Buchung", AmountColumn: "Betrag", CurrencyColumn: "Währung",
+		DescriptionColumns: []string{"Auftraggeber/Empfänger", "Verwendungszweck"},
+	},
+	"bunq": {
+		Name: "bunq", Delimiter: ";", DateLayout: "2006-01-02", DecimalComma: true,
+		DateColumn: "Date", AmountColumn: "Amount", Currency: "EUR",
+		DescriptionColumns: []string{"Name", "Description"},
+	},
+}
+
+// LookupProfile returns the built-in profile with the given name.
+func LookupProfile(name string) (Profile, bool) {
+	p, ok := profiles[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+// Profiles lists the built-in profiles by name.
+func Profiles() []Profile {
+	out := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
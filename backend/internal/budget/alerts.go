@@ -0,0 +1,123 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// AlertInput is one evaluation of a user's alert rules.
+type AlertInput struct {
+	UserID string
+	Rules  []domain.AlertRule
+	// Forecast is the user's current period without any planned trip.
+	Forecast domain.ForecastResult
+	// WithTrip is the same forecast including TripID's estimate; set only
+	// when the evaluation was triggered by planning that trip.
+	WithTrip *domain.ForecastResult
+	TripID   string
+	// Entries were just added and are checked against expense-over rules.
+	Entries []domain.BudgetEntry
+	Rates   *fx.Table
+	Now     time.Time
+}
+
+// EvaluateAlerts returns an alert for every enabled rule whose condition
+// holds. Amounts are reported in the forecast's currency; each alert carries
+// a Key so repeated evaluations of the same condition can be recorded once.
+func EvaluateAlerts(in AlertInput) []domain.Alert {
+	currency := in.Forecast.Currency
+	period := in.Forecast.PeriodStart
+	var out []domain.Alert
+	for _, rule := range in.Rules {
+		if rule.Disabled {
+			continue
+		}
+		base := domain.Alert{
+			UserID:      in.UserID,
+			RuleID:      rule.ID,
+			Kind:        rule.Kind,
+			Currency:    currency,
+			PeriodStart: period,
+			TriggeredAt: in.Now.UTC().Format(time.RFC3339),
+		}
+		threshold, err := in.Rates.ConvertAmount(rule.Threshold, ruleCurrency(rule, currency), currency, "")
+		if err != nil && rule.Kind != domain.AlertCategoryOver {
+			continue
+		}
+
+		switch rule.Kind {
+		case domain.AlertCategoryOver:
+			for _, c := range in.Forecast.Categories {
+				if !strings.EqualFold(c.Category, rule.Category) || c.Allocation <= 0 {
+					continue
+				}
+				// Percentages are held to two decimals like amounts.
+				percent := money.FromFloat(Round(c.Projected.Float()/c.Allocation.Float()*100, 1))
+				if percent < rule.Threshold {
+					continue
+				}
+				a := base
+				a.Severity = domain.SeverityWarning
+				a.Category, a.Value, a.Threshold, a.Currency = c.Category, percent, rule.Threshold, ""
+				a.Message = fmt.Sprintf("%s is projected at %.0f%% of its allocation (%s of %s %s)",
+					c.Category, percent.Float(), c.Projected, c.Allocation, currency)
+				a.Key = rule.ID + "/" + period
+				out = append(out, a)
+			}
+		case domain.AlertRemainingUnder:
+			remaining := in.Forecast.RemainingBudget
+			if remaining >= threshold {
+				continue
+			}
+			a := base
+			a.Severity = domain.SeverityWarning
+			if remaining < 0 {
+				a.Severity = domain.SeverityHighRisk
+			}
+			a.Value, a.Threshold = remaining, threshold
+			a.Message = fmt.Sprintf("projected remaining budget %s %s is below %s %s", remaining, currency, threshold, currency)
+			a.Key = rule.ID + "/" + period
+			out = append(out, a)
+		case domain.AlertExpenseOver:
+			for _, entry := range in.Entries {
+				converted, err := in.Rates.ConvertMoney(entry.Money(), currency, entry.Date)
+				amount := converted.Amount
+				if err != nil || amount <= threshold {
+					continue
+				}
+				a := base
+				a.Severity = domain.SeverityInfo
+				a.Category, a.EntryID = entry.Category, entry.ID
+				a.Value, a.Threshold = amount, threshold
+				a.Message = fmt.Sprintf("%s expense of %s %s on %s is over %s %s",
+					entry.Category, a.Value, currency, entry.Date, threshold, currency)
+				a.Key = rule.ID + "/entry/" + entry.ID
+				out = append(out, a)
+			}
+		case domain.AlertTripIntoRed:
+			if in.WithTrip == nil || in.Forecast.RemainingBudget < 0 || in.WithTrip.RemainingBudget >= 0 {
+				continue
+			}
+			a := base
+			a.Severity = domain.SeverityHighRisk
+			a.TripID, a.Value = in.TripID, in.WithTrip.RemainingBudget
+			a.Message = fmt.Sprintf("trip %s would leave the period %s %s over budget",
+				in.TripID, -in.WithTrip.RemainingBudget, currency)
+			a.Key = rule.ID + "/" + period + "/trip/" + in.TripID
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func ruleCurrency(rule domain.AlertRule, fallback string) string {
+	if rule.Currency == "" {
+		return fallback
+	}
+	return rule.Currency
+}
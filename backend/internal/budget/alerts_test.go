@@ -0,0 +1,99 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func alertInput(rules ...domain.AlertRule) AlertInput {
+	return AlertInput{
+		UserID: "u1",
+		Rules:  rules,
+		Forecast: domain.ForecastResult{
+			Currency:        "EUR",
+			PeriodStart:     "2026-04-01",
+			RemainingBudget: money.FromFloat(150),
+			Categories: []domain.CategorySpend{
+				{Category: "food", Spent: money.FromFloat(200), Projected: money.FromFloat(270), Allocation: money.FromFloat(300)},
+				{Category: "fun", Spent: money.FromFloat(50), Projected: money.FromFloat(80)},
+			},
+		},
+		Rates: fx.NewTable(nil),
+		Now:   time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEvaluateAlerts_CategoryAndRemaining(t *testing.T) {
+	alerts := EvaluateAlerts(alertInput(
+		domain.AlertRule{ID: "r1", Kind: domain.AlertCategoryOver, Category: "Food", Threshold: money.FromFloat(90)},
+		domain.AlertRule{ID: "r2", Kind: domain.AlertCategoryOver, Category: "fun", Threshold: money.FromFloat(10)},
+		domain.AlertRule{ID: "r3", Kind: domain.AlertRemainingUnder, Threshold: money.FromFloat(200), Currency: "EUR"},
+		domain.AlertRule{ID: "r4", Kind: domain.AlertRemainingUnder, Threshold: money.FromFloat(100), Currency: "EUR"},
+		domain.AlertRule{ID: "r5", Kind: domain.AlertRemainingUnder, Threshold: money.FromFloat(500), Disabled: true},
+	))
+	// fun has no allocation, r4 is not crossed and r5 is disabled.
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %+v", alerts)
+	}
+	food, remaining := alerts[0], alerts[1]
+	if food.RuleID != "r1" || food.Value != money.FromFloat(90) || food.Category != "food" || food.Key != "r1/2026-04-01" {
+		t.Fatalf("unexpected category alert %+v", food)
+	}
+	if remaining.RuleID != "r3" || remaining.Value != money.FromFloat(150) || remaining.Severity != domain.SeverityWarning {
+		t.Fatalf("unexpected remaining alert %+v", remaining)
+	}
+	if remaining.TriggeredAt != "2026-04-10T12:00:00Z" || remaining.UserID != "u1" {
+		t.Fatalf("unexpected stamp %+v", remaining)
+	}
+}
+
+func TestEvaluateAlerts_ExpenseOverConvertsCurrency(t *testing.T) {
+	in := alertInput(domain.AlertRule{ID: "r1", Kind: domain.AlertExpenseOver, Threshold: money.FromFloat(100), Currency: "EUR"})
+	in.Rates = fx.NewTable([]domain.FXRate{{Date: "2026-01-02", Currency: "PLN", Rate: 4}})
+	in.Entries = []domain.BudgetEntry{
+		{ID: "e1", Category: "rent", Amount: money.FromFloat(800), Currency: "PLN", Date: "2026-04-10"},
+		{ID: "e2", Category: "food", Amount: money.FromFloat(300), Currency: "PLN", Date: "2026-04-10"},
+	}
+	alerts := EvaluateAlerts(in)
+	if len(alerts) != 1 || alerts[0].EntryID != "e1" || alerts[0].Value != money.FromFloat(200) || alerts[0].Key != "r1/entry/e1" {
+		t.Fatalf("expected one alert for the 200 EUR rent, got %+v", alerts)
+	}
+}
+
+func TestEvaluateAlerts_TripIntoRed(t *testing.T) {
+	rule := domain.AlertRule{ID: "r1", Kind: domain.AlertTripIntoRed}
+	in := alertInput(rule)
+	if alerts := EvaluateAlerts(in); len(alerts) != 0 {
+		t.Fatalf("expected no alert without a planned trip, got %+v", alerts)
+	}
+
+	withTrip := in.Forecast
+	withTrip.RemainingBudget = money.FromFloat(-60)
+	in.WithTrip, in.TripID = &withTrip, "trip-1"
+	alerts := EvaluateAlerts(in)
+	if len(alerts) != 1 || alerts[0].Severity != domain.SeverityHighRisk || alerts[0].TripID != "trip-1" {
+		t.Fatalf("expected high-risk trip alert, got %+v", alerts)
+	}
+
+	// Already red before the trip: the trip did not push it there.
+	in.Forecast.RemainingBudget = money.FromFloat(-10)
+	if alerts := EvaluateAlerts(in); len(alerts) != 0 {
+		t.Fatalf("expected no alert when already red, got %+v", alerts)
+	}
+}
+
+func TestForecast_AmberThresholdSetting(t *testing.T) {
+	in := baseInput()
+	// 1000 budget - 600 projected leaves 400: green at the default threshold.
+	if f := Forecast(in); f.Affordability != "green" {
+		t.Fatalf("expected green, got %s", f.Affordability)
+	}
+	in.Settings.AmberThreshold = money.FromFloat(450)
+	if f := Forecast(in); f.Affordability != "amber" {
+		t.Fatalf("expected amber with a 450 threshold, got %s", f.Affordability)
+	}
+}
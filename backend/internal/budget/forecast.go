@@ -0,0 +1,297 @@
+// Package budget holds the forecasting and reconciliation logic shared by the
+// in-memory and PostgreSQL stores. Stores gather the inputs; this package
+// does the maths.
+package budget
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	DefaultMonthlyBudget  = money.Amount(900 * money.Scale) // EUR
+	DefaultStartDay       = 1
+	DefaultAmberThreshold = money.Amount(200 * money.Scale) // EUR
+	tripCategory          = "travel"
+	dateLayout            = "2006-01-02"
+)
+
+// DefaultSettings is what users get before saving their own budget settings.
+func DefaultSettings(userID string) domain.BudgetSettings {
+	return domain.BudgetSettings{
+		UserID:        userID,
+		MonthlyBudget: DefaultMonthlyBudget,
+		Allocations:   map[string]money.Amount{},
+		Currency:      fx.Base,
+		StartDay:      DefaultStartDay,
+	}
+}
+
+// ForecastInput is everything a forecast needs, already loaded from a store.
+type ForecastInput struct {
+	Entries  []domain.BudgetEntry
+	Settings domain.BudgetSettings
+	// Recurring are known fixed costs expected later in the period.
+	Recurring []domain.BudgetEntry
+	// Templates are recurring entries whose occurrences not yet generated
+	// are projected as fixed costs.
+	Templates []domain.RecurringEntry
+	// TripID/TripCost describe the selected trip (TripCost in EUR, zero when
+	// none). TripDate is when the trip starts, if known.
+	TripID   string
+	TripCost money.Amount
+	TripDate string
+	// Goals are the user's savings goals, checked against the projected
+	// remaining budget.
+	Goals []domain.SavingsGoal
+	// AsOf is the day the forecast is made (YYYY-MM-DD); empty means today.
+	AsOf string
+	// Currency is the currency the result is reported in.
+	Currency string
+	Rates    *fx.Table
+}
+
+// Period returns the first and last day of the budget period containing day.
+func Period(startDay int, day time.Time) (time.Time, time.Time) {
+	if startDay < 1 || startDay > 28 {
+		startDay = DefaultStartDay
+	}
+	start := time.Date(day.Year(), day.Month(), startDay, 0, 0, 0, 0, time.UTC)
+	if day.Day() < startDay {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start, start.AddDate(0, 1, -1)
+}
+
+// Forecast projects end-of-period spend for the budget period containing
+// AsOf: actual spend so far, the daily run-rate of discretionary spend over
+// the remaining days, scheduled and recurring costs, and the selected trip.
+func Forecast(in ForecastInput) domain.ForecastResult {
+	currency := fx.Normalize(in.Currency)
+	warnings := []string{}
+
+	asOf, err := time.Parse(dateLayout, in.AsOf)
+	if err != nil {
+		asOf = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	start, end := Period(in.Settings.StartDay, asOf)
+	startKey, endKey, asOfKey := start.Format(dateLayout), end.Format(dateLayout), asOf.Format(dateLayout)
+
+	convert := func(entry domain.BudgetEntry) (money.Amount, bool) {
+		converted, err := in.Rates.ConvertMoney(entry.Money(), currency, entry.Date)
+		if err != nil {
+			source := "entry " + entry.ID
+			if entry.ID == "" {
+				source = fmt.Sprintf("recurring %s on %s", entry.RecurringID, entry.Date)
+			}
+			warnings = append(warnings, fmt.Sprintf("%s skipped: %v", source, err))
+			return 0, false
+		}
+		return converted.Amount, true
+	}
+
+	// Known spend is summed in exact cents; only the run-rate projection is fractional.
+	daily := map[string]money.Amount{}         // date -> spend (actual or known)
+	spent := map[string]money.Amount{}         // category -> actual spend to date
+	upcoming := map[string]money.Amount{}      // category -> known spend after asOf
+	discretionary := map[string]money.Amount{} // category -> run-rate basis
+	var spentToDate, spentOnTrip money.Amount
+	for _, entry := range in.Entries {
+		if in.TripID != "" && entry.TripID == in.TripID {
+			if amount, ok := convert(entry); ok {
+				spentOnTrip += amount
+			}
+		}
+		if entry.Date < startKey || entry.Date > endKey {
+			continue
+		}
+		amount, ok := convert(entry)
+		if !ok {
+			continue
+		}
+		daily[entry.Date] += amount
+		if entry.Date > asOfKey {
+			upcoming[entry.Category] += amount
+			continue
+		}
+		spentToDate += amount
+		spent[entry.Category] += amount
+		// Trip spend and generated fixed costs say nothing about daily habits.
+		if entry.TripID == "" && entry.RecurringID == "" {
+			discretionary[entry.Category] += amount
+		}
+	}
+	recurring := in.Recurring
+	for _, template := range in.Templates {
+		recurring = append(recurring, template.Pending(endKey)...)
+	}
+	for _, entry := range recurring {
+		if entry.Date <= asOfKey || entry.Date > endKey {
+			continue
+		}
+		if amount, ok := convert(entry); ok {
+			daily[entry.Date] += amount
+			upcoming[entry.Category] += amount
+		}
+	}
+
+	// Budget settings are kept in their own currency; convert at the latest rate.
+	warned := false
+	toReport := func(amount money.Amount) money.Amount {
+		converted, err := in.Rates.ConvertAmount(amount, in.Settings.Currency, currency, "")
+		if err != nil {
+			if !warned {
+				warnings = append(warnings, fmt.Sprintf("budget not converted: %v", err))
+				warned = true
+			}
+			return amount
+		}
+		return converted
+	}
+	budget := in.Settings.MonthlyBudget
+	if budget <= 0 {
+		budget = DefaultMonthlyBudget
+	}
+	budget = toReport(budget)
+
+	// The trip's estimate only counts for what hasn't been spent on it yet.
+	var tripCost money.Amount
+	if in.TripCost != 0 {
+		if converted, err := in.Rates.ConvertAmount(in.TripCost, fx.Base, currency, ""); err == nil {
+			tripCost = max(0, converted-spentOnTrip)
+		} else {
+			warnings = append(warnings, fmt.Sprintf("trip cost not converted: %v", err))
+		}
+	}
+	if tripCost > 0 {
+		tripDay := in.TripDate
+		if tripDay <= asOfKey || tripDay > endKey {
+			tripDay = asOf.AddDate(0, 0, 1).Format(dateLayout)
+			if tripDay > endKey {
+				tripDay = endKey
+			}
+		}
+		daily[tripDay] += tripCost
+		upcoming[tripCategory] += tripCost
+	}
+
+	elapsedDays := int(asOf.Sub(start).Hours()/24) + 1
+	remainingDays := int(end.Sub(asOf).Hours() / 24)
+	if remainingDays < 0 {
+		remainingDays = 0
+	}
+	projectedByCategory := map[string]money.Amount{}
+	var projected money.Amount
+	for _, set := range []map[string]money.Amount{spent, upcoming} {
+		for category, amount := range set {
+			projectedByCategory[category] += amount
+			projected += amount
+		}
+	}
+	// Each category's run-rate spend over the remaining days is rounded to
+	// the cent once, then spread over those days so the series adds up.
+	runRate := 0.0
+	var runRateSpend money.Amount
+	for category, amount := range discretionary {
+		rate := amount.Float() / float64(elapsedDays)
+		extra := money.FromFloat(rate * float64(remainingDays))
+		runRate += rate
+		runRateSpend += extra
+		projectedByCategory[category] += extra
+		projected += extra
+	}
+	perDay := runRateSpend.Split(remainingDays)
+
+	categories := make([]domain.CategorySpend, 0, len(projectedByCategory)+len(in.Settings.Allocations))
+	for category, allocation := range in.Settings.Allocations {
+		allocation = toReport(allocation)
+		categories = append(categories, domain.CategorySpend{
+			Category:   category,
+			Spent:      spent[category],
+			Projected:  projectedByCategory[category],
+			Allocation: allocation,
+			Remaining:  allocation - projectedByCategory[category],
+		})
+	}
+	for category, amount := range projectedByCategory {
+		if _, ok := in.Settings.Allocations[category]; ok {
+			continue
+		}
+		categories = append(categories, domain.CategorySpend{
+			Category:  category,
+			Spent:     spent[category],
+			Projected: amount,
+		})
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+
+	series := make([]domain.DailyBalance, 0, int(end.Sub(start).Hours()/24)+1)
+	balance, future := budget, 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dateLayout)
+		spend := daily[key]
+		projectedDay := day.After(asOf)
+		if projectedDay && future < len(perDay) {
+			spend += perDay[future]
+			future++
+		}
+		balance -= spend
+		series = append(series, domain.DailyBalance{
+			Date:      key,
+			Spend:     spend,
+			Balance:   balance,
+			Projected: projectedDay,
+		})
+	}
+
+	amber, err := in.Rates.ConvertAmount(DefaultAmberThreshold, fx.Base, currency, "")
+	if err != nil {
+		amber = DefaultAmberThreshold
+	}
+	if in.Settings.AmberThreshold > 0 {
+		amber = toReport(in.Settings.AmberThreshold)
+	}
+
+	remaining := budget - projected
+	affordability := "green"
+	if remaining < 0 {
+		affordability = "red"
+	} else if remaining < amber {
+		affordability = "amber"
+	}
+	result := domain.ForecastResult{
+		ProjectedMonthlySpend: projected,
+		RemainingBudget:       remaining,
+		Affordability:         affordability,
+		Currency:              currency,
+		MonthlyBudget:         budget,
+		PeriodStart:           startKey,
+		PeriodEnd:             endKey,
+		AsOf:                  asOfKey,
+		SpentToDate:           spentToDate,
+		DailyRunRate:          money.FromFloat(runRate),
+		TripCost:              tripCost,
+		Categories:            categories,
+		Daily:                 series,
+	}
+	if len(in.Goals) > 0 {
+		goals, needed, goalWarnings := goalProgress(in.Goals, in.Settings.StartDay, start, asOf, remaining, currency, in.Rates)
+		result.Goals, result.SavingsNeeded = goals, needed
+		warnings = append(warnings, goalWarnings...)
+	}
+	if len(warnings) > 0 {
+		result.Warnings = warnings
+	}
+	return result
+}
+
+func Round(value float64, precision int) float64 {
+	factor := math.Pow10(precision)
+	return math.Round(value*factor) / factor
+}
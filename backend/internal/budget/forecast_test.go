@@ -0,0 +1,143 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestPeriod(t *testing.T) {
+	cases := []struct {
+		startDay           int
+		on                 string
+		wantStart, wantEnd string
+	}{
+		{startDay: 1, on: "2026-02-14", wantStart: "2026-02-01", wantEnd: "2026-02-28"},
+		{startDay: 25, on: "2026-02-14", wantStart: "2026-01-25", wantEnd: "2026-02-24"},
+		{startDay: 25, on: "2026-02-25", wantStart: "2026-02-25", wantEnd: "2026-03-24"},
+		{startDay: 0, on: "2026-12-31", wantStart: "2026-12-01", wantEnd: "2026-12-31"},
+	}
+	for _, tc := range cases {
+		start, end := Period(tc.startDay, day(tc.on))
+		if start.Format("2006-01-02") != tc.wantStart || end.Format("2006-01-02") != tc.wantEnd {
+			t.Fatalf("Period(%d, %s) = %s..%s, want %s..%s", tc.startDay, tc.on,
+				start.Format("2006-01-02"), end.Format("2006-01-02"), tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func baseInput() ForecastInput {
+	return ForecastInput{
+		Settings: domain.BudgetSettings{MonthlyBudget: money.FromFloat(1000), Currency: "EUR", StartDay: 1,
+			Allocations: map[string]money.Amount{"food": money.FromFloat(300)}},
+		Entries: []domain.BudgetEntry{
+			{ID: "old", Category: "food", Amount: money.FromFloat(999), Currency: "EUR", Date: "2026-01-31"},
+			{ID: "a", Category: "food", Amount: money.FromFloat(100), Currency: "EUR", Date: "2026-04-02"},
+			{ID: "b", Category: "food", Amount: money.FromFloat(100), Currency: "EUR", Date: "2026-04-10"},
+		},
+		AsOf:     "2026-04-10",
+		Currency: "EUR",
+		Rates:    fx.NewTable(nil),
+	}
+}
+
+func TestForecast_ProjectsRunRate(t *testing.T) {
+	f := Forecast(baseInput())
+	// 200 spent over 10 days = 20/day, 20 days left = 400 more.
+	if f.SpentToDate != money.FromFloat(200) || f.DailyRunRate != money.FromFloat(20) || f.ProjectedMonthlySpend != money.FromFloat(600) {
+		t.Fatalf("unexpected projection %+v", f)
+	}
+	if f.PeriodStart != "2026-04-01" || f.PeriodEnd != "2026-04-30" {
+		t.Fatalf("unexpected period %s..%s", f.PeriodStart, f.PeriodEnd)
+	}
+	if len(f.Categories) != 1 || f.Categories[0].Projected != money.FromFloat(600) || f.Categories[0].Remaining != money.FromFloat(-300) {
+		t.Fatalf("unexpected categories %+v", f.Categories)
+	}
+}
+
+func TestForecast_DailySeries(t *testing.T) {
+	f := Forecast(baseInput())
+	if len(f.Daily) != 30 {
+		t.Fatalf("expected 30 days, got %d", len(f.Daily))
+	}
+	if d := f.Daily[1]; d.Date != "2026-04-02" || d.Spend != money.FromFloat(100) || d.Balance != money.FromFloat(900) || d.Projected {
+		t.Fatalf("unexpected day %+v", d)
+	}
+	if d := f.Daily[10]; !d.Projected || d.Spend != money.FromFloat(20) {
+		t.Fatalf("expected projected run-rate day, got %+v", d)
+	}
+	if last := f.Daily[29]; last.Balance != f.RemainingBudget {
+		t.Fatalf("series should end at remaining budget %v, got %v", f.RemainingBudget, last.Balance)
+	}
+}
+
+func TestForecast_SeriesAddsUpToTheCent(t *testing.T) {
+	in := baseInput()
+	in.Entries = []domain.BudgetEntry{
+		{ID: "a", Category: "food", Amount: money.FromFloat(10.01), Currency: "EUR", Date: "2026-04-01"},
+		{ID: "b", Category: "fun", Amount: money.FromFloat(3.33), Currency: "EUR", Date: "2026-04-02"},
+	}
+	in.AsOf = "2026-04-03"
+	f := Forecast(in)
+	var spend money.Amount
+	for _, d := range f.Daily {
+		spend += d.Spend
+	}
+	// A run-rate of 4.4466... a day leaves cents to spread over 27 days.
+	if spend != f.ProjectedMonthlySpend || f.Daily[29].Balance != f.RemainingBudget || f.RemainingBudget != f.MonthlyBudget-spend {
+		t.Fatalf("series drifted: spend %s, projected %s, balance %s, remaining %s",
+			spend, f.ProjectedMonthlySpend, f.Daily[29].Balance, f.RemainingBudget)
+	}
+}
+
+func TestForecast_RecurringAndTripCosts(t *testing.T) {
+	in := baseInput()
+	in.Recurring = []domain.BudgetEntry{
+		{ID: "rent", Category: "living", Amount: money.FromFloat(300), Currency: "EUR", Date: "2026-04-15"},
+		{ID: "past", Category: "living", Amount: money.FromFloat(300), Currency: "EUR", Date: "2026-04-05"},
+	}
+	in.TripID, in.TripCost, in.TripDate = "trip-1", money.FromFloat(150), "2026-04-20"
+	in.Entries = append(in.Entries, domain.BudgetEntry{
+		ID: "deposit", Category: "travel", Amount: money.FromFloat(50), Currency: "EUR", Date: "2026-04-08", TripID: "trip-1",
+	})
+	f := Forecast(in)
+	// 250 spent; run-rate excludes the trip deposit (20/day x 20 = 400);
+	// rent 300; remaining trip estimate 150 - 50 = 100.
+	if f.TripCost != money.FromFloat(100) || f.ProjectedMonthlySpend != money.FromFloat(1050) || f.Affordability != "red" {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+	if d := f.Daily[19]; d.Spend != money.FromFloat(120) {
+		t.Fatalf("expected trip cost on its start day, got %+v", d)
+	}
+}
+
+func TestForecast_RecurringTemplatesAndRunRate(t *testing.T) {
+	in := baseInput()
+	// A generated rent entry is a fixed cost, not part of the daily run-rate.
+	in.Entries = append(in.Entries, domain.BudgetEntry{
+		ID: "rent-apr", Category: "living", Amount: money.FromFloat(400), Currency: "EUR", Date: "2026-04-01", RecurringID: "rec-1",
+	})
+	in.Templates = []domain.RecurringEntry{
+		{ID: "rec-1", Category: "living", Amount: money.FromFloat(400), Currency: "EUR", Frequency: domain.RecurringMonthly,
+			StartDate: "2026-03-01", MaterializedThrough: "2026-04-10"},
+		{ID: "rec-2", Category: "living", Amount: money.FromFloat(10), Currency: "EUR", Frequency: domain.RecurringWeekly,
+			StartDate: "2026-04-06", MaterializedThrough: "2026-04-10",
+			Overrides: []domain.RecurringOverride{{Date: "2026-04-20", Skip: true}}},
+	}
+	f := Forecast(in)
+	// 600 from the base case + 400 rent + 13th and 27th phone top-ups (20th skipped).
+	if f.DailyRunRate != money.FromFloat(20) || f.ProjectedMonthlySpend != money.FromFloat(1020) {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+	if d := f.Daily[12]; d.Date != "2026-04-13" || d.Spend != money.FromFloat(30) {
+		t.Fatalf("expected a weekly occurrence on the 13th, got %+v", d)
+	}
+}
@@ -0,0 +1,225 @@
+package budget
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	// feedbackPrior is how many "as estimated" trips each destination starts
+	// with, so a single trip only moves its factors part of the way.
+	feedbackPrior = 1
+	minCostFactor = 0.5
+	maxCostFactor = 2.0
+)
+
+var bucketCategories = map[string]string{
+	"travel":        domain.BucketTransport,
+	"transport":     domain.BucketTransport,
+	"stay":          domain.BucketStay,
+	"accommodation": domain.BucketStay,
+	"lodging":       domain.BucketStay,
+	"hostel":        domain.BucketStay,
+	"hotel":         domain.BucketStay,
+}
+
+// BucketFor maps an entry category onto an estimate bucket; anything that is
+// not transport or accommodation counts as daily spend.
+func BucketFor(category string) string {
+	if bucket, ok := bucketCategories[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return bucket
+	}
+	return domain.BucketDaily
+}
+
+// ReconcileInput is a trip, its destination's seeded costs and every entry
+// linked to it.
+type ReconcileInput struct {
+	Trip      domain.Trip
+	StartDate string
+	EndDate   string
+	// TransportBase and HostelNight are the destination's seeded EUR costs,
+	// zero when the destination is unknown.
+	TransportBase money.Amount
+	HostelNight   money.Amount
+	Entries       []domain.BudgetEntry
+	Finalized     bool
+	Currency      string
+	Rates         *fx.Table
+}
+
+// Nights counts the nights between two YYYY-MM-DD dates, at least one.
+func Nights(start, end string) int {
+	s, err1 := time.Parse(dateLayout, start)
+	e, err2 := time.Parse(dateLayout, end)
+	if err1 != nil || err2 != nil {
+		return 1
+	}
+	return max(int(e.Sub(s).Hours()/24), 1)
+}
+
+// EstimateBreakdown splits a trip's EUR estimate into transport and stay,
+// priced the way the optimizer prices them for the whole party, with the
+// rest counted as daily spend. When seeded costs exceed the estimate they are
+// scaled down to fit, so the buckets always add up to the estimate exactly.
+func EstimateBreakdown(in ReconcileInput) map[string]money.Amount {
+	party := money.Amount(max(len(in.Trip.Members), 1))
+	var transport, stay money.Amount
+	if in.TransportBase > 0 {
+		transport = in.TransportBase + party*7*money.Scale
+	}
+	stay = in.HostelNight * money.Amount(Nights(in.StartDate, in.EndDate)) * party
+	total := max(in.Trip.EstimatedCost, 0)
+	if sum := transport + stay; sum > total && sum > 0 {
+		scale := total.Float() / sum.Float()
+		transport = transport.Mul(scale)
+		stay = min(stay.Mul(scale), total-transport)
+	}
+	return map[string]money.Amount{
+		domain.BucketTransport: transport,
+		domain.BucketStay:      stay,
+		domain.BucketDaily:     total - transport - stay,
+	}
+}
+
+// Reconcile compares the estimate with the linked entries. Figures are
+// computed in EUR and reported in Currency at the trip's start-date rate.
+func Reconcile(in ReconcileInput) domain.TripReconciliation {
+	currency := fx.Normalize(in.Currency)
+	result := domain.TripReconciliation{
+		TripID:      in.Trip.ID,
+		Destination: in.Trip.Destination,
+		Currency:    currency,
+		StartDate:   in.StartDate,
+		EndDate:     in.EndDate,
+		Nights:      Nights(in.StartDate, in.EndDate),
+		ByCategory:  []domain.CategorySpend{},
+		EntryCount:  len(in.Entries),
+		Finalized:   in.Finalized,
+	}
+	actual, byCategory := actualByBucket(in, &result.Warnings)
+	estimate := EstimateBreakdown(in)
+
+	rate, err := in.Rates.Convert(1, fx.Base, currency, in.StartDate)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("report kept in %s: %v", fx.Base, err))
+		result.Currency, rate = fx.Base, 1
+	}
+	report := func(eur money.Amount) money.Amount { return eur.Mul(rate) }
+
+	categories := map[string][]string{}
+	for category := range byCategory {
+		bucket := BucketFor(category)
+		categories[bucket] = append(categories[bucket], category)
+	}
+	for _, bucket := range []string{domain.BucketTransport, domain.BucketStay, domain.BucketDaily} {
+		sort.Strings(categories[bucket])
+		line := domain.ReconciliationLine{
+			Bucket:     bucket,
+			Estimated:  report(estimate[bucket]),
+			Actual:     report(actual[bucket]),
+			Categories: append([]string{}, categories[bucket]...),
+		}
+		line.Delta = line.Actual - line.Estimated
+		result.Lines = append(result.Lines, line)
+		result.EstimatedTotal += line.Estimated
+		result.ActualTotal += line.Actual
+	}
+	for category, eur := range byCategory {
+		result.ByCategory = append(result.ByCategory, domain.CategorySpend{Category: category, Spent: report(eur)})
+	}
+	sort.Slice(result.ByCategory, func(i, j int) bool { return result.ByCategory[i].Category < result.ByCategory[j].Category })
+
+	result.Delta = result.ActualTotal - result.EstimatedTotal
+	if result.EstimatedTotal > 0 {
+		result.DeltaPercent = Round(result.Delta.Float()/result.EstimatedTotal.Float()*100, 1)
+	}
+	return result
+}
+
+// Feedback turns a trip into actual/estimated ratios for its destination.
+// Buckets with no estimate or no recorded spend are left at zero.
+func Feedback(in ReconcileInput) domain.CostFeedback {
+	actual, _ := actualByBucket(in, new([]string))
+	estimate := EstimateBreakdown(in)
+	ratio := func(bucket string) float64 {
+		if estimate[bucket] <= 0 || actual[bucket] <= 0 {
+			return 0
+		}
+		return Round(actual[bucket].Float()/estimate[bucket].Float(), 3)
+	}
+	return domain.CostFeedback{
+		TripID:         in.Trip.ID,
+		Destination:    in.Trip.Destination,
+		TransportRatio: ratio(domain.BucketTransport),
+		StayRatio:      ratio(domain.BucketStay),
+		DailyRatio:     ratio(domain.BucketDaily),
+	}
+}
+
+// CostFactors averages feedback per destination (keyed in lower case),
+// shrunk towards 1 and clamped so one odd trip cannot swing estimates wildly.
+func CostFactors(feedback []domain.CostFeedback) map[string]domain.CostFactors {
+	type sums struct{ transport, stay, daily, nt, ns, nd, samples float64 }
+	byCity := map[string]*sums{}
+	for _, f := range feedback {
+		key := strings.ToLower(f.Destination)
+		if byCity[key] == nil {
+			byCity[key] = &sums{}
+		}
+		s := byCity[key]
+		s.samples++
+		if f.TransportRatio > 0 {
+			s.transport, s.nt = s.transport+f.TransportRatio, s.nt+1
+		}
+		if f.StayRatio > 0 {
+			s.stay, s.ns = s.stay+f.StayRatio, s.ns+1
+		}
+		if f.DailyRatio > 0 {
+			s.daily, s.nd = s.daily+f.DailyRatio, s.nd+1
+		}
+	}
+	factor := func(sum, n float64) float64 {
+		v := (sum + feedbackPrior) / (n + feedbackPrior)
+		return Round(math.Min(maxCostFactor, math.Max(minCostFactor, v)), 3)
+	}
+	out := make(map[string]domain.CostFactors, len(byCity))
+	for city, s := range byCity {
+		out[city] = domain.CostFactors{
+			Transport: factor(s.transport, s.nt),
+			Stay:      factor(s.stay, s.ns),
+			Daily:     factor(s.daily, s.nd),
+			Samples:   int(s.samples),
+		}
+	}
+	return out
+}
+
+// FactorsFor returns the factors for city, or neutral ones.
+func FactorsFor(factors map[string]domain.CostFactors, city string) domain.CostFactors {
+	if f, ok := factors[strings.ToLower(city)]; ok {
+		return f
+	}
+	return domain.CostFactors{Transport: 1, Stay: 1, Daily: 1}
+}
+
+func actualByBucket(in ReconcileInput, warnings *[]string) (map[string]money.Amount, map[string]money.Amount) {
+	buckets, categories := map[string]money.Amount{}, map[string]money.Amount{}
+	for _, entry := range in.Entries {
+		eur, err := in.Rates.ConvertMoney(entry.Money(), fx.Base, entry.Date)
+		if err != nil {
+			*warnings = append(*warnings, fmt.Sprintf("entry %s skipped: %v", entry.ID, err))
+			continue
+		}
+		buckets[BucketFor(entry.Category)] += eur.Amount
+		categories[entry.Category] += eur.Amount
+	}
+	return buckets, categories
+}
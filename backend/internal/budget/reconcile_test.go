@@ -0,0 +1,71 @@
+package budget
+
+import (
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func pragueTrip() ReconcileInput {
+	return ReconcileInput{
+		Trip:          domain.Trip{ID: "trip-1", Destination: "Prague", Members: []string{"a"}, EstimatedCost: money.FromFloat(220)},
+		StartDate:     "2026-03-06",
+		EndDate:       "2026-03-08",
+		TransportBase: money.FromFloat(55),
+		HostelNight:   money.FromFloat(28),
+		Entries: []domain.BudgetEntry{
+			{ID: "e1", Category: "travel", Amount: money.FromFloat(80), Currency: "EUR", Date: "2026-03-06"},
+			{ID: "e2", Category: "Hostel", Amount: money.FromFloat(70), Currency: "EUR", Date: "2026-03-06"},
+			{ID: "e3", Category: "food", Amount: money.FromFloat(2520), Currency: "CZK", Date: "2026-03-07"},
+		},
+		Currency: "EUR",
+		Rates:    fx.NewTable([]domain.FXRate{{Date: "2026-01-02", Currency: "CZK", Rate: 25.2}}),
+	}
+}
+
+func TestEstimateBreakdown(t *testing.T) {
+	est := EstimateBreakdown(pragueTrip())
+	// transport 55 + 7, stay 28 x 2 nights, rest of the 220 estimate is daily spend
+	if est[domain.BucketTransport] != money.FromFloat(62) || est[domain.BucketStay] != money.FromFloat(56) || est[domain.BucketDaily] != money.FromFloat(102) {
+		t.Fatalf("unexpected breakdown %v", est)
+	}
+
+	in := pragueTrip()
+	in.Trip.EstimatedCost = money.FromFloat(59)
+	est = EstimateBreakdown(in)
+	if est[domain.BucketTransport] != money.FromFloat(31) || est[domain.BucketStay] != money.FromFloat(28) || est[domain.BucketDaily] != money.FromFloat(0) {
+		t.Fatalf("expected seeded costs scaled to the estimate, got %v", est)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	r := Reconcile(pragueTrip())
+	if r.Nights != 2 || r.EntryCount != 3 || r.ActualTotal != money.FromFloat(250) || r.EstimatedTotal != money.FromFloat(220) || r.Delta != money.FromFloat(30) || r.DeltaPercent != 13.6 {
+		t.Fatalf("unexpected report %+v", r)
+	}
+	stay := r.Lines[1]
+	if stay.Bucket != domain.BucketStay || stay.Actual != money.FromFloat(70) || stay.Delta != money.FromFloat(14) || len(stay.Categories) != 1 {
+		t.Fatalf("unexpected stay line %+v", stay)
+	}
+	if daily := r.Lines[2]; daily.Actual != money.FromFloat(100) || daily.Categories[0] != "food" {
+		t.Fatalf("expected CZK food converted into daily spend, got %+v", daily)
+	}
+}
+
+func TestFeedbackAndCostFactors(t *testing.T) {
+	f := Feedback(pragueTrip())
+	if f.TransportRatio != 1.29 || f.StayRatio != 1.25 || f.DailyRatio != 0.98 {
+		t.Fatalf("unexpected feedback %+v", f)
+	}
+	factors := CostFactors([]domain.CostFeedback{f, {Destination: "prague", TransportRatio: 5}})
+	got := FactorsFor(factors, "PRAGUE")
+	// Transport: (1.29 + 5 + 1 prior) / 3 = 2.43, clamped to 2. Stay: (1.25 + 1) / 2.
+	if got.Samples != 2 || got.Transport != 2 || got.Stay != 1.125 {
+		t.Fatalf("unexpected factors %+v", got)
+	}
+	if neutral := FactorsFor(factors, "Krakow"); neutral.Transport != 1 || neutral.Samples != 0 {
+		t.Fatalf("expected neutral factors, got %+v", neutral)
+	}
+}
@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// goalProgress reports each goal for the budget period starting at start and
+// returns the total still to be set aside in it. Goals are funded from
+// headroom, the projected remaining budget, earliest target date first: a
+// goal is on track when what it still needs this period fits in what the
+// earlier goals left over.
+func goalProgress(goals []domain.SavingsGoal, startDay int, start, asOf time.Time, headroom money.Amount, currency string, rates *fx.Table) ([]domain.GoalProgress, money.Amount, []string) {
+	goals = append([]domain.SavingsGoal(nil), goals...)
+	sort.SliceStable(goals, func(i, j int) bool { return goals[i].TargetDate < goals[j].TargetDate })
+
+	startKey, asOfKey := start.Format(dateLayout), asOf.Format(dateLayout)
+	dayBefore := start.AddDate(0, 0, -1).Format(dateLayout)
+	var warnings []string
+	var needed money.Amount
+	out := make([]domain.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		convert := func(amount money.Amount) (money.Amount, error) {
+			return rates.ConvertAmount(amount, goal.Currency, currency, "")
+		}
+		target, err := convert(goal.Target)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("goal %s skipped: %v", goal.ID, err))
+			continue
+		}
+		savedBefore, _ := convert(goal.Saved(dayBefore))
+		saved, _ := convert(goal.Saved(asOfKey))
+		p := domain.GoalProgress{
+			GoalID:          goal.ID,
+			TripID:          goal.TripID,
+			Name:            goal.Name,
+			TargetDate:      goal.TargetDate,
+			Target:          target,
+			Saved:           saved,
+			Remaining:       max(0, target-saved),
+			SavedThisPeriod: saved - savedBefore,
+		}
+		switch {
+		case saved >= target:
+			p.Status = domain.GoalReached
+		case goal.TargetDate < startKey:
+			p.Status = domain.GoalMissed
+			p.MonthlyNeeded = p.Remaining
+		default:
+			p.PeriodsLeft = periodsUntil(startDay, start, goal.TargetDate)
+			monthly := max(0, target-savedBefore).Split(p.PeriodsLeft)[0]
+			stillNeeded := max(0, monthly-(saved-savedBefore))
+			p.MonthlyNeeded = monthly
+			p.Status = domain.GoalBehind
+			if stillNeeded <= headroom {
+				p.Status = domain.GoalOnTrack
+			}
+			headroom -= stillNeeded
+			needed += stillNeeded
+		}
+		out = append(out, p)
+	}
+	return out, needed, warnings
+}
+
+// periodsUntil counts the budget periods from the one starting at start up
+// to and including the one containing date.
+func periodsUntil(startDay int, start time.Time, date string) int {
+	n := 1
+	for {
+		_, end := Period(startDay, start)
+		if end.Format(dateLayout) >= date {
+			return n
+		}
+		start = end.AddDate(0, 0, 1)
+		n++
+	}
+}
@@ -0,0 +1,72 @@
+package budget
+
+import (
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func TestForecast_SavingsGoals(t *testing.T) {
+	in := baseInput() // 400 left at the end of April
+	in.Goals = []domain.SavingsGoal{
+		{ID: "easter", Name: "Lisbon", Target: money.FromFloat(400), Currency: "EUR", TargetDate: "2026-04-30"},
+		{ID: "summer", Name: "Porto", Target: money.FromFloat(600), Currency: "EUR", TargetDate: "2026-06-20",
+			Contributions: []domain.SavingsContribution{
+				{Amount: money.FromFloat(100), Date: "2026-03-15"},
+				{Amount: money.FromFloat(50), Date: "2026-04-05"},
+				{Amount: money.FromFloat(500), Date: "2026-05-01"},
+			}},
+		{ID: "done", Target: money.FromFloat(50), Currency: "EUR", TargetDate: "2026-05-01",
+			Contributions: []domain.SavingsContribution{{Amount: money.FromFloat(50), Date: "2026-04-01"}}},
+		{ID: "late", Target: money.FromFloat(80), Currency: "EUR", TargetDate: "2026-03-01"},
+	}
+	f := Forecast(in)
+	if len(f.Goals) != 4 {
+		t.Fatalf("expected 4 goals, got %+v", f.Goals)
+	}
+	byID := map[string]domain.GoalProgress{}
+	for _, g := range f.Goals {
+		byID[g.GoalID] = g
+	}
+
+	// Easter comes first and takes the whole 400 this period, which fits.
+	if g := byID["easter"]; g.Status != domain.GoalOnTrack || g.PeriodsLeft != 1 || g.MonthlyNeeded != money.FromFloat(400) {
+		t.Fatalf("unexpected easter progress %+v", g)
+	}
+	// Summer still missed 500 on April 1st over April, May and June; 50 of
+	// the 166.67 was saved, and nothing is left of the budget for the rest.
+	// The May contribution is in the future and does not count yet.
+	if g := byID["summer"]; g.Status != domain.GoalBehind || g.PeriodsLeft != 3 || g.MonthlyNeeded != money.FromFloat(166.67) ||
+		g.Saved != money.FromFloat(150) || g.SavedThisPeriod != money.FromFloat(50) || g.Remaining != money.FromFloat(450) {
+		t.Fatalf("unexpected summer progress %+v", g)
+	}
+	if g := byID["done"]; g.Status != domain.GoalReached || g.MonthlyNeeded != 0 {
+		t.Fatalf("unexpected reached progress %+v", g)
+	}
+	if g := byID["late"]; g.Status != domain.GoalMissed || g.MonthlyNeeded != money.FromFloat(80) {
+		t.Fatalf("unexpected missed progress %+v", g)
+	}
+	if f.SavingsNeeded != money.FromFloat(516.67) {
+		t.Fatalf("expected 516.67 still to set aside, got %v", f.SavingsNeeded)
+	}
+	// Saving is not spending: the projection itself is unchanged.
+	if f.RemainingBudget != money.FromFloat(400) {
+		t.Fatalf("expected remaining budget 400, got %v", f.RemainingBudget)
+	}
+}
+
+func TestForecast_SavingsGoalInOtherCurrency(t *testing.T) {
+	in := baseInput()
+	in.Rates = fx.NewTable([]domain.FXRate{{Date: "2026-01-02", Currency: "PLN", Rate: 4}})
+	in.Goals = []domain.SavingsGoal{{ID: "g", Target: money.FromFloat(800), Currency: "PLN", TargetDate: "2026-05-31"}}
+	f := Forecast(in)
+	if len(f.Goals) != 1 || f.Goals[0].Target != money.FromFloat(200) || f.Goals[0].MonthlyNeeded != money.FromFloat(100) || f.Goals[0].Status != domain.GoalOnTrack {
+		t.Fatalf("expected a 200 EUR goal needing 100 a month, got %+v", f.Goals)
+	}
+	in.Goals[0].Currency = "XXX"
+	if f := Forecast(in); len(f.Goals) != 0 || len(f.Warnings) != 1 {
+		t.Fatalf("expected the goal skipped with a warning, got %+v %v", f.Goals, f.Warnings)
+	}
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *PgStore) ListAlertRules(userID string) []domain.AlertRule {
+	var models []AlertRuleModel
+	s.db.Where("user_id = ?", userID).Order("id").Find(&models)
+	result := make([]domain.AlertRule, len(models))
+	for i, m := range models {
+		result[i] = domain.AlertRule{
+			ID: m.ID, UserID: m.UserID, Kind: domain.AlertKind(m.Kind), Category: m.Category,
+			Threshold: m.Threshold, Currency: m.Currency, Disabled: m.Disabled,
+		}
+	}
+	return result
+}
+
+// SaveAlertRule creates rule when it has no ID and replaces the caller's
+// existing rule otherwise.
+func (s *PgStore) SaveAlertRule(rule domain.AlertRule) (*domain.AlertRule, error) {
+	if rule.ID != "" {
+		if err := s.ownedAlertRule(rule.UserID, rule.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		rule.ID = makeID("ar")
+	}
+	m := AlertRuleModel{
+		ID: rule.ID, UserID: rule.UserID, Kind: string(rule.Kind), Category: rule.Category,
+		Threshold: rule.Threshold, Currency: rule.Currency, Disabled: rule.Disabled,
+	}
+	if err := s.db.Save(&m).Error; err != nil {
+		return nil, fmt.Errorf("save alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// DeleteAlertRule removes a rule. Alerts it already triggered are kept.
+func (s *PgStore) DeleteAlertRule(userID, id string) error {
+	if err := s.ownedAlertRule(userID, id); err != nil {
+		return err
+	}
+	if err := s.db.Delete(&AlertRuleModel{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete alert rule: %w", err)
+	}
+	return nil
+}
+
+// RecordAlerts stores the alerts whose key has not been recorded for their
+// user yet and returns those; the unique (user_id, alert_key) index settles
+// concurrent evaluations.
+func (s *PgStore) RecordAlerts(alerts []domain.Alert) []domain.Alert {
+	recorded := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		alert.ID = makeID("al")
+		alert.Read = false
+		m := alertToModel(alert)
+		res := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&m)
+		if res.Error == nil && res.RowsAffected > 0 {
+			recorded = append(recorded, alert)
+		}
+	}
+	return recorded
+}
+
+// ListAlerts returns the user's alerts, newest first.
+func (s *PgStore) ListAlerts(userID string, unreadOnly bool) []domain.Alert {
+	q := s.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read = ?", false)
+	}
+	var models []AlertModel
+	q.Order("triggered_at DESC, id DESC").Find(&models)
+	result := make([]domain.Alert, len(models))
+	for i, m := range models {
+		result[i] = alertFromModel(m)
+	}
+	return result
+}
+
+// MarkAlertsRead marks the given alerts, or all of the user's alerts when ids
+// is empty, as read and returns how many changed.
+func (s *PgStore) MarkAlertsRead(userID string, ids []string) int {
+	q := s.db.Model(&AlertModel{}).Where("user_id = ? AND read = ?", userID, false)
+	if len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+	}
+	res := q.Update("read", true)
+	return int(res.RowsAffected)
+}
+
+func (s *PgStore) ownedAlertRule(userID, id string) error {
+	var m AlertRuleModel
+	if err := s.db.First(&m, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("%w: alert rule %s", domain.ErrNotFound, id)
+	}
+	if m.UserID != userID {
+		return fmt.Errorf("%w: alert rule %s belongs to another user", domain.ErrForbidden, id)
+	}
+	return nil
+}
+
+func alertToModel(a domain.Alert) AlertModel {
+	return AlertModel{
+		ID: a.ID, UserID: a.UserID, RuleID: a.RuleID, Kind: string(a.Kind),
+		Severity: string(a.Severity), Message: a.Message,
+		Value: a.Value, Threshold: a.Threshold, Currency: a.Currency,
+		Category: a.Category, EntryID: a.EntryID, TripID: a.TripID,
+		PeriodStart: a.PeriodStart, Key: a.Key, Read: a.Read, TriggeredAt: a.TriggeredAt,
+	}
+}
+
+func alertFromModel(m AlertModel) domain.Alert {
+	return domain.Alert{
+		ID: m.ID, UserID: m.UserID, RuleID: m.RuleID, Kind: domain.AlertKind(m.Kind),
+		Severity: domain.Severity(m.Severity), Message: m.Message,
+		Value: m.Value, Threshold: m.Threshold, Currency: m.Currency,
+		Category: m.Category, EntryID: m.EntryID, TripID: m.TripID,
+		PeriodStart: m.PeriodStart, Key: m.Key, Read: m.Read, TriggeredAt: m.TriggeredAt,
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
 )
 
 // JSONStringSlice is a custom type for JSONB text arrays.
@@ -34,10 +38,116 @@ func (j JSONStringSlice) Value() (driver.Value, error) {
 	return string(b), err
 }
 
+// JSONPollOptionSlice stores poll options as a JSONB array.
+type JSONPollOptionSlice []domain.PollOption
+
+func (j *JSONPollOptionSlice) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONPollOptionSlice) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// JSONRecurringOverrideSlice stores per-occurrence overrides as a JSONB array.
+type JSONRecurringOverrideSlice []domain.RecurringOverride
+
+func (j *JSONRecurringOverrideSlice) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONRecurringOverrideSlice) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// JSONCategoryRuleSlice stores keyword categorisation rules as a JSONB array.
+type JSONCategoryRuleSlice []domain.CategoryRule
+
+func (j *JSONCategoryRuleSlice) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONCategoryRuleSlice) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// JSONAmountMap stores a string-to-amount map as a JSONB object of numbers.
+type JSONAmountMap map[string]money.Amount
+
+func (j *JSONAmountMap) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONAmountMap) Value() (driver.Value, error) {
+	if j == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// JSONTransportOptionSlice stores cached transport options as a JSONB array.
+type JSONTransportOptionSlice []domain.TransportOption
+
+func (j *JSONTransportOptionSlice) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONTransportOptionSlice) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+// JSONTravelPassSlice stores a profile's passes as a JSONB array.
+type JSONTravelPassSlice []domain.TravelPass
+
+func (j *JSONTravelPassSlice) Scan(value interface{}) error {
+	return scanJSON(value, j)
+}
+
+func (j JSONTravelPassSlice) Value() (driver.Value, error) {
+	if j == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(j)
+	return string(b), err
+}
+
+func scanJSON(value interface{}, dest interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return json.Unmarshal(bytes, dest)
+}
+
 // --- GORM Models ---
 
 type AcademicEventModel struct {
 	ID        string `gorm:"column:id;primaryKey"`
+	UserID    string `gorm:"column:user_id"`
 	Type      string `gorm:"column:type"`
 	Title     string `gorm:"column:title"`
 	StartDate string `gorm:"column:start_date"`
@@ -64,37 +174,187 @@ type TripModel struct {
 	WindowID      string          `gorm:"column:window_id"`
 	Members       JSONStringSlice `gorm:"column:members;type:jsonb"`
 	Itinerary     JSONStringSlice `gorm:"column:itinerary;type:jsonb"`
-	EstimatedCost float64         `gorm:"column:estimated_cost"`
+	EstimatedCost money.Amount    `gorm:"column:estimated_cost"`
 }
 
 func (TripModel) TableName() string { return "trips" }
 
 type BudgetEntryModel struct {
-	ID       string  `gorm:"column:id;primaryKey"`
-	UserID   string  `gorm:"column:user_id"`
-	Category string  `gorm:"column:category"`
-	Amount   float64 `gorm:"column:amount"`
-	Currency string  `gorm:"column:currency"`
-	Date     string  `gorm:"column:date"`
-	TripID   string  `gorm:"column:trip_id"`
-	Note     string  `gorm:"column:note"`
+	ID       string       `gorm:"column:id;primaryKey"`
+	UserID   string       `gorm:"column:user_id"`
+	Category string       `gorm:"column:category"`
+	Amount   money.Amount `gorm:"column:amount"`
+	Currency string       `gorm:"column:currency"`
+	Date     string       `gorm:"column:date"`
+	TripID   string       `gorm:"column:trip_id"`
+	Note     string       `gorm:"column:note"`
+	// RecurringID is set on entries generated from a recurring template.
+	RecurringID string `gorm:"column:recurring_id"`
 }
 
 func (BudgetEntryModel) TableName() string { return "budget_entries" }
 
+type RecurringEntryModel struct {
+	ID                  string                     `gorm:"column:id;primaryKey"`
+	UserID              string                     `gorm:"column:user_id"`
+	Category            string                     `gorm:"column:category"`
+	Amount              money.Amount               `gorm:"column:amount"`
+	Currency            string                     `gorm:"column:currency"`
+	Note                string                     `gorm:"column:note"`
+	Frequency           string                     `gorm:"column:frequency"`
+	Interval            int                        `gorm:"column:interval_count"`
+	StartDate           string                     `gorm:"column:start_date"`
+	EndDate             string                     `gorm:"column:end_date"`
+	Overrides           JSONRecurringOverrideSlice `gorm:"column:overrides;type:jsonb"`
+	MaterializedThrough string                     `gorm:"column:materialized_through"`
+}
+
+func (RecurringEntryModel) TableName() string { return "recurring_budget_entries" }
+
+type SavingsGoalModel struct {
+	ID         string       `gorm:"column:id;primaryKey"`
+	UserID     string       `gorm:"column:user_id"`
+	TripID     string       `gorm:"column:trip_id"`
+	Name       string       `gorm:"column:name"`
+	Target     money.Amount `gorm:"column:target"`
+	Currency   string       `gorm:"column:currency"`
+	TargetDate string       `gorm:"column:target_date"`
+}
+
+func (SavingsGoalModel) TableName() string { return "savings_goals" }
+
+type SavingsContributionModel struct {
+	ID     string       `gorm:"column:id;primaryKey"`
+	GoalID string       `gorm:"column:goal_id"`
+	Amount money.Amount `gorm:"column:amount"`
+	Date   string       `gorm:"column:date"`
+	Note   string       `gorm:"column:note"`
+}
+
+func (SavingsContributionModel) TableName() string { return "savings_contributions" }
+
 type MonthlyBudgetModel struct {
-	UserID string  `gorm:"column:user_id;primaryKey"`
-	Budget float64 `gorm:"column:budget"`
+	UserID      string        `gorm:"column:user_id;primaryKey"`
+	Budget      money.Amount  `gorm:"column:budget"`
+	Allocations JSONAmountMap `gorm:"column:allocations;type:jsonb"`
+	Currency    string        `gorm:"column:currency"`
+	StartDay    int           `gorm:"column:start_day"`
+	// AmberThreshold of zero means the forecast default.
+	AmberThreshold money.Amount `gorm:"column:amber_threshold"`
 }
 
 func (MonthlyBudgetModel) TableName() string { return "monthly_budgets" }
 
+type CategoryRulesModel struct {
+	UserID string                `gorm:"column:user_id;primaryKey"`
+	Rules  JSONCategoryRuleSlice `gorm:"column:rules;type:jsonb"`
+}
+
+func (CategoryRulesModel) TableName() string { return "budget_category_rules" }
+
+type AlertRuleModel struct {
+	ID        string       `gorm:"column:id;primaryKey"`
+	UserID    string       `gorm:"column:user_id"`
+	Kind      string       `gorm:"column:kind"`
+	Category  string       `gorm:"column:category"`
+	Threshold money.Amount `gorm:"column:threshold"`
+	Currency  string       `gorm:"column:currency"`
+	Disabled  bool         `gorm:"column:disabled"`
+}
+
+func (AlertRuleModel) TableName() string { return "budget_alert_rules" }
+
+type AlertModel struct {
+	ID          string       `gorm:"column:id;primaryKey"`
+	UserID      string       `gorm:"column:user_id"`
+	RuleID      string       `gorm:"column:rule_id"`
+	Kind        string       `gorm:"column:kind"`
+	Severity    string       `gorm:"column:severity"`
+	Message     string       `gorm:"column:message"`
+	Value       money.Amount `gorm:"column:value"`
+	Threshold   money.Amount `gorm:"column:threshold"`
+	Currency    string       `gorm:"column:currency"`
+	Category    string       `gorm:"column:category"`
+	EntryID     string       `gorm:"column:entry_id"`
+	TripID      string       `gorm:"column:trip_id"`
+	PeriodStart string       `gorm:"column:period_start"`
+	Key         string       `gorm:"column:alert_key"`
+	Read        bool         `gorm:"column:read"`
+	TriggeredAt string       `gorm:"column:triggered_at"`
+}
+
+func (AlertModel) TableName() string { return "budget_alerts" }
+
+type TripCostFeedbackModel struct {
+	TripID         string  `gorm:"column:trip_id;primaryKey"`
+	Destination    string  `gorm:"column:destination"`
+	TransportRatio float64 `gorm:"column:transport_ratio"`
+	StayRatio      float64 `gorm:"column:stay_ratio"`
+	DailyRatio     float64 `gorm:"column:daily_ratio"`
+}
+
+func (TripCostFeedbackModel) TableName() string { return "trip_cost_feedback" }
+
 type DestinationModel struct {
 	City           string          `gorm:"column:city;primaryKey"`
 	BaseTravelHrs  float64         `gorm:"column:base_travel_hrs"`
-	TransportBase  float64         `gorm:"column:transport_base"`
-	HostelNightEUR float64         `gorm:"column:hostel_night_eur"`
+	TransportBase  money.Amount    `gorm:"column:transport_base"`
+	HostelNightEUR money.Amount    `gorm:"column:hostel_night_eur"`
 	Tags           JSONStringSlice `gorm:"column:tags;type:jsonb"`
 }
 
 func (DestinationModel) TableName() string { return "destinations" }
+
+type UserProfileModel struct {
+	UserID         string              `gorm:"column:user_id;primaryKey"`
+	DisplayName    string              `gorm:"column:display_name"`
+	HomeCity       string              `gorm:"column:home_city"`
+	Style          string              `gorm:"column:style"`
+	MaxTravelHours float64             `gorm:"column:max_travel_hours"`
+	HomeCurrency   string              `gorm:"column:home_currency"`
+	Passes         JSONTravelPassSlice `gorm:"column:passes;type:jsonb"`
+}
+
+func (UserProfileModel) TableName() string { return "user_profiles" }
+
+type FXRateModel struct {
+	Date     string  `gorm:"column:date;primaryKey"`
+	Currency string  `gorm:"column:currency;primaryKey"`
+	Rate     float64 `gorm:"column:rate"`
+}
+
+func (FXRateModel) TableName() string { return "fx_rates" }
+
+type TripPollModel struct {
+	ID             string              `gorm:"column:id;primaryKey"`
+	TripID         string              `gorm:"column:trip_id"`
+	CreatedBy      string              `gorm:"column:created_by"`
+	Question       string              `gorm:"column:question"`
+	Kind           string              `gorm:"column:kind"`
+	Options        JSONPollOptionSlice `gorm:"column:options;type:jsonb"`
+	CloseRule      string              `gorm:"column:close_rule"`
+	ClosesAt       string              `gorm:"column:closes_at"`
+	AutoApply      bool                `gorm:"column:auto_apply"`
+	Status         string              `gorm:"column:status"`
+	WinnerOptionID string              `gorm:"column:winner_option_id"`
+	Applied        bool                `gorm:"column:applied"`
+}
+
+func (TripPollModel) TableName() string { return "trip_polls" }
+
+type TripPollVoteModel struct {
+	PollID   string `gorm:"column:poll_id;primaryKey"`
+	UserID   string `gorm:"column:user_id;primaryKey"`
+	OptionID string `gorm:"column:option_id"`
+}
+
+func (TripPollVoteModel) TableName() string { return "trip_poll_votes" }
+
+type ProviderSearchCacheModel struct {
+	Key       string                   `gorm:"column:key;primaryKey"`
+	Options   JSONTransportOptionSlice `gorm:"column:options;type:jsonb"`
+	StoredAt  time.Time                `gorm:"column:stored_at"`
+	KeepUntil time.Time                `gorm:"column:keep_until"`
+}
+
+func (ProviderSearchCacheModel) TableName() string { return "provider_search_cache" }
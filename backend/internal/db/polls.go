@@ -0,0 +1,174 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *PgStore) CreatePoll(poll domain.Poll) (*domain.Poll, error) {
+	if s.GetTrip(poll.TripID) == nil {
+		return nil, fmt.Errorf("%w: trip %s", domain.ErrNotFound, poll.TripID)
+	}
+	poll.ID = makeID("poll")
+	poll.Status = domain.PollOpen
+	poll.Votes = []domain.PollVote{}
+	m := pollToModel(poll)
+	if err := s.db.Create(&m).Error; err != nil {
+		return nil, fmt.Errorf("create poll: %w", err)
+	}
+	return &poll, nil
+}
+
+func (s *PgStore) ListPolls(tripID string) []domain.Poll {
+	var models []TripPollModel
+	s.db.Where("trip_id = ?", tripID).Order("id").Find(&models)
+	result := make([]domain.Poll, 0, len(models))
+	for _, m := range models {
+		poll, err := s.settlePoll(s.db, m, false)
+		if err != nil {
+			continue
+		}
+		result = append(result, poll)
+	}
+	return result
+}
+
+func (s *PgStore) GetPoll(tripID, pollID string) (*domain.Poll, error) {
+	var out domain.Poll
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		m, err := findPoll(tx, tripID, pollID)
+		if err != nil {
+			return err
+		}
+		out, err = s.settlePoll(tx, m, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *PgStore) VotePoll(tripID, pollID, userID, optionID string) (*domain.Poll, error) {
+	var out domain.Poll
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		m, err := findPoll(tx, tripID, pollID)
+		if err != nil {
+			return err
+		}
+		poll, err := s.settlePoll(tx, m, false)
+		if err != nil {
+			return err
+		}
+		if poll.Status != domain.PollOpen {
+			return fmt.Errorf("%w: poll is closed", domain.ErrConflict)
+		}
+		if _, ok := poll.Option(optionID); !ok {
+			return fmt.Errorf("%w: unknown option %s", domain.ErrInvalid, optionID)
+		}
+		vote := TripPollVoteModel{PollID: pollID, UserID: userID, OptionID: optionID}
+		if err := tx.Save(&vote).Error; err != nil {
+			return fmt.Errorf("save vote: %w", err)
+		}
+		out, err = s.settlePoll(tx, m, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (s *PgStore) ClosePoll(tripID, pollID string) (*domain.Poll, error) {
+	var out domain.Poll
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		m, err := findPoll(tx, tripID, pollID)
+		if err != nil {
+			return err
+		}
+		out, err = s.settlePoll(tx, m, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func findPoll(tx *gorm.DB, tripID, pollID string) (TripPollModel, error) {
+	var m TripPollModel
+	err := tx.First(&m, "id = ? AND trip_id = ?", pollID, tripID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return m, fmt.Errorf("%w: poll %s", domain.ErrNotFound, pollID)
+	}
+	return m, err
+}
+
+// settlePoll loads votes for m, closes the poll when its rule is met (or force
+// is set) and applies the winner to the trip for auto-apply polls.
+func (s *PgStore) settlePoll(tx *gorm.DB, m TripPollModel, force bool) (domain.Poll, error) {
+	var votes []TripPollVoteModel
+	if err := tx.Where("poll_id = ?", m.ID).Order("user_id").Find(&votes).Error; err != nil {
+		return domain.Poll{}, fmt.Errorf("load votes: %w", err)
+	}
+	poll := modelToPoll(m, votes)
+	if poll.Status != domain.PollOpen {
+		return poll, nil
+	}
+	var trip TripModel
+	if err := tx.First(&trip, "id = ?", poll.TripID).Error; err != nil {
+		return poll, nil
+	}
+	if !force && !poll.ShouldClose(len(trip.Members), time.Now()) {
+		return poll, nil
+	}
+	poll.Close()
+	if poll.AutoApply {
+		t := domain.Trip{Destination: trip.Destination, WindowID: trip.WindowID}
+		if poll.Applied = poll.ApplyTo(&t); poll.Applied {
+			err := tx.Model(&TripModel{}).Where("id = ?", trip.ID).
+				Updates(map[string]interface{}{"destination": t.Destination, "window_id": t.WindowID}).Error
+			if err != nil {
+				return poll, fmt.Errorf("apply poll winner: %w", err)
+			}
+		}
+	}
+	err := tx.Model(&TripPollModel{}).Where("id = ?", poll.ID).Updates(map[string]interface{}{
+		"status": string(poll.Status), "winner_option_id": poll.WinnerOptionID, "applied": poll.Applied,
+	}).Error
+	if err != nil {
+		return poll, fmt.Errorf("close poll: %w", err)
+	}
+	return poll, nil
+}
+
+func pollToModel(p domain.Poll) TripPollModel {
+	return TripPollModel{
+		ID: p.ID, TripID: p.TripID, CreatedBy: p.CreatedBy, Question: p.Question,
+		Kind: string(p.Kind), Options: JSONPollOptionSlice(p.Options),
+		CloseRule: string(p.CloseRule), ClosesAt: p.ClosesAt, AutoApply: p.AutoApply,
+		Status: string(p.Status), WinnerOptionID: p.WinnerOptionID, Applied: p.Applied,
+	}
+}
+
+func modelToPoll(m TripPollModel, votes []TripPollVoteModel) domain.Poll {
+	p := domain.Poll{
+		ID: m.ID, TripID: m.TripID, CreatedBy: m.CreatedBy, Question: m.Question,
+		Kind: domain.PollKind(m.Kind), Options: []domain.PollOption(m.Options),
+		CloseRule: domain.PollCloseRule(m.CloseRule), ClosesAt: m.ClosesAt, AutoApply: m.AutoApply,
+		Status: domain.PollStatus(m.Status), WinnerOptionID: m.WinnerOptionID, Applied: m.Applied,
+		Votes: make([]domain.PollVote, len(votes)),
+	}
+	if p.Options == nil {
+		p.Options = []domain.PollOption{}
+	}
+	for i, v := range votes {
+		p.Votes[i] = domain.PollVote{UserID: v.UserID, OptionID: v.OptionID}
+	}
+	return p
+}
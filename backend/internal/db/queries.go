@@ -5,11 +5,16 @@ import (
 	"math"
 	"math/rand"
 	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"exchange-travel-planner/backend/internal/budget"
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
 )
 
 // PgStore implements domain.DataStore backed by PostgreSQL via GORM.
@@ -33,11 +38,6 @@ func makeID(prefix string) string {
 	return fmt.Sprintf("%s-%06d", prefix, rand.Intn(999999))
 }
 
-func roundF(value float64, precision int) float64 {
-	factor := math.Pow10(precision)
-	return math.Round(value*factor) / factor
-}
-
 // --- Interface implementations ---
 
 func (s *PgStore) ImportAcademicEvents(events []domain.AcademicEvent) []domain.AcademicEvent {
@@ -46,17 +46,28 @@ func (s *PgStore) ImportAcademicEvents(events []domain.AcademicEvent) []domain.A
 			e.ID = makeID("ev")
 		}
 		m := AcademicEventModel{
-			ID: e.ID, Type: string(e.Type), Title: e.Title,
+			ID: e.ID, UserID: e.UserID, Type: string(e.Type), Title: e.Title,
 			StartDate: e.Start, EndDate: e.End, Priority: e.Priority,
 		}
 		s.db.Where("id = ?", m.ID).FirstOrCreate(&m)
 	}
 	var models []AcademicEventModel
 	s.db.Order("start_date").Find(&models)
+	return eventsFromModels(models)
+}
+
+// ListAcademicEvents returns the shared events and those imported by userID.
+func (s *PgStore) ListAcademicEvents(userID string) []domain.AcademicEvent {
+	var models []AcademicEventModel
+	s.db.Where("user_id = '' OR user_id = ?", userID).Order("start_date").Find(&models)
+	return eventsFromModels(models)
+}
+
+func eventsFromModels(models []AcademicEventModel) []domain.AcademicEvent {
 	result := make([]domain.AcademicEvent, len(models))
 	for i, m := range models {
 		result[i] = domain.AcademicEvent{
-			ID: m.ID, Type: domain.AcademicEventType(m.Type), Title: m.Title,
+			ID: m.ID, UserID: m.UserID, Type: domain.AcademicEventType(m.Type), Title: m.Title,
 			Start: m.StartDate, End: m.EndDate, Priority: m.Priority,
 		}
 	}
@@ -95,6 +106,7 @@ func (s *PgStore) loadDestinations() []DestinationModel {
 
 func (s *PgStore) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 	dests := s.loadDestinations()
+	factors := s.costFactors()
 
 	type scored struct {
 		domain.TripOption
@@ -102,6 +114,8 @@ func (s *PgStore) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 	}
 	items := make([]scored, 0, len(dests))
 	for _, entry := range dests {
+		// Finalised trips adjust seeded costs towards what people really paid.
+		adjust := budget.FactorsFor(factors, entry.City)
 		styleBoost := 0.0
 		for _, tag := range entry.Tags {
 			if tag == c.Style {
@@ -110,12 +124,12 @@ func (s *PgStore) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 			}
 		}
 		durationPenalty := math.Max(0, entry.BaseTravelHrs-c.MaxTravelHours) * 18
-		transportPrice := entry.TransportBase + float64(c.PartySize*7)
-		stayPrice := entry.HostelNightEUR * 2 * float64(c.PartySize)
-		total := math.Round(transportPrice + stayPrice)
+		transportPrice := (entry.TransportBase + money.Amount(c.PartySize*7*money.Scale)).Mul(adjust.Transport)
+		stayPrice := entry.HostelNightEUR.Mul(2 * float64(c.PartySize) * adjust.Stay)
+		total := (transportPrice + stayPrice).Round()
 		budgetPenalty := 0.0
 		if total > c.BudgetCap {
-			budgetPenalty = (total - c.BudgetCap) / 3
+			budgetPenalty = (total - c.BudgetCap).Float() / 3
 		}
 		score := 100 + styleBoost - durationPenalty - budgetPenalty
 
@@ -134,12 +148,12 @@ func (s *PgStore) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 		}
 
 		transport := []domain.TransportOption{
-			{Provider: "EuroRail Connect", Mode: "train", DurationHours: roundF(entry.BaseTravelHrs, 1), Price: math.Round(transportPrice * 0.92), Deeplink: "https://example.com/train"},
-			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: roundF(entry.BaseTravelHrs*1.3, 1), Price: math.Round(transportPrice * 0.76), Deeplink: "https://example.com/bus"},
+			{Provider: "EuroRail Connect", Mode: "train", DurationHours: budget.Round(entry.BaseTravelHrs, 1), Price: transportPrice.Mul(0.92).Round(), Deeplink: "https://example.com/train", Synthetic: true, PriceKind: domain.PriceEstimated},
+			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: budget.Round(entry.BaseTravelHrs*1.3, 1), Price: transportPrice.Mul(0.76).Round(), Deeplink: "https://example.com/bus", Synthetic: true, PriceKind: domain.PriceEstimated},
 		}
 		stays := []domain.StayOption{
-			{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR, Rating: 4.3, Deeplink: "https://example.com/hostel"},
-			{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: entry.HostelNightEUR + 16, Rating: 4.0, Deeplink: "https://example.com/hotel"},
+			{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR.Mul(adjust.Stay), Rating: 4.3, Deeplink: "https://example.com/hostel", Synthetic: true},
+			{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: (entry.HostelNightEUR + 16*money.Scale).Mul(adjust.Stay), Rating: 4.0, Deeplink: "https://example.com/hotel", Synthetic: true},
 		}
 
 		risk := domain.SeverityInfo
@@ -153,9 +167,11 @@ func (s *PgStore) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 				Destination:        entry.City,
 				ReasonTags:         reasons,
 				TotalEstimatedCost: total,
+				StayCost:           stayPrice,
 				TransportOptions:   transport,
 				StayOptions:        stays,
 				RiskLevel:          risk,
+				Currency:           fx.Base,
 			},
 			Score: score,
 		})
@@ -207,64 +223,239 @@ func (s *PgStore) ShareTrip(tripID string, memberIDs []string) *domain.Trip {
 
 func (s *PgStore) AddBudgetEntry(entry domain.BudgetEntry) domain.BudgetEntry {
 	entry.ID = makeID("b")
-	m := BudgetEntryModel{
-		ID: entry.ID, UserID: entry.UserID, Category: entry.Category,
-		Amount: entry.Amount, Currency: entry.Currency, Date: entry.Date,
-		TripID: entry.TripID, Note: entry.Note,
-	}
+	m := entryToModel(entry)
 	s.db.Create(&m)
 	return entry
 }
 
+// AddBudgetEntries inserts a batch of entries in one transaction.
+func (s *PgStore) AddBudgetEntries(entries []domain.BudgetEntry) []domain.BudgetEntry {
+	if len(entries) == 0 {
+		return []domain.BudgetEntry{}
+	}
+	out := make([]domain.BudgetEntry, len(entries))
+	models := make([]BudgetEntryModel, len(entries))
+	for i, entry := range entries {
+		entry.ID = makeID("b")
+		out[i] = entry
+		models[i] = entryToModel(entry)
+	}
+	if err := s.db.CreateInBatches(models, 200).Error; err != nil {
+		return []domain.BudgetEntry{}
+	}
+	return out
+}
+
 func (s *PgStore) ListBudgetEntries(userID string) []domain.BudgetEntry {
 	var models []BudgetEntryModel
 	s.db.Where("user_id = ?", userID).Order("date").Find(&models)
 	result := make([]domain.BudgetEntry, len(models))
 	for i, m := range models {
-		result[i] = domain.BudgetEntry{
-			ID: m.ID, UserID: m.UserID, Category: m.Category,
-			Amount: m.Amount, Currency: m.Currency, Date: m.Date,
-			TripID: m.TripID, Note: m.Note,
-		}
+		result[i] = entryFromModel(m)
 	}
 	return result
 }
 
-func (s *PgStore) Forecast(userID, tripID string) domain.ForecastResult {
-	entries := s.ListBudgetEntries(userID)
-	spend := 0.0
-	for _, e := range entries {
-		spend += e.Amount
+func (s *PgStore) QueryBudgetEntries(userID string, filter domain.BudgetEntryFilter) domain.BudgetEntryPage {
+	q := s.entryQuery(userID, filter)
+
+	page := domain.BudgetEntryPage{Limit: filter.Limit, Offset: filter.Offset, Entries: []domain.BudgetEntry{}}
+	var total int64
+	q.Count(&total)
+	page.Total = int(total)
+
+	q = q.Order("date, id")
+	if filter.Offset > 0 {
+		q = q.Offset(filter.Offset)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
 	}
+	var models []BudgetEntryModel
+	q.Find(&models)
+	for _, m := range models {
+		page.Entries = append(page.Entries, entryFromModel(m))
+	}
+	return page
+}
 
-	var mb MonthlyBudgetModel
-	budget := 900.0
-	if err := s.db.First(&mb, "user_id = ?", userID).Error; err == nil && mb.Budget > 0 {
-		budget = mb.Budget
+// StreamBudgetEntries walks matching entries in date order through a database
+// cursor, so large histories are never held in memory at once.
+func (s *PgStore) StreamBudgetEntries(userID string, filter domain.BudgetEntryFilter, fn func(domain.BudgetEntry) error) error {
+	rows, err := s.entryQuery(userID, filter).Order("date, id").Rows()
+	if err != nil {
+		return fmt.Errorf("stream entries: %w", err)
 	}
+	defer rows.Close()
+	for rows.Next() {
+		var m BudgetEntryModel
+		if err := s.db.ScanRows(rows, &m); err != nil {
+			return fmt.Errorf("scan entry: %w", err)
+		}
+		if err := fn(entryFromModel(m)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// entryQuery applies filter (pagination aside) to the user's entries.
+func (s *PgStore) entryQuery(userID string, filter domain.BudgetEntryFilter) *gorm.DB {
+	q := s.db.Model(&BudgetEntryModel{}).Where("user_id = ?", userID)
+	if filter.From != "" {
+		q = q.Where("date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		q = q.Where("date <= ?", filter.To)
+	}
+	if filter.Category != "" {
+		q = q.Where("LOWER(category) = LOWER(?)", filter.Category)
+	}
+	if filter.TripID != "" {
+		q = q.Where("trip_id = ?", filter.TripID)
+	}
+	if filter.Currency != "" {
+		q = q.Where("UPPER(currency) = UPPER(?)", filter.Currency)
+	}
+	if filter.MinAmount != nil {
+		q = q.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		q = q.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.Query != "" {
+		q = q.Where("note ILIKE ?", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	return q
+}
 
-	tripCost := 0.0
+func (s *PgStore) UpdateBudgetEntry(userID, id string, patch domain.BudgetEntryPatch) (*domain.BudgetEntry, error) {
+	m, err := s.ownedEntry(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	entry := entryFromModel(m)
+	patch.Apply(&entry)
+	m = entryToModel(entry)
+	if err := s.db.Save(&m).Error; err != nil {
+		return nil, fmt.Errorf("update entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (s *PgStore) DeleteBudgetEntry(userID, id string) error {
+	if _, err := s.ownedEntry(userID, id); err != nil {
+		return err
+	}
+	if err := s.db.Delete(&BudgetEntryModel{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete entry: %w", err)
+	}
+	return nil
+}
+
+func (s *PgStore) ownedEntry(userID, id string) (BudgetEntryModel, error) {
+	var m BudgetEntryModel
+	if err := s.db.First(&m, "id = ?", id).Error; err != nil {
+		return m, fmt.Errorf("%w: entry %s", domain.ErrNotFound, id)
+	}
+	if m.UserID != userID {
+		return m, fmt.Errorf("%w: entry %s belongs to another user", domain.ErrForbidden, id)
+	}
+	return m, nil
+}
+
+// likeEscaper escapes LIKE wildcards in user-supplied search text.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func entryFromModel(m BudgetEntryModel) domain.BudgetEntry {
+	return domain.BudgetEntry{
+		ID: m.ID, UserID: m.UserID, Category: m.Category,
+		Amount: m.Amount, Currency: m.Currency, Date: m.Date,
+		TripID: m.TripID, Note: m.Note, RecurringID: m.RecurringID,
+	}
+}
+
+func entryToModel(e domain.BudgetEntry) BudgetEntryModel {
+	return BudgetEntryModel{
+		ID: e.ID, UserID: e.UserID, Category: e.Category,
+		Amount: e.Amount, Currency: e.Currency, Date: e.Date,
+		TripID: e.TripID, Note: e.Note, RecurringID: e.RecurringID,
+	}
+}
+
+func (s *PgStore) Forecast(userID, tripID, asOf string) domain.ForecastResult {
+	in := budget.ForecastInput{
+		Entries:   s.ListBudgetEntries(userID),
+		Settings:  s.GetBudgetSettings(userID),
+		Templates: s.ListRecurringEntries(userID),
+		Goals:     s.ListSavingsGoals(userID),
+		TripID:    tripID,
+		AsOf:      asOf,
+		Currency:  s.GetProfile(userID).HomeCurrency,
+		Rates:     fx.NewTable(s.ListFXRates()),
+	}
 	if tripID != "" {
 		if t := s.GetTrip(tripID); t != nil {
-			tripCost = t.EstimatedCost
+			in.TripCost = t.EstimatedCost
+			var w TravelWindowModel
+			if err := s.db.First(&w, "id = ?", t.WindowID).Error; err == nil {
+				in.TripDate = w.StartDate
+			}
 		}
 	}
-	projected := spend + tripCost
-	remaining := budget - projected
-	affordability := "green"
-	if remaining < 0 {
-		affordability = "red"
-	} else if remaining < 200 {
-		affordability = "amber"
+	return budget.Forecast(in)
+}
+
+func (s *PgStore) GetBudgetSettings(userID string) domain.BudgetSettings {
+	var m MonthlyBudgetModel
+	if err := s.db.First(&m, "user_id = ?", userID).Error; err != nil {
+		return budget.DefaultSettings(userID)
+	}
+	allocations := map[string]money.Amount(m.Allocations)
+	if allocations == nil {
+		allocations = map[string]money.Amount{}
 	}
-	return domain.ForecastResult{
-		ProjectedMonthlySpend: roundF(projected, 2),
-		RemainingBudget:       roundF(remaining, 2),
-		Affordability:         affordability,
+	return domain.BudgetSettings{
+		UserID: m.UserID, MonthlyBudget: m.Budget, Allocations: allocations,
+		Currency: m.Currency, StartDay: m.StartDay, AmberThreshold: m.AmberThreshold,
 	}
 }
 
+func (s *PgStore) SaveBudgetSettings(settings domain.BudgetSettings) domain.BudgetSettings {
+	m := MonthlyBudgetModel{
+		UserID: settings.UserID, Budget: settings.MonthlyBudget,
+		Allocations: JSONAmountMap(settings.Allocations),
+		Currency:    settings.Currency, StartDay: settings.StartDay,
+		AmberThreshold: settings.AmberThreshold,
+	}
+	s.db.Save(&m)
+	return settings
+}
+
+func (s *PgStore) GetCategoryRules(userID string) []domain.CategoryRule {
+	var m CategoryRulesModel
+	if err := s.db.First(&m, "user_id = ?", userID).Error; err != nil || m.Rules == nil {
+		return []domain.CategoryRule{}
+	}
+	return m.Rules
+}
+
+func (s *PgStore) SaveCategoryRules(userID string, rules []domain.CategoryRule) []domain.CategoryRule {
+	m := CategoryRulesModel{UserID: userID, Rules: JSONCategoryRuleSlice(rules)}
+	s.db.Save(&m)
+	return rules
+}
+
 func (s *PgStore) EvaluateConflicts(windowID string) []domain.ConflictAlert {
+	return s.evaluateConflicts(windowID, s.db)
+}
+
+// EvaluateMemberConflicts only considers shared events and events imported by userID.
+func (s *PgStore) EvaluateMemberConflicts(userID, windowID string) []domain.ConflictAlert {
+	return s.evaluateConflicts(windowID, s.db.Where("user_id = '' OR user_id = ?", userID))
+}
+
+func (s *PgStore) evaluateConflicts(windowID string, events *gorm.DB) []domain.ConflictAlert {
 	var w TravelWindowModel
 	if err := s.db.First(&w, "id = ?", windowID).Error; err != nil {
 		return []domain.ConflictAlert{}
@@ -272,11 +463,11 @@ func (s *PgStore) EvaluateConflicts(windowID string) []domain.ConflictAlert {
 	start, _ := time.Parse("2006-01-02", w.StartDate)
 	end, _ := time.Parse("2006-01-02", w.EndDate)
 
-	var events []AcademicEventModel
-	s.db.Find(&events)
+	var models []AcademicEventModel
+	events.Find(&models)
 
 	var alerts []domain.ConflictAlert
-	for _, ev := range events {
+	for _, ev := range models {
 		eventDate, err := time.Parse("2006-01-02", ev.StartDate)
 		if err != nil || eventDate.Before(start) || eventDate.After(end) {
 			continue
@@ -305,8 +496,8 @@ func (s *PgStore) SearchTransport(_from, to string) []domain.TransportOption {
 		return []domain.TransportOption{}
 	}
 	return []domain.TransportOption{
-		{Provider: "EuroRail Connect", Mode: "train", DurationHours: dest.BaseTravelHrs, Price: math.Round(dest.TransportBase * 0.9), Deeplink: "https://example.com/train"},
-		{Provider: "SkySaver", Mode: "flight", DurationHours: roundF(dest.BaseTravelHrs*0.65, 1), Price: math.Round(dest.TransportBase * 1.18), Deeplink: "https://example.com/flight"},
+		{Provider: "EuroRail Connect", Mode: "train", DurationHours: dest.BaseTravelHrs, Price: money.FromFloat(math.Round(dest.TransportBase.Float() * 0.9)), Deeplink: "https://example.com/train", Synthetic: true, PriceKind: domain.PriceEstimated},
+		{Provider: "SkySaver", Mode: "flight", DurationHours: budget.Round(dest.BaseTravelHrs*0.65, 1), Price: money.FromFloat(math.Round(dest.TransportBase.Float() * 1.18)), Deeplink: "https://example.com/flight", Synthetic: true, PriceKind: domain.PriceEstimated},
 	}
 }
 
@@ -316,7 +507,58 @@ func (s *PgStore) SearchStays(city string) []domain.StayOption {
 		return []domain.StayOption{}
 	}
 	return []domain.StayOption{
-		{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: dest.HostelNightEUR, Rating: 4.2, Deeplink: "https://example.com/hostel"},
-		{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: dest.HostelNightEUR + 14, Rating: 4.0, Deeplink: "https://example.com/hotel"},
+		{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: dest.HostelNightEUR, Rating: 4.2, Deeplink: "https://example.com/hostel", Synthetic: true},
+		{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: dest.HostelNightEUR + 14*money.Scale, Rating: 4.0, Deeplink: "https://example.com/hotel", Synthetic: true},
 	}
 }
+
+func (s *PgStore) GetProfile(userID string) domain.UserProfile {
+	var m UserProfileModel
+	if err := s.db.First(&m, "user_id = ?", userID).Error; err != nil {
+		return domain.UserProfile{UserID: userID}
+	}
+	return domain.UserProfile{
+		UserID: m.UserID, DisplayName: m.DisplayName, HomeCity: m.HomeCity,
+		Style: m.Style, MaxTravelHours: m.MaxTravelHours, HomeCurrency: m.HomeCurrency,
+		Passes: []domain.TravelPass(m.Passes),
+	}
+}
+
+func (s *PgStore) SaveProfile(profile domain.UserProfile) domain.UserProfile {
+	m := UserProfileModel{
+		UserID: profile.UserID, DisplayName: profile.DisplayName, HomeCity: profile.HomeCity,
+		Style: profile.Style, MaxTravelHours: profile.MaxTravelHours, HomeCurrency: profile.HomeCurrency,
+		Passes: JSONTravelPassSlice(profile.Passes),
+	}
+	s.db.Save(&m)
+	return profile
+}
+
+// SaveFXRates upserts rates keyed by (date, currency).
+func (s *PgStore) SaveFXRates(rates []domain.FXRate) int {
+	if len(rates) == 0 {
+		return 0
+	}
+	models := make([]FXRateModel, len(rates))
+	for i, r := range rates {
+		models[i] = FXRateModel{Date: r.Date, Currency: r.Currency, Rate: r.Rate}
+	}
+	res := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}).CreateInBatches(models, 500)
+	if res.Error != nil {
+		return 0
+	}
+	return int(res.RowsAffected)
+}
+
+func (s *PgStore) ListFXRates() []domain.FXRate {
+	var models []FXRateModel
+	s.db.Order("date, currency").Find(&models)
+	result := make([]domain.FXRate, len(models))
+	for i, m := range models {
+		result[i] = domain.FXRate{Date: m.Date, Currency: m.Currency, Rate: m.Rate}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"exchange-travel-planner/backend/internal/budget"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+)
+
+func (s *PgStore) ReconcileTrip(tripID, currency string) (*domain.TripReconciliation, error) {
+	in, err := s.reconcileInput(tripID, currency)
+	if err != nil {
+		return nil, err
+	}
+	report := budget.Reconcile(in)
+	return &report, nil
+}
+
+// FinalizeTripReconciliation records the trip's cost feedback for its
+// destination, replacing any earlier feedback for the same trip.
+func (s *PgStore) FinalizeTripReconciliation(tripID, currency string) (*domain.TripReconciliation, error) {
+	in, err := s.reconcileInput(tripID, currency)
+	if err != nil {
+		return nil, err
+	}
+	f := budget.Feedback(in)
+	m := TripCostFeedbackModel{
+		TripID: f.TripID, Destination: f.Destination,
+		TransportRatio: f.TransportRatio, StayRatio: f.StayRatio, DailyRatio: f.DailyRatio,
+	}
+	if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&m).Error; err != nil {
+		return nil, fmt.Errorf("save cost feedback: %w", err)
+	}
+	in.Finalized = true
+	report := budget.Reconcile(in)
+	return &report, nil
+}
+
+func (s *PgStore) reconcileInput(tripID, currency string) (budget.ReconcileInput, error) {
+	trip := s.GetTrip(tripID)
+	if trip == nil {
+		return budget.ReconcileInput{}, fmt.Errorf("%w: trip %s", domain.ErrNotFound, tripID)
+	}
+	in := budget.ReconcileInput{
+		Trip:     *trip,
+		Entries:  []domain.BudgetEntry{},
+		Currency: currency,
+		Rates:    fx.NewTable(s.ListFXRates()),
+	}
+	var feedback int64
+	s.db.Model(&TripCostFeedbackModel{}).Where("trip_id = ?", tripID).Count(&feedback)
+	in.Finalized = feedback > 0
+
+	var w TravelWindowModel
+	if err := s.db.First(&w, "id = ?", trip.WindowID).Error; err == nil {
+		in.StartDate, in.EndDate = w.StartDate, w.EndDate
+	}
+	var dest DestinationModel
+	if err := s.db.First(&dest, "LOWER(city) = LOWER(?)", trip.Destination).Error; err == nil {
+		in.TransportBase, in.HostelNight = dest.TransportBase, dest.HostelNightEUR
+	}
+	var models []BudgetEntryModel
+	s.db.Where("trip_id = ?", tripID).Order("date, id").Find(&models)
+	for _, m := range models {
+		in.Entries = append(in.Entries, entryFromModel(m))
+	}
+	return in, nil
+}
+
+func (s *PgStore) costFactors() map[string]domain.CostFactors {
+	var models []TripCostFeedbackModel
+	s.db.Find(&models)
+	feedback := make([]domain.CostFeedback, len(models))
+	for i, m := range models {
+		feedback[i] = domain.CostFeedback{
+			TripID: m.TripID, Destination: m.Destination,
+			TransportRatio: m.TransportRatio, StayRatio: m.StayRatio, DailyRatio: m.DailyRatio,
+		}
+	}
+	return budget.CostFactors(feedback)
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *PgStore) CreateRecurringEntry(entry domain.RecurringEntry) domain.RecurringEntry {
+	entry.ID = makeID("rec")
+	entry.Overrides = []domain.RecurringOverride{}
+	entry.MaterializedThrough = ""
+	m := recurringToModel(entry)
+	s.db.Create(&m)
+	return entry
+}
+
+func (s *PgStore) ListRecurringEntries(userID string) []domain.RecurringEntry {
+	var models []RecurringEntryModel
+	s.db.Where("user_id = ?", userID).Order("start_date, id").Find(&models)
+	result := make([]domain.RecurringEntry, len(models))
+	for i, m := range models {
+		result[i] = recurringFromModel(m)
+	}
+	return result
+}
+
+// DeleteRecurringEntry stops a series. Entries already generated are kept.
+func (s *PgStore) DeleteRecurringEntry(userID, id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := ownedRecurring(tx, userID, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&RecurringEntryModel{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete recurring entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// OverrideRecurringOccurrence records o and, when that occurrence has already
+// been generated, brings its budget entry in line.
+func (s *PgStore) OverrideRecurringOccurrence(userID, id string, o domain.RecurringOverride) (*domain.RecurringEntry, error) {
+	var out domain.RecurringEntry
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		m, err := ownedRecurring(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, id)
+		if err != nil {
+			return err
+		}
+		template := recurringFromModel(m)
+		if !template.IsOccurrence(o.Date) {
+			return fmt.Errorf("%w: %s is not a scheduled occurrence", domain.ErrInvalid, o.Date)
+		}
+		template.SetOverride(o)
+		if err := tx.Model(&RecurringEntryModel{}).Where("id = ?", id).
+			Update("overrides", JSONRecurringOverrideSlice(template.Overrides)).Error; err != nil {
+			return fmt.Errorf("save override: %w", err)
+		}
+
+		if template.MaterializedThrough != "" && o.Date <= template.MaterializedThrough {
+			var existing BudgetEntryModel
+			found := tx.Where("recurring_id = ? AND date = ?", id, o.Date).Limit(1).Find(&existing).RowsAffected > 0
+			want, keep := template.EntryOn(o.Date)
+			switch {
+			case !keep && found:
+				err = tx.Delete(&BudgetEntryModel{}, "id = ?", existing.ID).Error
+			case keep && found:
+				want.ID, want.TripID = existing.ID, existing.TripID
+				em := entryToModel(want)
+				err = tx.Save(&em).Error
+			case keep:
+				want.ID = makeID("b")
+				em := entryToModel(want)
+				err = tx.Create(&em).Error
+			}
+			if err != nil {
+				return fmt.Errorf("sync occurrence: %w", err)
+			}
+		}
+		out = template
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MaterializeRecurring generates the budget entries every template owes up
+// to and including asOf, and returns how many were created. Each template is
+// locked while it is advanced, and the unique (recurring_id, date) index keeps
+// concurrent runs from generating an occurrence twice.
+func (s *PgStore) MaterializeRecurring(asOf string) int {
+	var due []RecurringEntryModel
+	s.db.Where("materialized_through < ?", asOf).Find(&due)
+	created := 0
+	for _, candidate := range due {
+		_ = s.db.Transaction(func(tx *gorm.DB) error {
+			var m RecurringEntryModel
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&m, "id = ?", candidate.ID).Error; err != nil {
+				return err
+			}
+			if m.MaterializedThrough >= asOf {
+				return nil
+			}
+			for _, entry := range recurringFromModel(m).Pending(asOf) {
+				entry.ID = makeID("b")
+				em := entryToModel(entry)
+				res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&em)
+				if res.Error != nil {
+					return res.Error
+				}
+				created += int(res.RowsAffected)
+			}
+			return tx.Model(&RecurringEntryModel{}).Where("id = ?", m.ID).
+				Update("materialized_through", asOf).Error
+		})
+	}
+	return created
+}
+
+func ownedRecurring(tx *gorm.DB, userID, id string) (RecurringEntryModel, error) {
+	var m RecurringEntryModel
+	if err := tx.First(&m, "id = ?", id).Error; err != nil {
+		return m, fmt.Errorf("%w: recurring entry %s", domain.ErrNotFound, id)
+	}
+	if m.UserID != userID {
+		return m, fmt.Errorf("%w: recurring entry %s belongs to another user", domain.ErrForbidden, id)
+	}
+	return m, nil
+}
+
+func recurringToModel(r domain.RecurringEntry) RecurringEntryModel {
+	return RecurringEntryModel{
+		ID: r.ID, UserID: r.UserID, Category: r.Category,
+		Amount: r.Amount, Currency: r.Currency, Note: r.Note,
+		Frequency: string(r.Frequency), Interval: max(r.Interval, 1),
+		StartDate: r.StartDate, EndDate: r.EndDate,
+		Overrides:           JSONRecurringOverrideSlice(r.Overrides),
+		MaterializedThrough: r.MaterializedThrough,
+	}
+}
+
+func recurringFromModel(m RecurringEntryModel) domain.RecurringEntry {
+	overrides := []domain.RecurringOverride(m.Overrides)
+	if overrides == nil {
+		overrides = []domain.RecurringOverride{}
+	}
+	return domain.RecurringEntry{
+		ID: m.ID, UserID: m.UserID, Category: m.Category,
+		Amount: m.Amount, Currency: m.Currency, Note: m.Note,
+		Frequency: domain.RecurringFrequency(m.Frequency), Interval: m.Interval,
+		StartDate: m.StartDate, EndDate: m.EndDate,
+		Overrides:           overrides,
+		MaterializedThrough: m.MaterializedThrough,
+	}
+}
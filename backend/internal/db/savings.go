@@ -0,0 +1,117 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *PgStore) CreateSavingsGoal(goal domain.SavingsGoal) domain.SavingsGoal {
+	goal.ID = makeID("sg")
+	goal.Contributions = []domain.SavingsContribution{}
+	m := SavingsGoalModel{
+		ID: goal.ID, UserID: goal.UserID, TripID: goal.TripID, Name: goal.Name,
+		Target: goal.Target, Currency: goal.Currency, TargetDate: goal.TargetDate,
+	}
+	s.db.Create(&m)
+	return goal
+}
+
+func (s *PgStore) ListSavingsGoals(userID string) []domain.SavingsGoal {
+	var models []SavingsGoalModel
+	s.db.Where("user_id = ?", userID).Order("target_date, id").Find(&models)
+	result := make([]domain.SavingsGoal, len(models))
+	index := make(map[string]int, len(models))
+	ids := make([]string, len(models))
+	for i, m := range models {
+		result[i] = goalFromModel(m)
+		index[m.ID], ids[i] = i, m.ID
+	}
+	if len(ids) == 0 {
+		return result
+	}
+	var contributions []SavingsContributionModel
+	s.db.Where("goal_id IN ?", ids).Order("date, id").Find(&contributions)
+	for _, c := range contributions {
+		goal := &result[index[c.GoalID]]
+		goal.Contributions = append(goal.Contributions, contributionFromModel(c))
+	}
+	return result
+}
+
+// DeleteSavingsGoal removes a goal together with its contributions.
+func (s *PgStore) DeleteSavingsGoal(userID, id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := ownedGoal(tx, userID, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&SavingsContributionModel{}, "goal_id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete savings contributions: %w", err)
+		}
+		if err := tx.Delete(&SavingsGoalModel{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete savings goal: %w", err)
+		}
+		return nil
+	})
+}
+
+// AddSavingsContribution records c against the caller's goal and returns the
+// updated goal. The goal row is locked so concurrent withdrawals cannot take
+// the balance below zero.
+func (s *PgStore) AddSavingsContribution(userID, goalID string, c domain.SavingsContribution) (*domain.SavingsGoal, error) {
+	var out domain.SavingsGoal
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		m, err := ownedGoal(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, goalID)
+		if err != nil {
+			return err
+		}
+		var existing []SavingsContributionModel
+		if err := tx.Where("goal_id = ?", goalID).Order("date, id").Find(&existing).Error; err != nil {
+			return fmt.Errorf("load savings contributions: %w", err)
+		}
+		out = goalFromModel(m)
+		for _, e := range existing {
+			out.Contributions = append(out.Contributions, contributionFromModel(e))
+		}
+		if out.Saved("")+c.Amount < 0 {
+			return fmt.Errorf("%w: cannot withdraw more than was saved", domain.ErrInvalid)
+		}
+		c.ID = makeID("sc")
+		cm := SavingsContributionModel{ID: c.ID, GoalID: goalID, Amount: c.Amount, Date: c.Date, Note: c.Note}
+		if err := tx.Create(&cm).Error; err != nil {
+			return fmt.Errorf("save savings contribution: %w", err)
+		}
+		out.Contributions = append(out.Contributions, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func ownedGoal(tx *gorm.DB, userID, id string) (SavingsGoalModel, error) {
+	var m SavingsGoalModel
+	if err := tx.First(&m, "id = ?", id).Error; err != nil {
+		return m, fmt.Errorf("%w: savings goal %s", domain.ErrNotFound, id)
+	}
+	if m.UserID != userID {
+		return m, fmt.Errorf("%w: savings goal %s belongs to another user", domain.ErrForbidden, id)
+	}
+	return m, nil
+}
+
+func goalFromModel(m SavingsGoalModel) domain.SavingsGoal {
+	return domain.SavingsGoal{
+		ID: m.ID, UserID: m.UserID, TripID: m.TripID, Name: m.Name,
+		Target: m.Target, Currency: m.Currency, TargetDate: m.TargetDate,
+		Contributions: []domain.SavingsContribution{},
+	}
+}
+
+func contributionFromModel(m SavingsContributionModel) domain.SavingsContribution {
+	return domain.SavingsContribution{ID: m.ID, Amount: m.Amount, Date: m.Date, Note: m.Note}
+}
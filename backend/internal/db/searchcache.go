@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+// PgSearchCache keeps provider answers in provider_search_cache so that
+// every instance of the server shares them and they survive a restart.
+type PgSearchCache struct {
+	db *gorm.DB
+}
+
+func NewPgSearchCache(db *gorm.DB) *PgSearchCache {
+	return &PgSearchCache{db: db}
+}
+
+func (c *PgSearchCache) Get(ctx context.Context, key string) (provider.CachedSearch, bool, error) {
+	var m ProviderSearchCacheModel
+	err := c.db.WithContext(ctx).Where("key = ? AND keep_until > ?", key, time.Now()).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return provider.CachedSearch{}, false, nil
+	}
+	if err != nil {
+		return provider.CachedSearch{}, false, fmt.Errorf("read search cache: %w", err)
+	}
+	return provider.CachedSearch{Options: m.Options, StoredAt: m.StoredAt}, true, nil
+}
+
+// Put also clears out entries that have expired, so the table only grows
+// with the searches people keep making.
+func (c *PgSearchCache) Put(ctx context.Context, key string, entry provider.CachedSearch, keepUntil time.Time) error {
+	m := ProviderSearchCacheModel{Key: key, Options: entry.Options, StoredAt: entry.StoredAt, KeepUntil: keepUntil}
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("keep_until <= ?", time.Now()).Delete(&ProviderSearchCacheModel{}).Error; err != nil {
+			return fmt.Errorf("prune search cache: %w", err)
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"options", "stored_at", "keep_until"}),
+		}).Create(&m).Error
+		if err != nil {
+			return fmt.Errorf("write search cache: %w", err)
+		}
+		return nil
+	})
+}
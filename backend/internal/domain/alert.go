@@ -0,0 +1,55 @@
+package domain
+
+import "exchange-travel-planner/backend/internal/money"
+
+type AlertKind string
+
+const (
+	// AlertCategoryOver fires when a category's projected spend passes
+	// Threshold percent of its allocation.
+	AlertCategoryOver AlertKind = "category-over"
+	// AlertRemainingUnder fires when the projected remaining budget drops
+	// below Threshold.
+	AlertRemainingUnder AlertKind = "remaining-under"
+	// AlertExpenseOver fires for a single new entry above Threshold.
+	AlertExpenseOver AlertKind = "expense-over"
+	// AlertTripIntoRed fires when a planned trip turns a period that was
+	// affordable into an overspent one.
+	AlertTripIntoRed AlertKind = "trip-into-red"
+)
+
+// AlertRule is a user-defined budget threshold. Threshold is a percentage for
+// category-over rules, an amount in Currency for remaining-under and
+// expense-over rules, and unused for trip-into-red.
+type AlertRule struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"userId"`
+	Kind      AlertKind    `json:"kind"`
+	Category  string       `json:"category,omitempty"`
+	Threshold money.Amount `json:"threshold"`
+	Currency  string       `json:"currency,omitempty"`
+	Disabled  bool         `json:"disabled"`
+}
+
+// Alert is a triggered rule. Value and Threshold are in Currency, or percent
+// for category-over alerts.
+type Alert struct {
+	ID          string       `json:"id"`
+	UserID      string       `json:"userId"`
+	RuleID      string       `json:"ruleId"`
+	Kind        AlertKind    `json:"kind"`
+	Severity    Severity     `json:"severity"`
+	Message     string       `json:"message"`
+	Value       money.Amount `json:"value"`
+	Threshold   money.Amount `json:"threshold"`
+	Currency    string       `json:"currency,omitempty"`
+	Category    string       `json:"category,omitempty"`
+	EntryID     string       `json:"entryId,omitempty"`
+	TripID      string       `json:"tripId,omitempty"`
+	PeriodStart string       `json:"periodStart"`
+	// Key names the condition an alert reports so it is only recorded once,
+	// e.g. once per rule and budget period, or once per rule and entry.
+	Key         string `json:"-"`
+	Read        bool   `json:"read"`
+	TriggeredAt string `json:"triggeredAt"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// BudgetEntryFilter narrows a budget entry listing. Zero values mean "any";
+// a Limit of zero or less returns every match.
+type BudgetEntryFilter struct {
+	From      string
+	To        string
+	Category  string
+	TripID    string
+	Currency  string
+	MinAmount *money.Amount
+	MaxAmount *money.Amount
+	Query     string
+	Limit     int
+	Offset    int
+}
+
+// Matches reports whether entry passes every filter (pagination aside).
+func (f BudgetEntryFilter) Matches(entry BudgetEntry) bool {
+	switch {
+	case f.From != "" && entry.Date < f.From,
+		f.To != "" && entry.Date > f.To,
+		f.Category != "" && !strings.EqualFold(entry.Category, f.Category),
+		f.TripID != "" && entry.TripID != f.TripID,
+		f.Currency != "" && !strings.EqualFold(entry.Currency, f.Currency),
+		f.MinAmount != nil && entry.Amount < *f.MinAmount,
+		f.MaxAmount != nil && entry.Amount > *f.MaxAmount,
+		f.Query != "" && !strings.Contains(strings.ToLower(entry.Note), strings.ToLower(f.Query)):
+		return false
+	}
+	return true
+}
+
+type BudgetEntryPage struct {
+	Entries []BudgetEntry `json:"entries"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
+
+// BudgetEntryPatch carries the fields of a partial update; nil means unchanged.
+type BudgetEntryPatch struct {
+	Category *string       `json:"category"`
+	Amount   *money.Amount `json:"amount"`
+	Currency *string       `json:"currency"`
+	Date     *string       `json:"date"`
+	TripID   *string       `json:"tripId"`
+	Note     *string       `json:"note"`
+}
+
+// Apply copies the set fields onto entry.
+func (p BudgetEntryPatch) Apply(entry *BudgetEntry) {
+	if p.Category != nil {
+		entry.Category = *p.Category
+	}
+	if p.Amount != nil {
+		entry.Amount = *p.Amount
+	}
+	if p.Currency != nil {
+		entry.Currency = *p.Currency
+	}
+	if p.Date != nil {
+		entry.Date = *p.Date
+	}
+	if p.TripID != nil {
+		entry.TripID = *p.TripID
+	}
+	if p.Note != nil {
+		entry.Note = *p.Note
+	}
+}
+
+// Money returns the entry's amount in its currency.
+func (e BudgetEntry) Money() money.Money {
+	return money.New(e.Amount, e.Currency)
+}
+
+// CategoryRule files imported transactions whose description contains
+// Keyword (case-insensitive) under Category.
+type CategoryRule struct {
+	Keyword  string `json:"keyword"`
+	Category string `json:"category"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+// Sentinel errors returned by DataStore methods. Implementations wrap them
+// with context (fmt.Errorf("%w: ...")) so handlers can map them to HTTP codes.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+	ErrInvalid   = errors.New("invalid request")
+	ErrConflict  = errors.New("conflict")
+)
@@ -0,0 +1,38 @@
+package domain
+
+import "exchange-travel-planner/backend/internal/money"
+
+// Member strain reasons reported by group optimization.
+const (
+	StrainOverBudget    = "over-budget"
+	StrainLongTransit   = "long-transit"
+	StrainStyleMismatch = "style-mismatch"
+	StrainExamConflict  = "exam-conflict"
+	StrainDeadline      = "deadline-nearby"
+)
+
+// MemberFit describes how one trip member fares with a group option.
+type MemberFit struct {
+	UserID         string          `json:"userId"`
+	DepartureCity  string          `json:"departureCity"`
+	EstimatedCost  money.Amount    `json:"estimatedCost"`
+	BudgetHeadroom money.Amount    `json:"budgetHeadroom"`
+	Conflicts      []ConflictAlert `json:"conflicts"`
+	Strains        []string        `json:"strains"`
+	Score          float64         `json:"score"`
+}
+
+// GroupTripOption is a destination + travel window ranked for a whole trip.
+// Its costs, and its members' costs and headroom, are all in Currency.
+type GroupTripOption struct {
+	Destination        string       `json:"destination"`
+	WindowID           string       `json:"windowId"`
+	StartDate          string       `json:"startDate"`
+	EndDate            string       `json:"endDate"`
+	Score              float64      `json:"score"`
+	TotalEstimatedCost money.Amount `json:"totalEstimatedCost"`
+	Currency           string       `json:"currency"`
+	WorksForEveryone   bool         `json:"worksForEveryone"`
+	StrainedMembers    []string     `json:"strainedMembers"`
+	Members            []MemberFit  `json:"members"`
+}
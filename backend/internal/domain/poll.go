@@ -0,0 +1,165 @@
+package domain
+
+import "time"
+
+type PollKind string
+
+const (
+	PollDestination PollKind = "destination"
+	PollWindow      PollKind = "window"
+	PollFreeform    PollKind = "freeform"
+)
+
+type PollCloseRule string
+
+const (
+	// PollCloseManual polls stay open until closed explicitly or the deadline passes.
+	PollCloseManual PollCloseRule = "manual"
+	// PollCloseAllVoted polls close once every trip member has voted.
+	PollCloseAllVoted PollCloseRule = "all-voted"
+	// PollCloseMajority polls close once one option holds more than half the members.
+	PollCloseMajority PollCloseRule = "majority"
+)
+
+type PollStatus string
+
+const (
+	PollOpen   PollStatus = "open"
+	PollClosed PollStatus = "closed"
+)
+
+type PollOption struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Destination string `json:"destination,omitempty"`
+	WindowID    string `json:"windowId,omitempty"`
+}
+
+type PollVote struct {
+	UserID   string `json:"userId"`
+	OptionID string `json:"optionId"`
+}
+
+type Poll struct {
+	ID             string        `json:"id"`
+	TripID         string        `json:"tripId"`
+	CreatedBy      string        `json:"createdBy"`
+	Question       string        `json:"question"`
+	Kind           PollKind      `json:"kind"`
+	Options        []PollOption  `json:"options"`
+	Votes          []PollVote    `json:"votes"`
+	CloseRule      PollCloseRule `json:"closeRule"`
+	ClosesAt       string        `json:"closesAt,omitempty"`
+	AutoApply      bool          `json:"autoApply"`
+	Status         PollStatus    `json:"status"`
+	WinnerOptionID string        `json:"winnerOptionId,omitempty"`
+	Applied        bool          `json:"applied"`
+}
+
+// Option returns the option with the given ID.
+func (p Poll) Option(id string) (PollOption, bool) {
+	for _, opt := range p.Options {
+		if opt.ID == id {
+			return opt, true
+		}
+	}
+	return PollOption{}, false
+}
+
+// Tally counts votes per option ID.
+func (p Poll) Tally() map[string]int {
+	counts := make(map[string]int, len(p.Options))
+	for _, v := range p.Votes {
+		counts[v.OptionID]++
+	}
+	return counts
+}
+
+// Leader returns the option with the most votes. Ties and empty polls have no leader.
+func (p Poll) Leader() (PollOption, int, bool) {
+	counts := p.Tally()
+	var best PollOption
+	bestVotes, tied := 0, false
+	for _, opt := range p.Options {
+		switch n := counts[opt.ID]; {
+		case n > bestVotes:
+			best, bestVotes, tied = opt, n, false
+		case n == bestVotes && n > 0:
+			tied = true
+		}
+	}
+	if bestVotes == 0 || tied {
+		return PollOption{}, bestVotes, false
+	}
+	return best, bestVotes, true
+}
+
+// CastVote records userID's vote, replacing any earlier vote by the same member.
+func (p *Poll) CastVote(userID, optionID string) {
+	for i := range p.Votes {
+		if p.Votes[i].UserID == userID {
+			p.Votes[i].OptionID = optionID
+			return
+		}
+	}
+	p.Votes = append(p.Votes, PollVote{UserID: userID, OptionID: optionID})
+}
+
+// Deadline parses ClosesAt, accepting RFC3339 timestamps or plain dates
+// (a date closes at the end of that day, UTC).
+func (p Poll) Deadline() (time.Time, bool) {
+	if p.ClosesAt == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, p.ClosesAt); err == nil {
+		return t, true
+	}
+	if d, err := time.Parse("2006-01-02", p.ClosesAt); err == nil {
+		return d.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, false
+}
+
+// ShouldClose reports whether an open poll meets its closing rule for a trip
+// with memberCount members at time now.
+func (p Poll) ShouldClose(memberCount int, now time.Time) bool {
+	if p.Status != PollOpen {
+		return false
+	}
+	if deadline, ok := p.Deadline(); ok && !now.Before(deadline) {
+		return true
+	}
+	switch p.CloseRule {
+	case PollCloseAllVoted:
+		return memberCount > 0 && len(p.Votes) >= memberCount
+	case PollCloseMajority:
+		_, votes, ok := p.Leader()
+		return ok && votes*2 > memberCount
+	}
+	return false
+}
+
+// Close marks the poll closed and records the leading option, if any.
+func (p *Poll) Close() {
+	p.Status = PollClosed
+	if winner, _, ok := p.Leader(); ok {
+		p.WinnerOptionID = winner.ID
+	}
+}
+
+// ApplyTo copies the winning destination and/or window onto trip. It reports
+// false when there is no winner or the winner carries nothing to apply
+// (free-form options).
+func (p Poll) ApplyTo(trip *Trip) bool {
+	winner, ok := p.Option(p.WinnerOptionID)
+	if !ok || (winner.Destination == "" && winner.WindowID == "") {
+		return false
+	}
+	if winner.Destination != "" {
+		trip.Destination = winner.Destination
+	}
+	if winner.WindowID != "" {
+		trip.WindowID = winner.WindowID
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package domain
+
+// UserProfile holds per-user travel preferences used when planning for a group.
+type UserProfile struct {
+	UserID         string  `json:"userId"`
+	DisplayName    string  `json:"displayName"`
+	HomeCity       string  `json:"homeCity"`
+	Style          string  `json:"style"`
+	MaxTravelHours float64 `json:"maxTravelHours"`
+	HomeCurrency   string  `json:"homeCurrency"`
+	// Passes are the rail passes and discount cards the user holds; their
+	// searches and trip estimates are priced with them.
+	Passes []TravelPass `json:"passes"`
+}
+
+// TravelPass is a pass or discount card. Type names a known one such as
+// "interrail" or "bahncard-50", whose terms the other fields can narrow, or
+// is "custom" for one described entirely by them. Dates are YYYY-MM-DD and
+// inclusive.
+type TravelPass struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name,omitempty"`
+	Countries  []string `json:"countries,omitempty"`
+	Operators  []string `json:"operators,omitempty"`
+	Modes      []string `json:"modes,omitempty"`
+	Percent    float64  `json:"percent,omitempty"`
+	Covers     bool     `json:"covers,omitempty"`
+	ValidFrom  string   `json:"validFrom,omitempty"`
+	ValidUntil string   `json:"validUntil,omitempty"`
+}
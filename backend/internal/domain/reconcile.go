@@ -0,0 +1,58 @@
+package domain
+
+import "exchange-travel-planner/backend/internal/money"
+
+// Cost buckets a trip estimate is broken down into.
+const (
+	BucketTransport = "transport"
+	BucketStay      = "stay"
+	BucketDaily     = "daily"
+)
+
+// ReconciliationLine compares one bucket of a trip's estimate with what was spent.
+type ReconciliationLine struct {
+	Bucket     string       `json:"bucket"`
+	Estimated  money.Amount `json:"estimated"`
+	Actual     money.Amount `json:"actual"`
+	Delta      money.Amount `json:"delta"`
+	Categories []string     `json:"categories"`
+}
+
+// TripReconciliation is the post-trip report of estimated vs actual cost,
+// summed over every member's entries linked to the trip.
+type TripReconciliation struct {
+	TripID         string               `json:"tripId"`
+	Destination    string               `json:"destination"`
+	Currency       string               `json:"currency"`
+	StartDate      string               `json:"startDate,omitempty"`
+	EndDate        string               `json:"endDate,omitempty"`
+	Nights         int                  `json:"nights"`
+	Lines          []ReconciliationLine `json:"lines"`
+	ByCategory     []CategorySpend      `json:"byCategory"`
+	EstimatedTotal money.Amount         `json:"estimatedTotal"`
+	ActualTotal    money.Amount         `json:"actualTotal"`
+	Delta          money.Amount         `json:"delta"`
+	// DeltaPercent is Delta relative to the estimate; zero without an estimate.
+	DeltaPercent float64  `json:"deltaPercent"`
+	EntryCount   int      `json:"entryCount"`
+	Finalized    bool     `json:"finalized"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// CostFeedback is what a finalised trip teaches us about its destination:
+// actual/estimated ratios per bucket, zero where there was nothing to compare.
+type CostFeedback struct {
+	TripID         string  `json:"tripId"`
+	Destination    string  `json:"destination"`
+	TransportRatio float64 `json:"transportRatio"`
+	StayRatio      float64 `json:"stayRatio"`
+	DailyRatio     float64 `json:"dailyRatio"`
+}
+
+// CostFactors scale a destination's seeded costs; 1 means no adjustment.
+type CostFactors struct {
+	Transport float64 `json:"transport"`
+	Stay      float64 `json:"stay"`
+	Daily     float64 `json:"daily"`
+	Samples   int     `json:"samples"`
+}
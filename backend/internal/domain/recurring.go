@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"time"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+type RecurringFrequency string
+
+const (
+	RecurringMonthly RecurringFrequency = "monthly"
+	RecurringWeekly  RecurringFrequency = "weekly"
+)
+
+// RecurringOverride changes or skips a single occurrence of a recurring entry.
+// Nil fields keep the template's value.
+type RecurringOverride struct {
+	Date     string        `json:"date"`
+	Skip     bool          `json:"skip,omitempty"`
+	Category *string       `json:"category,omitempty"`
+	Amount   *money.Amount `json:"amount,omitempty"`
+	Note     *string       `json:"note,omitempty"`
+}
+
+// RecurringEntry is a template that materialises a BudgetEntry on every
+// scheduled date between StartDate and EndDate (open-ended when empty).
+type RecurringEntry struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"userId"`
+	Category  string             `json:"category"`
+	Amount    money.Amount       `json:"amount"`
+	Currency  string             `json:"currency"`
+	Note      string             `json:"note,omitempty"`
+	Frequency RecurringFrequency `json:"frequency"`
+	// Interval repeats every N weeks or months; zero means every one.
+	Interval  int                 `json:"interval,omitempty"`
+	StartDate string              `json:"startDate"`
+	EndDate   string              `json:"endDate,omitempty"`
+	Overrides []RecurringOverride `json:"overrides"`
+	// MaterializedThrough is the last day entries have been generated for.
+	MaterializedThrough string `json:"materializedThrough,omitempty"`
+}
+
+// Occurrences lists scheduled dates within [from, to], inclusive. Monthly
+// schedules keep the start date's day of month, clamped to shorter months.
+// Open-ended templates need a to date.
+func (r RecurringEntry) Occurrences(from, to string) []string {
+	start, err := time.Parse("2006-01-02", r.StartDate)
+	if err != nil || (to == "" && r.EndDate == "") {
+		return nil
+	}
+	interval := max(r.Interval, 1)
+	var out []string
+	for i := 0; ; i++ {
+		var day time.Time
+		switch r.Frequency {
+		case RecurringWeekly:
+			day = start.AddDate(0, 0, 7*interval*i)
+		case RecurringMonthly:
+			first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, interval*i, 0)
+			last := first.AddDate(0, 1, -1).Day()
+			day = first.AddDate(0, 0, min(start.Day(), last)-1)
+		default:
+			return nil
+		}
+		key := day.Format("2006-01-02")
+		if (to != "" && key > to) || (r.EndDate != "" && key > r.EndDate) {
+			return out
+		}
+		if key >= from {
+			out = append(out, key)
+		}
+	}
+}
+
+// IsOccurrence reports whether date is on the schedule.
+func (r RecurringEntry) IsOccurrence(date string) bool {
+	return len(r.Occurrences(date, date)) == 1
+}
+
+// Override returns the override recorded for date, if any.
+func (r RecurringEntry) Override(date string) (RecurringOverride, bool) {
+	for _, o := range r.Overrides {
+		if o.Date == date {
+			return o, true
+		}
+	}
+	return RecurringOverride{}, false
+}
+
+// SetOverride records o, replacing any earlier override for the same date.
+func (r *RecurringEntry) SetOverride(o RecurringOverride) {
+	for i := range r.Overrides {
+		if r.Overrides[i].Date == o.Date {
+			r.Overrides[i] = o
+			return
+		}
+	}
+	r.Overrides = append(r.Overrides, o)
+}
+
+// EntryOn builds the budget entry for the occurrence on date, with any
+// override applied. It reports false when that occurrence is skipped.
+func (r RecurringEntry) EntryOn(date string) (BudgetEntry, bool) {
+	entry := BudgetEntry{
+		UserID:      r.UserID,
+		Category:    r.Category,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Date:        date,
+		Note:        r.Note,
+		RecurringID: r.ID,
+	}
+	o, ok := r.Override(date)
+	if !ok {
+		return entry, true
+	}
+	if o.Skip {
+		return BudgetEntry{}, false
+	}
+	if o.Category != nil {
+		entry.Category = *o.Category
+	}
+	if o.Amount != nil {
+		entry.Amount = *o.Amount
+	}
+	if o.Note != nil {
+		entry.Note = *o.Note
+	}
+	return entry, true
+}
+
+// Pending returns the entries scheduled after MaterializedThrough up to and
+// including through, leaving out skipped occurrences. Entries have no ID yet.
+func (r RecurringEntry) Pending(through string) []BudgetEntry {
+	from := r.StartDate
+	if r.MaterializedThrough != "" {
+		next, err := time.Parse("2006-01-02", r.MaterializedThrough)
+		if err != nil {
+			return nil
+		}
+		from = max(from, next.AddDate(0, 0, 1).Format("2006-01-02"))
+	}
+	var out []BudgetEntry
+	for _, date := range r.Occurrences(from, through) {
+		if entry, ok := r.EntryOn(date); ok {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
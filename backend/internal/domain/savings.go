@@ -0,0 +1,62 @@
+package domain
+
+import "exchange-travel-planner/backend/internal/money"
+
+// SavingsGoal is money a user sets aside for a planned trip by TargetDate.
+type SavingsGoal struct {
+	ID            string                `json:"id"`
+	UserID        string                `json:"userId"`
+	TripID        string                `json:"tripId"`
+	Name          string                `json:"name"`
+	Target        money.Amount          `json:"target"`
+	Currency      string                `json:"currency"`
+	TargetDate    string                `json:"targetDate"`
+	Contributions []SavingsContribution `json:"contributions"`
+}
+
+// SavingsContribution is money put towards a goal, or taken back out of it
+// when Amount is negative. It is in the goal's currency.
+type SavingsContribution struct {
+	ID     string       `json:"id"`
+	Amount money.Amount `json:"amount"`
+	Date   string       `json:"date"`
+	Note   string       `json:"note,omitempty"`
+}
+
+// Saved sums the contributions made up to and including date; an empty date
+// counts all of them.
+func (g SavingsGoal) Saved(date string) money.Amount {
+	var total money.Amount
+	for _, c := range g.Contributions {
+		if date == "" || c.Date <= date {
+			total += c.Amount
+		}
+	}
+	return total
+}
+
+type GoalStatus string
+
+const (
+	GoalReached GoalStatus = "reached"
+	GoalOnTrack GoalStatus = "on-track"
+	GoalBehind  GoalStatus = "behind"
+	GoalMissed  GoalStatus = "missed"
+)
+
+// GoalProgress is a savings goal seen from one forecast period, in the
+// forecast's currency. MonthlyNeeded spreads what was still missing at the
+// start of the period evenly over the periods left until TargetDate.
+type GoalProgress struct {
+	GoalID          string       `json:"goalId"`
+	TripID          string       `json:"tripId"`
+	Name            string       `json:"name"`
+	TargetDate      string       `json:"targetDate"`
+	Target          money.Amount `json:"target"`
+	Saved           money.Amount `json:"saved"`
+	Remaining       money.Amount `json:"remaining"`
+	PeriodsLeft     int          `json:"periodsLeft"`
+	MonthlyNeeded   money.Amount `json:"monthlyNeeded"`
+	SavedThisPeriod money.Amount `json:"savedThisPeriod"`
+	Status          GoalStatus   `json:"status"`
+}
@@ -4,15 +4,52 @@ package domain
 // Both the in-memory store and the PostgreSQL-backed store implement this.
 type DataStore interface {
 	ImportAcademicEvents(events []AcademicEvent) []AcademicEvent
+	ListAcademicEvents(userID string) []AcademicEvent
 	ListTravelWindows(from, to string) []TravelWindow
 	OptimizeTrips(c TripConstraint) []TripOption
 	GetTrip(id string) *Trip
 	ShareTrip(tripID string, memberIDs []string) *Trip
 	AddBudgetEntry(entry BudgetEntry) BudgetEntry
+	AddBudgetEntries(entries []BudgetEntry) []BudgetEntry
 	ListBudgetEntries(userID string) []BudgetEntry
-	Forecast(userID, tripID string) ForecastResult
+	QueryBudgetEntries(userID string, filter BudgetEntryFilter) BudgetEntryPage
+	StreamBudgetEntries(userID string, filter BudgetEntryFilter, fn func(BudgetEntry) error) error
+	UpdateBudgetEntry(userID, id string, patch BudgetEntryPatch) (*BudgetEntry, error)
+	DeleteBudgetEntry(userID, id string) error
+	CreateRecurringEntry(entry RecurringEntry) RecurringEntry
+	ListRecurringEntries(userID string) []RecurringEntry
+	DeleteRecurringEntry(userID, id string) error
+	OverrideRecurringOccurrence(userID, id string, override RecurringOverride) (*RecurringEntry, error)
+	MaterializeRecurring(asOf string) int
+	CreateSavingsGoal(goal SavingsGoal) SavingsGoal
+	ListSavingsGoals(userID string) []SavingsGoal
+	DeleteSavingsGoal(userID, id string) error
+	AddSavingsContribution(userID, goalID string, c SavingsContribution) (*SavingsGoal, error)
+	Forecast(userID, tripID, asOf string) ForecastResult
+	GetBudgetSettings(userID string) BudgetSettings
+	SaveBudgetSettings(settings BudgetSettings) BudgetSettings
+	GetCategoryRules(userID string) []CategoryRule
+	SaveCategoryRules(userID string, rules []CategoryRule) []CategoryRule
+	ListAlertRules(userID string) []AlertRule
+	SaveAlertRule(rule AlertRule) (*AlertRule, error)
+	DeleteAlertRule(userID, id string) error
+	RecordAlerts(alerts []Alert) []Alert
+	ListAlerts(userID string, unreadOnly bool) []Alert
+	MarkAlertsRead(userID string, ids []string) int
+	ReconcileTrip(tripID, currency string) (*TripReconciliation, error)
+	FinalizeTripReconciliation(tripID, currency string) (*TripReconciliation, error)
 	EvaluateConflicts(windowID string) []ConflictAlert
+	EvaluateMemberConflicts(userID, windowID string) []ConflictAlert
 	SearchTransport(from, to string) []TransportOption
 	SearchStays(city string) []StayOption
+	CreatePoll(poll Poll) (*Poll, error)
+	ListPolls(tripID string) []Poll
+	GetPoll(tripID, pollID string) (*Poll, error)
+	VotePoll(tripID, pollID, userID, optionID string) (*Poll, error)
+	ClosePoll(tripID, pollID string) (*Poll, error)
+	SaveFXRates(rates []FXRate) int
+	ListFXRates() []FXRate
+	GetProfile(userID string) UserProfile
+	SaveProfile(profile UserProfile) UserProfile
 	Close() error
 }
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"time"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
 type Severity string
 
 const (
@@ -27,6 +33,7 @@ type TravelWindow struct {
 
 type AcademicEvent struct {
 	ID       string            `json:"id"`
+	UserID   string            `json:"userId,omitempty"`
 	Type     AcademicEventType `json:"type"`
 	Title    string            `json:"title"`
 	Start    string            `json:"start"`
@@ -35,28 +42,149 @@ type AcademicEvent struct {
 }
 
 type TripConstraint struct {
-	BudgetCap      float64 `json:"budgetCap"`
-	MaxTravelHours float64 `json:"maxTravelHours"`
-	PartySize      int     `json:"partySize"`
-	Style          string  `json:"style"`
-	WindowID       string  `json:"windowId"`
-	DepartureCity  string  `json:"departureCity"`
+	BudgetCap      money.Amount `json:"budgetCap"`
+	MaxTravelHours float64      `json:"maxTravelHours"`
+	PartySize      int          `json:"partySize"`
+	Style          string       `json:"style"`
+	WindowID       string       `json:"windowId"`
+	DepartureCity  string       `json:"departureCity"`
+	// Currency of BudgetCap and of the returned costs; empty means EUR.
+	Currency string `json:"currency,omitempty"`
 }
 
+// PriceKind says where a price came from: a fare quoted by the operator or
+// an estimate from a fare table or formula.
+type PriceKind string
+
+const (
+	PriceQuoted    PriceKind = "quoted"
+	PriceEstimated PriceKind = "estimated"
+)
+
 type TransportOption struct {
 	Provider      string  `json:"provider"`
 	Mode          string  `json:"mode"`
 	DurationHours float64 `json:"durationHours"`
-	Price         float64 `json:"price"`
-	Deeplink      string  `json:"deeplink"`
+	// Price covers every passenger searched for.
+	Price     money.Amount `json:"price"`
+	PriceKind PriceKind    `json:"priceKind,omitempty"`
+	Deeplink  string       `json:"deeplink"`
+	// Departure and Arrival are RFC 3339 timestamps, set by providers that
+	// search a real timetable.
+	Departure string         `json:"departure,omitempty"`
+	Arrival   string         `json:"arrival,omitempty"`
+	Transfers int            `json:"transfers"`
+	Legs      []TransportLeg `json:"legs,omitempty"`
+	// Synthetic marks options made up from seed data rather than returned
+	// by a provider.
+	Synthetic bool `json:"synthetic,omitempty"`
+	// Passes names the user's passes and cards that lowered Price.
+	Passes []string `json:"passes,omitempty"`
+	// Baggage and Refundable are the fare's rules, set by providers that
+	// publish them. BaggageCost is what the bags searched for add to Price.
+	Baggage     *Baggage     `json:"baggage,omitempty"`
+	Refundable  *bool        `json:"refundable,omitempty"`
+	BaggageCost money.Amount `json:"baggageCost,omitempty"`
+}
+
+// TotalCost is what the journey costs with the bags searched for.
+func (o TransportOption) TotalCost() money.Amount { return o.Price + o.BaggageCost }
+
+// Baggage is what a fare allows each passenger to bring.
+type Baggage struct {
+	CabinBag   BagAllowance `json:"cabinBag"`
+	CheckedBag BagAllowance `json:"checkedBag"`
+}
+
+// BagAllowance says whether a bag is included in the fare and, if not,
+// what adding one costs.
+type BagAllowance struct {
+	Included bool         `json:"included"`
+	Fee      money.Amount `json:"fee,omitempty"`
+}
+
+// Cost is what cabin and checked bags, counted across the party, cost on
+// top of the fare.
+func (b Baggage) Cost(cabin, checked int) money.Amount {
+	cost := money.Amount(0)
+	if !b.CabinBag.Included {
+		cost += b.CabinBag.Fee.Mul(float64(cabin))
+	}
+	if !b.CheckedBag.Included {
+		cost += b.CheckedBag.Fee.Mul(float64(checked))
+	}
+	return cost
+}
+
+type LegKind string
+
+const (
+	LegRide LegKind = "ride"
+	LegWalk LegKind = "walk"
+)
+
+// TransportLeg is one part of a journey: a ride on a line, or a walking
+// transfer between stations.
+type TransportLeg struct {
+	Kind      LegKind `json:"kind"`
+	Mode      string  `json:"mode"`
+	Line      string  `json:"line,omitempty"`
+	Operator  string  `json:"operator,omitempty"`
+	Direction string  `json:"direction,omitempty"`
+	From      LegStop `json:"from"`
+	To        LegStop `json:"to"`
+	// WalkMinutes is set on walking legs.
+	WalkMinutes int `json:"walkMinutes,omitempty"`
+	// DistanceKm is the straight-line distance between the stops, when the
+	// provider knows where they are.
+	DistanceKm float64 `json:"distanceKm,omitempty"`
+	// CoveredBy names the pass that pays for a ride, which then costs only
+	// its ReservationFee.
+	CoveredBy      string       `json:"coveredBy,omitempty"`
+	ReservationFee money.Amount `json:"reservationFee,omitempty"`
+}
+
+// LegStop is where a leg starts or ends; Time is RFC 3339.
+type LegStop struct {
+	Station   string `json:"station"`
+	StationID string `json:"stationId,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	Time      string `json:"time,omitempty"`
+}
+
+// DepartureTime is when the journey leaves, from the option or its first
+// timed leg.
+func (o TransportOption) DepartureTime() (time.Time, bool) {
+	raw := o.Departure
+	for i := 0; raw == "" && i < len(o.Legs); i++ {
+		raw = o.Legs[i].From.Time
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	return t, err == nil
+}
+
+// ArrivalTime is when the journey arrives, from the option or its last
+// timed leg.
+func (o TransportOption) ArrivalTime() (time.Time, bool) {
+	raw := o.Arrival
+	for i := len(o.Legs) - 1; raw == "" && i >= 0; i-- {
+		raw = o.Legs[i].To.Time
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	return t, err == nil
 }
 
 type StayOption struct {
-	Provider     string  `json:"provider"`
-	Kind         string  `json:"kind"`
-	NightlyPrice float64 `json:"nightlyPrice"`
-	Rating       float64 `json:"rating"`
-	Deeplink     string  `json:"deeplink"`
+	Provider     string       `json:"provider"`
+	Name         string       `json:"name,omitempty"`
+	Kind         string       `json:"kind"`
+	NightlyPrice money.Amount `json:"nightlyPrice"`
+	// TotalPrice covers the whole party for the whole stay; set only by
+	// providers that priced actual dates.
+	TotalPrice money.Amount `json:"totalPrice,omitempty"`
+	Rating     float64      `json:"rating"`
+	Deeplink   string       `json:"deeplink"`
+	Synthetic  bool         `json:"synthetic,omitempty"`
 }
 
 type ConflictAlert struct {
@@ -66,38 +194,95 @@ type ConflictAlert struct {
 }
 
 type TripOption struct {
-	ID                 string            `json:"id"`
-	Destination        string            `json:"destination"`
-	ReasonTags         []string          `json:"reasonTags"`
-	TotalEstimatedCost float64           `json:"totalEstimatedCost"`
-	TransportOptions   []TransportOption `json:"transportOptions"`
-	StayOptions        []StayOption      `json:"stayOptions"`
-	RiskLevel          Severity          `json:"riskLevel"`
+	ID                 string       `json:"id"`
+	Destination        string       `json:"destination"`
+	ReasonTags         []string     `json:"reasonTags"`
+	TotalEstimatedCost money.Amount `json:"totalEstimatedCost"`
+	// StayCost is the accommodation part of TotalEstimatedCost.
+	StayCost         money.Amount      `json:"stayCost"`
+	TransportOptions []TransportOption `json:"transportOptions"`
+	StayOptions      []StayOption      `json:"stayOptions"`
+	RiskLevel        Severity          `json:"riskLevel"`
+	Currency         string            `json:"currency"`
 }
 
 type Trip struct {
-	ID            string   `json:"id"`
-	OwnerID       string   `json:"ownerId"`
-	Destination   string   `json:"destination"`
-	WindowID      string   `json:"windowId"`
-	Members       []string `json:"members"`
-	Itinerary     []string `json:"itinerary"`
-	EstimatedCost float64  `json:"estimatedCost"`
+	ID            string       `json:"id"`
+	OwnerID       string       `json:"ownerId"`
+	Destination   string       `json:"destination"`
+	WindowID      string       `json:"windowId"`
+	Members       []string     `json:"members"`
+	Itinerary     []string     `json:"itinerary"`
+	EstimatedCost money.Amount `json:"estimatedCost"`
 }
 
 type BudgetEntry struct {
-	ID       string  `json:"id"`
-	UserID   string  `json:"userId"`
-	Category string  `json:"category"`
-	Amount   float64 `json:"amount"`
-	Currency string  `json:"currency"`
-	Date     string  `json:"date"`
-	TripID   string  `json:"tripId,omitempty"`
-	Note     string  `json:"note,omitempty"`
+	ID       string       `json:"id"`
+	UserID   string       `json:"userId"`
+	Category string       `json:"category"`
+	Amount   money.Amount `json:"amount"`
+	Currency string       `json:"currency"`
+	Date     string       `json:"date"`
+	TripID   string       `json:"tripId,omitempty"`
+	Note     string       `json:"note,omitempty"`
+	// RecurringID links entries generated from a recurring template.
+	RecurringID string `json:"recurringId,omitempty"`
 }
 
 type ForecastResult struct {
-	ProjectedMonthlySpend float64 `json:"projectedMonthlySpend"`
-	RemainingBudget       float64 `json:"remainingBudget"`
-	Affordability         string  `json:"affordability"`
+	ProjectedMonthlySpend money.Amount    `json:"projectedMonthlySpend"`
+	RemainingBudget       money.Amount    `json:"remainingBudget"`
+	Affordability         string          `json:"affordability"`
+	Currency              string          `json:"currency"`
+	MonthlyBudget         money.Amount    `json:"monthlyBudget"`
+	PeriodStart           string          `json:"periodStart"`
+	PeriodEnd             string          `json:"periodEnd"`
+	AsOf                  string          `json:"asOf"`
+	SpentToDate           money.Amount    `json:"spentToDate"`
+	DailyRunRate          money.Amount    `json:"dailyRunRate"`
+	TripCost              money.Amount    `json:"tripCost"`
+	Categories            []CategorySpend `json:"categories"`
+	Daily                 []DailyBalance  `json:"daily"`
+	// SavingsNeeded is what the user's goals still ask to be set aside this
+	// period; Goals has the per-goal breakdown.
+	SavingsNeeded money.Amount   `json:"savingsNeeded,omitempty"`
+	Goals         []GoalProgress `json:"goals,omitempty"`
+	Warnings      []string       `json:"warnings,omitempty"`
+}
+
+// DailyBalance is one day of the remaining-balance series; Projected days
+// are estimated from the run-rate and known upcoming costs.
+type DailyBalance struct {
+	Date      string       `json:"date"`
+	Spend     money.Amount `json:"spend"`
+	Balance   money.Amount `json:"balance"`
+	Projected bool         `json:"projected"`
+}
+
+// FXRate is an ECB-style reference rate: units of Currency per 1 EUR on Date.
+type FXRate struct {
+	Date     string  `json:"date"`
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+}
+
+// BudgetSettings is a user's monthly budget configuration.
+type BudgetSettings struct {
+	UserID        string                  `json:"userId"`
+	MonthlyBudget money.Amount            `json:"monthlyBudget"`
+	Allocations   map[string]money.Amount `json:"allocations"`
+	Currency      string                  `json:"currency"`
+	// StartDay is the day of month (1-28) a budget period begins.
+	StartDay int `json:"startDay"`
+	// AmberThreshold is the remaining budget, in Currency, below which a
+	// forecast turns amber; zero uses the default of €200.
+	AmberThreshold money.Amount `json:"amberThreshold"`
+}
+
+type CategorySpend struct {
+	Category   string       `json:"category"`
+	Spent      money.Amount `json:"spent"`
+	Projected  money.Amount `json:"projected"`
+	Allocation money.Amount `json:"allocation"`
+	Remaining  money.Amount `json:"remaining"`
 }
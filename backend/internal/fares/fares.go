@@ -0,0 +1,180 @@
+// Package fares prices transport journeys. Providers that cannot quote a
+// fare themselves ask a Model, which tries the fare sources registered for
+// the provider and falls back to estimating from a distance-based table.
+package fares
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// ErrNoFare is what a Source returns for journeys it cannot price.
+var ErrNoFare = errors.New("no fare")
+
+type Category string
+
+const (
+	Adult   Category = "adult"
+	Youth   Category = "youth"
+	Student Category = "student"
+)
+
+// Party is who travels: Youth (under 26) and Students are counted apart
+// from Adults because they may travel at a discount. Passes are held by one
+// of them, the traveller searching.
+type Party struct {
+	Adults   int
+	Youth    int
+	Students int
+	Passes   []Pass
+}
+
+// Size is the number of travellers, at least one.
+func (p Party) Size() int { return max(p.Adults+p.Youth+p.Students, 1) }
+
+func (p Party) counts() map[Category]int {
+	counts := map[Category]int{Adult: p.Adults, Youth: p.Youth, Student: p.Students}
+	if p.Adults+p.Youth+p.Students <= 0 {
+		counts[Adult] = 1
+	}
+	return counts
+}
+
+// Journey is what gets priced. Legs are used when there are any; otherwise
+// the journey is priced as a single ride of Mode lasting Hours. Date
+// (YYYY-MM-DD) decides which passes are valid and defaults to the first
+// leg's.
+type Journey struct {
+	Provider   string
+	Country    string
+	Mode       string
+	Hours      float64
+	DistanceKm float64
+	Date       string
+	Legs       []domain.TransportLeg
+}
+
+func (j Journey) date() string {
+	if j.Date != "" {
+		return j.Date
+	}
+	for _, leg := range j.Legs {
+		if t, err := time.Parse(time.RFC3339, leg.From.Time); err == nil {
+			return t.Format(passDateLayout)
+		}
+	}
+	return ""
+}
+
+// operator is who runs the journey's first ride, which decides discounts.
+func (j Journey) operator() string {
+	for _, leg := range j.Legs {
+		if leg.Kind == domain.LegRide && leg.Operator != "" {
+			return leg.Operator
+		}
+	}
+	return ""
+}
+
+// Fare is a price for one adult or, from Model.Price, for the whole party.
+// Passes names the passes that lowered it and Covers the rides they paid
+// for.
+type Fare struct {
+	Amount money.Amount
+	Kind   domain.PriceKind
+	Source string
+	Passes []string
+	Covers []LegCover
+}
+
+type Source interface {
+	Fare(ctx context.Context, j Journey) (Fare, error)
+}
+
+// Model prices journeys for the party travelling.
+type Model struct {
+	table   *Table
+	sources map[string][]Source
+}
+
+func NewModel(table *Table) *Model {
+	return &Model{table: table, sources: map[string][]Source{}}
+}
+
+// Register adds s to the sources tried, in registration order, for
+// journeys found by provider.
+func (m *Model) Register(provider string, s Source) {
+	key := strings.ToLower(provider)
+	m.sources[key] = append(m.sources[key], s)
+}
+
+// Price asks the provider's sources for a fare and estimates one from the
+// table when none has it, then prices it for the party with PriceFrom. It
+// reports false only when nothing could price the journey.
+func (m *Model) Price(ctx context.Context, j Journey, party Party) (Fare, bool) {
+	fare, ok := Fare{}, false
+	for _, source := range m.sources[strings.ToLower(j.Provider)] {
+		if f, err := source.Fare(ctx, j); err == nil {
+			fare, ok = f, true
+			break
+		}
+	}
+	if !ok && m.table != nil {
+		if f, err := m.table.Fare(ctx, j); err == nil {
+			fare, ok = f, true
+		}
+	}
+	if !ok {
+		return Fare{}, false
+	}
+	return m.PriceFrom(j, fare, party), true
+}
+
+// PriceFrom prices j for the party from its adult fare, with the table's
+// discounts for each category and the pass holder's passes for their seat.
+func (m *Model) PriceFrom(j Journey, adult Fare, party Party) Fare {
+	counts := party.counts()
+	fare := Fare{Kind: adult.Kind, Source: adult.Source}
+	if len(party.Passes) > 0 {
+		category, student := Adult, ""
+		for _, p := range party.Passes {
+			if p.Student && p.validOn(j.date()) {
+				category, student = Student, p.Name
+				break
+			}
+		}
+		// The holder is one of the party, counted wherever there is room.
+		from := category
+		for _, c := range []Category{category, Adult, Youth, Student} {
+			if counts[c] > 0 {
+				from = c
+				break
+			}
+		}
+		if student == "" {
+			category = from
+		}
+		counts[from]--
+		holder := m.holderFare(adult, category, j, party.Passes)
+		fare.Amount, fare.Passes, fare.Covers = holder.Amount, holder.Passes, holder.Covers
+		if student != "" {
+			fare.Passes = append([]string{student}, fare.Passes...)
+		}
+	}
+	for category, n := range counts {
+		if n <= 0 {
+			continue
+		}
+		each := adult.Amount
+		if m.table != nil {
+			each = m.table.discounted(each, category, j)
+		}
+		fare.Amount += each.Mul(float64(n))
+	}
+	return fare
+}
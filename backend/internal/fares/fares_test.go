@@ -0,0 +1,181 @@
+package fares
+
+import (
+	"context"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const testTable = `{
+	"rules": [
+		{"mode": "train", "base": 4, "perKm": 0.10, "perHour": 12, "min": 5},
+		{"country": "CH", "mode": "train", "base": 3, "perKm": 0.30, "perHour": 20, "min": 3},
+		{"operator": "DB", "base": 5, "perKm": 0.20, "perHour": 20, "min": 5}
+	],
+	"discounts": [
+		{"category": "youth", "percent": 20},
+		{"category": "youth", "operator": "DB", "percent": 50},
+		{"category": "student", "country": "CH", "percent": 10}
+	]
+}`
+
+type quoted money.Amount
+
+func (q quoted) Fare(context.Context, Journey) (Fare, error) {
+	return Fare{Amount: money.Amount(q), Kind: domain.PriceQuoted, Source: "operator"}, nil
+}
+
+type noFare struct{}
+
+func (noFare) Fare(context.Context, Journey) (Fare, error) { return Fare{}, ErrNoFare }
+
+func table(t *testing.T) *Table {
+	t.Helper()
+	tbl, err := LoadTable([]byte(testTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl
+}
+
+func TestTableFare_PricesEachRideByTheMostSpecificRule(t *testing.T) {
+	tbl := table(t)
+	j := Journey{Country: "CH", Legs: []domain.TransportLeg{
+		{Kind: domain.LegRide, Mode: "train", Operator: "DB", DistanceKm: 100},
+		{Kind: domain.LegWalk, WalkMinutes: 5},
+		{Kind: domain.LegRide, Mode: "train", DistanceKm: 50},
+		{Kind: domain.LegRide, Mode: "train", From: domain.LegStop{Time: "2026-03-06T10:00:00+01:00"}, To: domain.LegStop{Time: "2026-03-06T10:30:00+01:00"}},
+	}}
+	fare, err := tbl.Fare(context.Background(), j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// DB 5+20 = 25; Swiss train 3+15 = 18; half an hour 3+10 = 13.
+	if fare.Amount != money.FromFloat(56) || fare.Kind != domain.PriceEstimated || fare.Source != TableSource {
+		t.Fatalf("unexpected fare %+v", fare)
+	}
+
+	j.Country = "AT"
+	j.Legs = []domain.TransportLeg{{Kind: domain.LegRide, Mode: "train", DistanceKm: 5}}
+	if fare, _ := tbl.Fare(context.Background(), j); fare.Amount != money.FromFloat(5) {
+		t.Fatalf("expected the minimum fare, got %s", fare.Amount)
+	}
+}
+
+func TestTableFare_FallsBackToTheWholeJourney(t *testing.T) {
+	tbl := table(t)
+	// The ride has no distance or times, so the journey's hours are used.
+	j := Journey{Mode: "train", Hours: 2, Legs: []domain.TransportLeg{{Kind: domain.LegRide, Mode: "train"}}}
+	fare, err := tbl.Fare(context.Background(), j)
+	if err != nil || fare.Amount != money.FromFloat(28) {
+		t.Fatalf("expected 4 + 2h*12, got %+v, %v", fare, err)
+	}
+	if _, err := tbl.Fare(context.Background(), Journey{Mode: "flight", Hours: 2}); err == nil {
+		t.Fatal("expected no fare without a matching rule")
+	}
+}
+
+func TestModelPrice_SourcesThenTableThenDiscounts(t *testing.T) {
+	m := NewModel(table(t))
+	m.Register("rail", noFare{})
+	m.Register("rail", quoted(money.FromFloat(40)))
+	j := Journey{Provider: "Rail", Country: "CH", Legs: []domain.TransportLeg{{Kind: domain.LegRide, Mode: "train", Operator: "DB", DistanceKm: 100}}}
+
+	fare, ok := m.Price(context.Background(), j, Party{Adults: 1, Youth: 1, Students: 1})
+	// 40 + 50% off with DB for the youth + 10% off in CH for the student.
+	if !ok || fare.Kind != domain.PriceQuoted || fare.Amount != money.FromFloat(40+20+36) {
+		t.Fatalf("unexpected quoted fare %+v", fare)
+	}
+
+	j.Provider = "bus"
+	fare, ok = m.Price(context.Background(), j, Party{})
+	if !ok || fare.Kind != domain.PriceEstimated || fare.Amount != money.FromFloat(25) {
+		t.Fatalf("expected a single adult's estimated fare, got %+v", fare)
+	}
+}
+
+func TestLoadTable_RejectsBadAmounts(t *testing.T) {
+	for _, data := range []string{`{"rules":[{"base":-1}]}`, `{"discounts":[{"category":"youth","percent":120}]}`, `not json`} {
+		if _, err := LoadTable([]byte(data)); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+	if len(DefaultTable().Rules) == 0 {
+		t.Fatal("expected the bundled table to have rules")
+	}
+}
+
+func TestStraightLineKm(t *testing.T) {
+	// Zurich HB to Bern, about 95 km apart.
+	if km := StraightLineKm(47.378, 8.540, 46.949, 7.439); km < 90 || km > 100 {
+		t.Fatalf("unexpected distance %.1f", km)
+	}
+}
+
+func TestPriceFrom_PassesCoverAndDiscountTheHoldersRides(t *testing.T) {
+	tbl, err := LoadTable([]byte(`{
+		"rules": [
+			{"mode": "train", "perKm": 0.20, "reservation": 5},
+			{"mode": "bus", "perKm": 0.20}
+		],
+		"discounts": [{"category": "student", "percent": 10}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(tbl)
+	interrail, _ := PassFor(domain.TravelPass{Type: "interrail"})
+	halfFare, _ := PassFor(domain.TravelPass{Type: "swiss-half-fare"})
+	j := Journey{Country: "CH", Legs: []domain.TransportLeg{
+		{Kind: domain.LegRide, Mode: "train", DistanceKm: 100, From: domain.LegStop{Time: "2026-03-06T08:00:00+01:00"}},
+		{Kind: domain.LegRide, Mode: "bus", DistanceKm: 50},
+	}}
+	adult := Fare{Amount: money.FromFloat(30), Kind: domain.PriceEstimated}
+
+	// The holder pays the train's reservation and half the bus; the other
+	// adult pays in full.
+	fare := m.PriceFrom(j, adult, Party{Adults: 2, Passes: []Pass{interrail, halfFare}})
+	if fare.Amount != money.FromFloat(40) || len(fare.Passes) != 2 {
+		t.Fatalf("unexpected fare %+v", fare)
+	}
+	if len(fare.Covers) != 1 || fare.Covers[0].Leg != 0 || fare.Covers[0].Reservation != money.FromFloat(5) {
+		t.Fatalf("expected the train to be covered, got %+v", fare.Covers)
+	}
+	fare.Annotate(j.Legs)
+	if j.Legs[0].CoveredBy != "Interrail Pass" || j.Legs[1].CoveredBy != "" {
+		t.Fatalf("unexpected legs %+v", j.Legs)
+	}
+
+	// An expired pass no longer covers the train, so half fare is used.
+	interrail.Until = "2026-03-01"
+	if fare := m.PriceFrom(j, adult, Party{Adults: 2, Passes: []Pass{interrail, halfFare}}); fare.Amount != money.FromFloat(45) {
+		t.Fatalf("expected the expired pass to be ignored, got %+v", fare)
+	}
+
+	// ISIC makes its holder a student.
+	isic, _ := PassFor(domain.TravelPass{Type: "isic"})
+	if fare := m.PriceFrom(j, adult, Party{Adults: 1, Passes: []Pass{isic}}); fare.Amount != money.FromFloat(27) || fare.Passes[0] != "ISIC" {
+		t.Fatalf("expected a student fare, got %+v", fare)
+	}
+}
+
+func TestPassFor_Validates(t *testing.T) {
+	for _, tp := range []domain.TravelPass{
+		{Type: "golden-ticket"},
+		{Type: "custom", Percent: 20},
+		{Type: "custom", Name: "Uni card"},
+		{Type: "bahncard-25", Percent: 150},
+		{Type: "interrail", ValidFrom: "2026-05-01", ValidUntil: "2026-04-01"},
+		{Type: "interrail", ValidFrom: "01.05.2026"},
+	} {
+		if _, err := PassFor(tp); err == nil {
+			t.Fatalf("expected %+v to be rejected", tp)
+		}
+	}
+	p, err := PassFor(domain.TravelPass{Type: "Interrail", Countries: []string{"it"}})
+	if err != nil || !p.Covers || len(p.Countries) != 1 || p.Countries[0] != "IT" {
+		t.Fatalf("expected a one-country Interrail pass, got %+v, %v", p, err)
+	}
+}
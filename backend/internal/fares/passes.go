@@ -0,0 +1,237 @@
+package fares
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const passDateLayout = "2006-01-02"
+
+// Pass is a rail pass or discount card held by the traveller searching.
+// Empty Countries, Operators and Modes match any ride. A pass that Covers a
+// ride leaves only its seat reservation to pay; otherwise Percent comes off
+// the ride's adult fare. Student passes make their holder travel at student
+// prices. From and Until are inclusive YYYY-MM-DD dates, empty when open.
+type Pass struct {
+	Name      string
+	Countries []string
+	Operators []string
+	Modes     []string
+	Covers    bool
+	Percent   float64
+	Student   bool
+	From      string
+	Until     string
+}
+
+// passTypes are the passes a profile can name by type.
+var passTypes = map[string]Pass{
+	"interrail":       {Name: "Interrail Pass", Modes: []string{"train"}, Covers: true},
+	"eurail":          {Name: "Eurail Pass", Modes: []string{"train"}, Covers: true},
+	"swiss-ga":        {Name: "GA travelcard", Countries: []string{"CH"}, Covers: true},
+	"swiss-half-fare": {Name: "Half Fare travelcard", Countries: []string{"CH"}, Percent: 50},
+	"bahncard-25":     {Name: "BahnCard 25", Operators: []string{"DB"}, Percent: 25},
+	"bahncard-50":     {Name: "BahnCard 50", Operators: []string{"DB"}, Percent: 50},
+	"railcard-16-25":  {Name: "16-25 Railcard", Countries: []string{"GB"}, Modes: []string{"train"}, Percent: 34},
+	"isic":            {Name: "ISIC", Student: true},
+}
+
+// customPass is the type of passes described entirely by the profile.
+const customPass = "custom"
+
+// PassTypes lists the pass types a profile can name, "custom" included.
+func PassTypes() []string {
+	types := []string{customPass}
+	for t := range passTypes {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// PassFor resolves a profile's pass against its type. Countries, operators
+// and modes given on the profile replace the type's, so an Interrail One
+// Country pass is "interrail" with one country.
+func PassFor(tp domain.TravelPass) (Pass, error) {
+	kind := strings.ToLower(strings.TrimSpace(tp.Type))
+	p, ok := passTypes[kind]
+	if !ok && kind != customPass {
+		return Pass{}, fmt.Errorf("unknown pass type %q, expected one of %s", tp.Type, strings.Join(PassTypes(), ", "))
+	}
+	if tp.Name != "" {
+		p.Name = tp.Name
+	}
+	if len(tp.Countries) > 0 {
+		p.Countries = upper(tp.Countries)
+	}
+	if len(tp.Operators) > 0 {
+		p.Operators = tp.Operators
+	}
+	if len(tp.Modes) > 0 {
+		p.Modes = tp.Modes
+	}
+	if tp.Covers {
+		p.Covers = true
+	}
+	if tp.Percent != 0 {
+		p.Percent = tp.Percent
+	}
+	if p.Percent < 0 || p.Percent > 100 {
+		return Pass{}, fmt.Errorf("pass percent must be between 0 and 100")
+	}
+	if kind == customPass && (p.Name == "" || (!p.Covers && p.Percent == 0)) {
+		return Pass{}, fmt.Errorf("custom passes need a name and either covers or a percent")
+	}
+	for _, date := range []string{tp.ValidFrom, tp.ValidUntil} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(passDateLayout, date); err != nil {
+			return Pass{}, fmt.Errorf("pass dates must be YYYY-MM-DD")
+		}
+	}
+	if tp.ValidFrom != "" && tp.ValidUntil != "" && tp.ValidUntil < tp.ValidFrom {
+		return Pass{}, fmt.Errorf("pass validUntil is before validFrom")
+	}
+	p.From, p.Until = tp.ValidFrom, tp.ValidUntil
+	return p, nil
+}
+
+// Passes resolves a profile's passes, leaving out any that do not resolve.
+func Passes(tps []domain.TravelPass) []Pass {
+	var out []Pass
+	for _, tp := range tps {
+		if p, err := PassFor(tp); err == nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func upper(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToUpper(strings.TrimSpace(v))
+	}
+	return out
+}
+
+// validOn reports whether the pass can be used on date; journeys of unknown
+// date are taken to be within it.
+func (p Pass) validOn(date string) bool {
+	return date == "" || ((p.From == "" || date >= p.From) && (p.Until == "" || date <= p.Until))
+}
+
+func (p Pass) applies(country string, r ride) bool {
+	matches := func(values []string, got string) bool {
+		return len(values) == 0 || slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, got) })
+	}
+	return matches(p.Countries, country) && matches(p.Operators, r.operator) && matches(p.Modes, r.mode)
+}
+
+// LegCover is a ride of a journey, by index into its legs, that a pass
+// pays for.
+type LegCover struct {
+	Leg         int
+	Pass        string
+	Reservation money.Amount
+}
+
+// Annotate marks the legs the fare's passes cover.
+func (f Fare) Annotate(legs []domain.TransportLeg) {
+	for _, c := range f.Covers {
+		if c.Leg >= 0 && c.Leg < len(legs) {
+			legs[c.Leg].CoveredBy, legs[c.Leg].ReservationFee = c.Pass, c.Reservation
+		}
+	}
+}
+
+// holderFare prices the pass holder's seat on j from its adult fare, ride
+// by ride: each ride costs the cheapest of its usual fare for category, its
+// fare with a card's discount, or its reservation fee when a pass covers
+// it. Rides share the fare in proportion to what the table would charge.
+func (m *Model) holderFare(fare Fare, category Category, j Journey, passes []Pass) Fare {
+	type part struct {
+		leg int
+		ride
+	}
+	var parts []part
+	for i, leg := range j.Legs {
+		if leg.Kind == domain.LegRide {
+			parts = append(parts, part{leg: i, ride: ride{operator: leg.Operator, mode: leg.Mode, km: leg.DistanceKm, hours: legHours(leg)}})
+		}
+	}
+	if len(parts) == 0 {
+		parts = []part{{leg: -1, ride: ride{operator: j.operator(), mode: j.Mode, km: j.DistanceKm, hours: j.Hours}}}
+	}
+	weights := make([]money.Amount, len(parts))
+	total := money.Amount(0)
+	for i, p := range parts {
+		if m.table != nil {
+			if w, err := m.table.price(j.Country, []ride{p.ride}); err == nil {
+				weights[i] = w
+			}
+		}
+		if weights[i] <= 0 {
+			// Without a price for every ride, they share the fare equally.
+			weights, total = nil, 0
+			break
+		}
+		total += weights[i]
+	}
+
+	date := j.date()
+	out := Fare{Kind: fare.Kind, Source: fare.Source}
+	left := fare.Amount
+	for i, p := range parts {
+		share := left
+		if i < len(parts)-1 {
+			if total > 0 {
+				share = fare.Amount.Mul(float64(weights[i]) / float64(total))
+			} else {
+				share = fare.Amount.Mul(1 / float64(len(parts)))
+			}
+			left -= share
+		}
+		best, used, covers := share, "", false
+		if m.table != nil {
+			best = m.table.discounted(share, category, j)
+		}
+		for _, pass := range passes {
+			if !pass.validOn(date) || !pass.applies(j.Country, p.ride) {
+				continue
+			}
+			cost := share.Mul(1 - pass.Percent/100)
+			if pass.Covers {
+				cost = m.reservation(j.Country, p.ride)
+			}
+			if (pass.Covers || pass.Percent > 0) && cost < best {
+				best, used, covers = cost, pass.Name, pass.Covers
+			}
+		}
+		out.Amount += best
+		if used != "" && !slices.Contains(out.Passes, used) {
+			out.Passes = append(out.Passes, used)
+		}
+		if covers {
+			out.Covers = append(out.Covers, LegCover{Leg: p.leg, Pass: used, Reservation: best})
+		}
+	}
+	return out
+}
+
+func (m *Model) reservation(country string, r ride) money.Amount {
+	if m.table == nil {
+		return 0
+	}
+	rule, ok := m.table.match(country, r.operator, r.mode)
+	if !ok {
+		return 0
+	}
+	return rule.Reservation
+}
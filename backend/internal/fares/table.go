@@ -0,0 +1,206 @@
+package fares
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// TableSource is how estimated fares are labelled.
+const TableSource = "fare-table"
+
+//go:embed default_table.json
+var defaultTable []byte
+
+// Rule prices one ride. Empty Country, Operator and Mode match anything;
+// the most specific matching rule wins, operator before country before mode.
+// A ride costs Base plus PerKm of its distance or, when the distance is not
+// known, PerHour of its duration, and never less than Min. Reservation is
+// what a seat costs on a ride a pass covers. Amounts are EUR.
+type Rule struct {
+	Country     string       `json:"country"`
+	Operator    string       `json:"operator"`
+	Mode        string       `json:"mode"`
+	Base        money.Amount `json:"base"`
+	PerKm       money.Amount `json:"perKm"`
+	PerHour     money.Amount `json:"perHour"`
+	Min         money.Amount `json:"min"`
+	Reservation money.Amount `json:"reservation"`
+}
+
+// Discount takes Percent off the fare for a Category, on journeys run by
+// Operator or in Country when those are set.
+type Discount struct {
+	Category Category `json:"category"`
+	Country  string   `json:"country"`
+	Operator string   `json:"operator"`
+	Percent  float64  `json:"percent"`
+}
+
+// Table estimates fares for providers that publish none.
+type Table struct {
+	Rules     []Rule     `json:"rules"`
+	Discounts []Discount `json:"discounts"`
+}
+
+func LoadTable(data []byte) (*Table, error) {
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("decode fare table: %w", err)
+	}
+	for i, r := range t.Rules {
+		if r.Base < 0 || r.PerKm < 0 || r.PerHour < 0 || r.Min < 0 || r.Reservation < 0 {
+			return nil, fmt.Errorf("fare rule %d has a negative amount", i)
+		}
+	}
+	for i, d := range t.Discounts {
+		if d.Percent < 0 || d.Percent > 100 {
+			return nil, fmt.Errorf("fare discount %d must be between 0 and 100 percent", i)
+		}
+	}
+	return &t, nil
+}
+
+// TableFromEnv loads the table in FARE_TABLE_FILE, or the bundled one.
+func TableFromEnv() (*Table, error) {
+	if path := strings.TrimSpace(os.Getenv("FARE_TABLE_FILE")); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read fare table: %w", err)
+		}
+		return LoadTable(data)
+	}
+	return DefaultTable(), nil
+}
+
+// DefaultTable is the bundled table of rough European fares.
+func DefaultTable() *Table {
+	t, err := LoadTable(defaultTable)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+type ride struct {
+	operator, mode string
+	km, hours      float64
+}
+
+// Fare estimates the adult fare as the sum of the journey's rides, or as a
+// single ride when its rides cannot each be priced.
+func (t *Table) Fare(_ context.Context, j Journey) (Fare, error) {
+	var rides []ride
+	for _, leg := range j.Legs {
+		if leg.Kind == domain.LegRide {
+			rides = append(rides, ride{operator: leg.Operator, mode: leg.Mode, km: leg.DistanceKm, hours: legHours(leg)})
+		}
+	}
+	total, err := t.price(j.Country, rides)
+	if len(rides) == 0 || err != nil {
+		total, err = t.price(j.Country, []ride{{operator: j.operator(), mode: j.Mode, km: j.DistanceKm, hours: j.Hours}})
+	}
+	if err != nil {
+		return Fare{}, err
+	}
+	return Fare{Amount: total, Kind: domain.PriceEstimated, Source: TableSource}, nil
+}
+
+func (t *Table) price(country string, rides []ride) (money.Amount, error) {
+	total := money.Amount(0)
+	for _, r := range rides {
+		rule, ok := t.match(country, r.operator, r.mode)
+		if !ok {
+			return 0, fmt.Errorf("%w: no fare rule for %s %s", ErrNoFare, r.operator, r.mode)
+		}
+		var price money.Amount
+		switch {
+		case r.km > 0:
+			price = rule.Base + rule.PerKm.Mul(r.km)
+		case r.hours > 0:
+			price = rule.Base + rule.PerHour.Mul(r.hours)
+		default:
+			return 0, fmt.Errorf("%w: ride has neither distance nor duration", ErrNoFare)
+		}
+		total += max(price, rule.Min)
+	}
+	return total, nil
+}
+
+func (t *Table) match(country, operator, mode string) (Rule, bool) {
+	best, bestScore := Rule{}, -1
+	for _, r := range t.Rules {
+		score, ok := 0, true
+		for _, f := range []struct {
+			want, got string
+			weight    int
+		}{{r.Operator, operator, 4}, {r.Country, country, 2}, {r.Mode, mode, 1}} {
+			if f.want == "" {
+				continue
+			}
+			if !strings.EqualFold(f.want, f.got) {
+				ok = false
+				break
+			}
+			score += f.weight
+		}
+		if ok && score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// discounted applies the most specific discount for category on j.
+func (t *Table) discounted(fare money.Amount, category Category, j Journey) money.Amount {
+	operator := j.operator()
+	best, bestScore := -1.0, -1
+	for _, d := range t.Discounts {
+		if d.Category != category {
+			continue
+		}
+		if (d.Operator != "" && !strings.EqualFold(d.Operator, operator)) || (d.Country != "" && !strings.EqualFold(d.Country, j.Country)) {
+			continue
+		}
+		score := 0
+		if d.Operator != "" {
+			score += 2
+		}
+		if d.Country != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = d.Percent, score
+		}
+	}
+	if best <= 0 {
+		return fare
+	}
+	return fare.Mul(1 - best/100)
+}
+
+func legHours(leg domain.TransportLeg) float64 {
+	from, err1 := time.Parse(time.RFC3339, leg.From.Time)
+	to, err2 := time.Parse(time.RFC3339, leg.To.Time)
+	if err1 != nil || err2 != nil || !to.After(from) {
+		return 0
+	}
+	return to.Sub(from).Hours()
+}
+
+// StraightLineKm is the great-circle distance between two WGS84 points.
+func StraightLineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := rad(lat2-lat1), rad(lon2-lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
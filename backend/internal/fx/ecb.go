@@ -0,0 +1,122 @@
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+// LoadFile reads an ECB reference-rate file, picking the parser from the
+// extension (.csv or .xml).
+func LoadFile(path string) ([]domain.FXRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open rates file: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseECBCSV(f)
+	case ".xml":
+		return ParseECBXML(f)
+	default:
+		return nil, fmt.Errorf("unsupported rates file %q (want .csv or .xml)", path)
+	}
+}
+
+// ParseECBCSV parses the eurofxref CSV layout: a "Date,USD,JPY,..." header
+// followed by one row per day. "N/A" cells and trailing empty columns are skipped.
+func ParseECBCSV(r io.Reader) ([]domain.FXRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	if len(header) == 0 || !strings.EqualFold(strings.TrimSpace(header[0]), "Date") {
+		return nil, fmt.Errorf("csv header must start with Date")
+	}
+
+	var rates []domain.FXRate
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv row: %w", err)
+		}
+		date, err := normalizeDate(row[0])
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(row) && i < len(header); i++ {
+			cur := Normalize(header[i])
+			raw := strings.TrimSpace(row[i])
+			if header[i] == "" || raw == "" || strings.EqualFold(raw, "N/A") {
+				continue
+			}
+			rate, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s rate on %s: %w", cur, date, err)
+			}
+			rates = append(rates, domain.FXRate{Date: date, Currency: cur, Rate: rate})
+		}
+	}
+	return rates, nil
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML parses the gesmes eurofxref XML layout (daily, 90-day or historic).
+func ParseECBXML(r io.Reader) ([]domain.FXRate, error) {
+	var env ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("decode xml: %w", err)
+	}
+	var rates []domain.FXRate
+	for _, day := range env.Days {
+		date, err := normalizeDate(day.Time)
+		if err != nil {
+			return nil, err
+		}
+		for _, cube := range day.Rates {
+			rate, err := strconv.ParseFloat(strings.TrimSpace(cube.Rate), 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s rate on %s: %w", cube.Currency, date, err)
+			}
+			rates = append(rates, domain.FXRate{Date: date, Currency: Normalize(cube.Currency), Rate: rate})
+		}
+	}
+	return rates, nil
+}
+
+// normalizeDate accepts ISO dates and the "02 January 2006" form used by older ECB files.
+func normalizeDate(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range []string{"2006-01-02", "02 January 2006", "2 January 2006"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid rate date %q", raw)
+}
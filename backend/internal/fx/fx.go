@@ -0,0 +1,140 @@
+// Package fx converts amounts between currencies using date-stamped
+// EUR-based reference rates, as published by the ECB.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// Base is the currency every rate is quoted against.
+const Base = "EUR"
+
+var ErrNoRate = errors.New("no exchange rate")
+
+type datedRate struct {
+	date string
+	rate float64
+}
+
+// Table answers conversions from a set of rates. It is immutable once built
+// and safe for concurrent use.
+type Table struct {
+	rates map[string][]datedRate // currency -> rates sorted by date
+}
+
+// NewTable indexes rates by currency and date. Later duplicates win.
+func NewTable(rates []domain.FXRate) *Table {
+	byCurrency := map[string]map[string]float64{}
+	for _, r := range rates {
+		cur := Normalize(r.Currency)
+		if cur == "" || cur == Base || r.Rate <= 0 {
+			continue
+		}
+		if byCurrency[cur] == nil {
+			byCurrency[cur] = map[string]float64{}
+		}
+		byCurrency[cur][r.Date] = r.Rate
+	}
+	t := &Table{rates: make(map[string][]datedRate, len(byCurrency))}
+	for cur, dates := range byCurrency {
+		series := make([]datedRate, 0, len(dates))
+		for date, rate := range dates {
+			series = append(series, datedRate{date: date, rate: rate})
+		}
+		sort.Slice(series, func(i, j int) bool { return series[i].date < series[j].date })
+		t.rates[cur] = series
+	}
+	return t
+}
+
+// Normalize upper-cases a currency code, defaulting empty codes to EUR.
+func Normalize(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return Base
+	}
+	return currency
+}
+
+// Rate returns units of currency per EUR effective on date: the latest rate
+// published on or before date, or the earliest known rate for dates that
+// predate the table.
+func (t *Table) Rate(currency, date string) (float64, error) {
+	currency = Normalize(currency)
+	if currency == Base {
+		return 1, nil
+	}
+	series := t.rates[currency]
+	if len(series) == 0 {
+		return 0, fmt.Errorf("%w for %s", ErrNoRate, currency)
+	}
+	if date == "" {
+		return series[len(series)-1].rate, nil
+	}
+	i := sort.Search(len(series), func(i int) bool { return series[i].date > date })
+	if i == 0 {
+		return series[0].rate, nil
+	}
+	return series[i-1].rate, nil
+}
+
+// Convert converts amount from one currency to another at the rate effective on date.
+func (t *Table) Convert(amount float64, from, to, date string) (float64, error) {
+	from, to = Normalize(from), Normalize(to)
+	if from == to {
+		return amount, nil
+	}
+	fromRate, err := t.Rate(from, date)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := t.Rate(to, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount / fromRate * toRate, nil
+}
+
+// ConvertAmount converts an exact amount, rounding the result to the cent.
+func (t *Table) ConvertAmount(amount money.Amount, from, to, date string) (money.Amount, error) {
+	if Normalize(from) == Normalize(to) {
+		return amount, nil
+	}
+	converted, err := t.Convert(amount.Float(), from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return money.FromFloat(converted), nil
+}
+
+// ConvertMoney converts m into currency to at the rate effective on date.
+func (t *Table) ConvertMoney(m money.Money, to, date string) (money.Money, error) {
+	amount, err := t.ConvertAmount(m.Amount, m.Currency, to, date)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.New(amount, Normalize(to)), nil
+}
+
+// Effective returns one rate per currency as of date.
+func (t *Table) Effective(date string) []domain.FXRate {
+	out := make([]domain.FXRate, 0, len(t.rates))
+	for cur, series := range t.rates {
+		i := len(series) - 1
+		if date != "" {
+			i = sort.Search(len(series), func(i int) bool { return series[i].date > date }) - 1
+			if i < 0 {
+				i = 0
+			}
+		}
+		out = append(out, domain.FXRate{Date: series[i].date, Currency: cur, Rate: series[i].rate})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Currency < out[j].Currency })
+	return out
+}
@@ -0,0 +1,91 @@
+package fx
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func TestLoadFile_CSV(t *testing.T) {
+	rates, err := LoadFile("testdata/eurofxref-hist.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2 days x 5 currencies; ISK is N/A and the trailing column is empty.
+	if len(rates) != 10 {
+		t.Fatalf("expected 10 rates, got %d", len(rates))
+	}
+	if rates[0].Date != "2026-02-03" || rates[0].Currency != "USD" || rates[0].Rate != 1.079 {
+		t.Fatalf("unexpected first rate %+v", rates[0])
+	}
+}
+
+func TestLoadFile_XML(t *testing.T) {
+	rates, err := LoadFile("testdata/eurofxref-daily.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("expected 2 rates, got %d", len(rates))
+	}
+	if rates[1].Currency != "CZK" || rates[1].Rate != 25.12 || rates[1].Date != "2026-02-03" {
+		t.Fatalf("unexpected rate %+v", rates[1])
+	}
+}
+
+func TestLoadFile_UnsupportedExtension(t *testing.T) {
+	if _, err := LoadFile("testdata/rates.json"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestConvert_UsesLatestRateOnOrBeforeDate(t *testing.T) {
+	table := NewTable([]domain.FXRate{
+		{Date: "2026-02-02", Currency: "CZK", Rate: 25},
+		{Date: "2026-02-05", Currency: "CZK", Rate: 20},
+	})
+	cases := []struct {
+		date string
+		want float64
+	}{
+		{date: "2026-02-01", want: 80}, // before the table: earliest rate
+		{date: "2026-02-04", want: 80},
+		{date: "2026-02-05", want: 100},
+		{date: "", want: 100},
+	}
+	for _, tc := range cases {
+		got, err := table.Convert(2000, "czk", "EUR", tc.date)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Fatalf("Convert on %q = %v, want %v", tc.date, got, tc.want)
+		}
+	}
+}
+
+func TestConvert_CrossRate(t *testing.T) {
+	table := NewTable([]domain.FXRate{
+		{Date: "2026-02-02", Currency: "CZK", Rate: 25},
+		{Date: "2026-02-02", Currency: "PLN", Rate: 4},
+	})
+	got, err := table.Convert(100, "PLN", "CZK", "2026-02-02")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(got-625) > 1e-9 {
+		t.Fatalf("expected 625, got %v", got)
+	}
+}
+
+func TestConvert_UnknownCurrency(t *testing.T) {
+	table := NewTable(nil)
+	if _, err := table.Convert(1, "XYZ", "EUR", ""); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("expected ErrNoRate, got %v", err)
+	}
+	if got, err := table.Convert(7, "", "eur", ""); err != nil || got != 7 {
+		t.Fatalf("EUR to EUR should be identity, got %v, %v", got, err)
+	}
+}
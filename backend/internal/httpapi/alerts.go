@@ -0,0 +1,191 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/budget"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+)
+
+const maxAlertRules = 50
+
+// checkAlerts evaluates the user's rules after entries were added or tripID
+// was planned, and records the alerts that newly triggered.
+func (s *Server) checkAlerts(userID string, entries []domain.BudgetEntry, tripID string) []domain.Alert {
+	rules := s.store.ListAlertRules(userID)
+	if len(rules) == 0 {
+		return nil
+	}
+	in := budget.AlertInput{
+		UserID:   userID,
+		Rules:    rules,
+		Forecast: s.store.Forecast(userID, "", ""),
+		TripID:   tripID,
+		Entries:  entries,
+		Rates:    s.rates(),
+		Now:      time.Now(),
+	}
+	if tripID != "" {
+		withTrip := s.store.Forecast(userID, tripID, "")
+		in.WithTrip = &withTrip
+	}
+	alerts := budget.EvaluateAlerts(in)
+	if len(alerts) == 0 {
+		return nil
+	}
+	return s.store.RecordAlerts(alerts)
+}
+
+// checkTripAlerts evaluates every member's rules once a trip is planned.
+func (s *Server) checkTripAlerts(trip *domain.Trip) {
+	for _, member := range trip.Members {
+		s.checkAlerts(member, nil, trip.ID)
+	}
+}
+
+// handleAlerts serves GET /api/alerts[?unread=true].
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	userID := auth.UserIDFromContext(r.Context())
+	alerts := s.store.ListAlerts(userID, r.URL.Query().Get("unread") == "true")
+	unread := 0
+	for _, alert := range alerts {
+		if !alert.Read {
+			unread++
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"alerts": alerts, "unread": unread})
+}
+
+// handleAlertRoutes serves POST /api/alerts/read, GET and POST on
+// /api/alerts/rules, and PUT and DELETE on /api/alerts/rules/{id}.
+func (s *Server) handleAlertRoutes(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/alerts/"), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "read":
+		if r.Method != http.MethodPost {
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var req struct {
+			IDs []string `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"marked": s.store.MarkAlertsRead(userID, req.IDs)})
+	case len(parts) == 1 && parts[0] == "rules":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{"rules": s.store.ListAlertRules(userID)})
+		case http.MethodPost:
+			rule, ok := s.decodeAlertRule(w, r, userID)
+			if !ok {
+				return
+			}
+			if len(s.store.ListAlertRules(userID)) >= maxAlertRules {
+				writeErr(w, http.StatusBadRequest, "too many alert rules")
+				return
+			}
+			saved, err := s.store.SaveAlertRule(rule)
+			if err != nil {
+				writeStoreErr(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, saved)
+		default:
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(parts) == 2 && parts[0] == "rules" && parts[1] != "":
+		switch r.Method {
+		case http.MethodPut:
+			rule, ok := s.decodeAlertRule(w, r, userID)
+			if !ok {
+				return
+			}
+			rule.ID = parts[1]
+			saved, err := s.store.SaveAlertRule(rule)
+			if err != nil {
+				writeStoreErr(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, saved)
+		case http.MethodDelete:
+			if err := s.store.DeleteAlertRule(userID, parts[1]); err != nil {
+				writeStoreErr(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	default:
+		writeErr(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) decodeAlertRule(w http.ResponseWriter, r *http.Request, userID string) (domain.AlertRule, bool) {
+	var rule domain.AlertRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return rule, false
+	}
+	rule.ID, rule.UserID = "", userID
+	if msg := s.validateAlertRule(&rule); msg != "" {
+		writeErr(w, http.StatusBadRequest, msg)
+		return rule, false
+	}
+	return rule, true
+}
+
+func (s *Server) validateAlertRule(rule *domain.AlertRule) string {
+	rule.Category = strings.TrimSpace(rule.Category)
+	switch rule.Kind {
+	case domain.AlertCategoryOver:
+		if rule.Category == "" {
+			return "category-over rules need a category"
+		}
+		if rule.Threshold <= 0 {
+			return "threshold must be a positive percentage"
+		}
+		rule.Currency = ""
+	case domain.AlertRemainingUnder, domain.AlertExpenseOver:
+		if rule.Threshold < 0 || (rule.Kind == domain.AlertExpenseOver && rule.Threshold == 0) {
+			return "threshold must be positive"
+		}
+		if rule.Currency == "" {
+			rule.Currency = s.homeCurrency(rule.UserID)
+		}
+		rule.Currency = fx.Normalize(rule.Currency)
+		if _, err := s.rates().Rate(rule.Currency, ""); err != nil {
+			return "unsupported currency " + rule.Currency
+		}
+	case domain.AlertTripIntoRed:
+		rule.Category, rule.Threshold, rule.Currency = "", 0, ""
+	default:
+		return "kind must be category-over, remaining-under, expense-over or trip-into-red"
+	}
+	return ""
+}
+
+// checkAppliedPoll treats a poll whose winner was applied to its trip as the
+// trip being planned.
+func (s *Server) checkAppliedPoll(poll *domain.Poll) {
+	if poll == nil || !poll.Applied {
+		return
+	}
+	if trip := s.store.GetTrip(poll.TripID); trip != nil {
+		s.checkTripAlerts(trip)
+	}
+}
@@ -0,0 +1,194 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/bankimport"
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+const (
+	maxImportBytes = 5 << 20
+	maxImportRules = 200
+)
+
+type importRequest struct {
+	// Format is "csv" (default) or "camt053".
+	Format string `json:"format"`
+	// Profile names a built-in CSV mapping; Mapping supplies a custom one.
+	Profile string              `json:"profile"`
+	Mapping *bankimport.Profile `json:"mapping"`
+	Data    string              `json:"data"`
+	DryRun  bool                `json:"dryRun"`
+	// Rules apply to this import only, ahead of the user's saved rules.
+	Rules []domain.CategoryRule `json:"rules"`
+}
+
+// handleBudgetImport previews or imports a bank statement on POST /api/budget/import.
+func (s *Server) handleBudgetImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	userID := auth.UserIDFromContext(r.Context())
+
+	var req importRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes)).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if strings.TrimSpace(req.Data) == "" {
+		writeErr(w, http.StatusBadRequest, "data is required")
+		return
+	}
+	if msg := validateRules(req.Rules); msg != "" {
+		writeErr(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var (
+		txns []bankimport.Transaction
+		err  error
+	)
+	switch strings.ToLower(req.Format) {
+	case "", "csv":
+		profile, ok := bankimport.LookupProfile(req.Profile)
+		if req.Mapping != nil {
+			profile, ok = *req.Mapping, req.Mapping.DateColumn != "" && req.Mapping.AmountColumn != ""
+			if profile.Name == "" {
+				profile.Name = "custom"
+			}
+		} else if req.Profile == "" {
+			profile, ok = bankimport.LookupProfile("generic")
+		}
+		if !ok {
+			writeErr(w, http.StatusBadRequest, "unknown profile "+req.Profile)
+			return
+		}
+		req.Format, req.Profile = "csv", profile.Name
+		txns, err = bankimport.ParseCSV(strings.NewReader(req.Data), profile)
+	case "camt053", "camt.053":
+		req.Format, req.Profile = "camt053", ""
+		txns, err = bankimport.ParseCAMT053(strings.NewReader(req.Data))
+	default:
+		writeErr(w, http.StatusBadRequest, "format must be csv or camt053")
+		return
+	}
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rates := s.rates()
+	opts := bankimport.PlanOptions{
+		Rules: append(req.Rules, s.store.GetCategoryRules(userID)...),
+		Supported: func(currency, date string) bool {
+			_, err := rates.Rate(currency, date)
+			return err == nil
+		},
+	}
+	if from, to := dateRange(txns); from != "" {
+		opts.Existing = s.store.QueryBudgetEntries(userID, domain.BudgetEntryFilter{From: from, To: to}).Entries
+	}
+	rows, summary := bankimport.Plan(txns, opts)
+
+	imported := []domain.BudgetEntry{}
+	if !req.DryRun {
+		entries := make([]domain.BudgetEntry, 0, summary.New)
+		for _, row := range rows {
+			if row.Status == bankimport.RowNew {
+				entries = append(entries, row.Entry(userID))
+			}
+		}
+		if len(entries) > 0 {
+			imported = s.store.AddBudgetEntries(entries)
+			s.checkAlerts(userID, imported, "")
+		}
+	}
+
+	status := http.StatusOK
+	if !req.DryRun && len(imported) > 0 {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, map[string]any{
+		"dryRun":   req.DryRun,
+		"format":   req.Format,
+		"profile":  req.Profile,
+		"summary":  summary,
+		"rows":     rows,
+		"imported": imported,
+	})
+}
+
+func (s *Server) handleImportProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, bankimport.Profiles())
+}
+
+// handleImportRules serves the caller's saved categorisation rules.
+func (s *Server) handleImportRules(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"rules":    s.store.GetCategoryRules(userID),
+			"defaults": bankimport.DefaultRules,
+		})
+	case http.MethodPut:
+		var req struct {
+			Rules []domain.CategoryRule `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		if msg := validateRules(req.Rules); msg != "" {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		if req.Rules == nil {
+			req.Rules = []domain.CategoryRule{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"rules": s.store.SaveCategoryRules(userID, req.Rules)})
+	default:
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func validateRules(rules []domain.CategoryRule) string {
+	if len(rules) > maxImportRules {
+		return "too many rules"
+	}
+	for i := range rules {
+		rules[i].Keyword = strings.TrimSpace(rules[i].Keyword)
+		rules[i].Category = strings.TrimSpace(rules[i].Category)
+		if rules[i].Keyword == "" || rules[i].Category == "" {
+			return "rules need a keyword and a category"
+		}
+	}
+	return ""
+}
+
+// dateRange returns the earliest and latest readable transaction dates.
+func dateRange(txns []bankimport.Transaction) (string, string) {
+	from, to := "", ""
+	for _, tx := range txns {
+		if tx.Date == "" {
+			continue
+		}
+		if from == "" || tx.Date < from {
+			from = tx.Date
+		}
+		if tx.Date > to {
+			to = tx.Date
+		}
+	}
+	return from, to
+}
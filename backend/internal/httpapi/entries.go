@@ -0,0 +1,155 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	defaultEntryPageSize = 100
+	maxEntryPageSize     = 500
+)
+
+// parseEntryFilter reads list filters and pagination from the query string.
+func parseEntryFilter(q url.Values) (domain.BudgetEntryFilter, error) {
+	f := domain.BudgetEntryFilter{
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+		Category: q.Get("category"),
+		TripID:   q.Get("tripId"),
+		Currency: q.Get("currency"),
+		Query:    strings.TrimSpace(q.Get("q")),
+		Limit:    defaultEntryPageSize,
+	}
+	for _, d := range []struct{ name, value string }{{"from", f.From}, {"to", f.To}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			return f, fmt.Errorf("%s must be YYYY-MM-DD", d.name)
+		}
+	}
+	for _, a := range []struct {
+		name string
+		dst  **money.Amount
+	}{{"minAmount", &f.MinAmount}, {"maxAmount", &f.MaxAmount}} {
+		raw := q.Get(a.name)
+		if raw == "" {
+			continue
+		}
+		v, err := money.Parse(raw)
+		if err != nil {
+			return f, fmt.Errorf("%s must be a number", a.name)
+		}
+		*a.dst = &v
+	}
+	if raw := q.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxEntryPageSize {
+			return f, fmt.Errorf("limit must be between 1 and %d", maxEntryPageSize)
+		}
+		f.Limit = v
+	}
+	if raw := q.Get("offset"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return f, fmt.Errorf("offset must be a non-negative integer")
+		}
+		f.Offset = v
+	}
+	return f, nil
+}
+
+func (s *Server) listBudgetEntries(w http.ResponseWriter, r *http.Request, userID string) {
+	filter, err := parseEntryFilter(r.URL.Query())
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page := s.store.QueryBudgetEntries(userID, filter)
+
+	// Totals cover every match, not just the requested page.
+	all := filter
+	all.Limit, all.Offset = 0, 0
+	matches := page.Entries
+	if page.Total > len(page.Entries) {
+		matches = s.store.QueryBudgetEntries(userID, all).Entries
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"entries": page.Entries,
+		"total":   page.Total,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
+		"totals":  totalEntries(s.rates(), matches, s.homeCurrency(userID)),
+	})
+}
+
+// handleBudgetEntry serves PATCH and DELETE on /api/budget/entries/{id}.
+func (s *Server) handleBudgetEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/budget/entries/"), "/")
+	if id == "" || strings.Contains(id, "/") {
+		writeErr(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		var patch domain.BudgetEntryPatch
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		if msg := s.validateEntryPatch(&patch); msg != "" {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		entry, err := s.store.UpdateBudgetEntry(userID, id, patch)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		s.checkAlerts(userID, []domain.BudgetEntry{*entry}, "")
+		writeJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		if err := s.store.DeleteBudgetEntry(userID, id); err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) validateEntryPatch(patch *domain.BudgetEntryPatch) string {
+	if patch.Category != nil && strings.TrimSpace(*patch.Category) == "" {
+		return "category must not be empty"
+	}
+	if patch.Amount != nil && *patch.Amount <= 0 {
+		return "amount must be positive"
+	}
+	if patch.Date != nil {
+		if _, err := time.Parse("2006-01-02", *patch.Date); err != nil {
+			return "date must be YYYY-MM-DD"
+		}
+	}
+	if patch.Currency != nil {
+		currency := fx.Normalize(*patch.Currency)
+		if _, err := s.rates().Rate(currency, ""); err != nil {
+			return "unsupported currency " + currency
+		}
+		patch.Currency = &currency
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// exportFlushEvery is how many rows are buffered before flushing to the client.
+const exportFlushEvery = 200
+
+var exportCSVHeader = []string{
+	"id", "date", "category", "amount", "currency",
+	"convertedAmount", "convertedCurrency", "tripId", "tripDestination", "recurringId", "note",
+}
+
+type exportRow struct {
+	domain.BudgetEntry
+	// ConvertedAmount is nil when the entry's currency has no rate.
+	ConvertedAmount   *money.Amount `json:"convertedAmount"`
+	ConvertedCurrency string        `json:"convertedCurrency"`
+	TripDestination   string        `json:"tripDestination,omitempty"`
+}
+
+// exporter converts entries and keeps running category totals while rows stream out.
+type exporter struct {
+	s        *Server
+	currency string
+	convert  func(domain.BudgetEntry) (money.Amount, error)
+	trips    map[string]string // trip ID -> destination
+	totals   map[string]money.Amount
+	count    int
+	skipped  int
+}
+
+func (e *exporter) row(entry domain.BudgetEntry) exportRow {
+	row := exportRow{BudgetEntry: entry, ConvertedCurrency: e.currency}
+	if amount, err := e.convert(entry); err == nil {
+		row.ConvertedAmount = &amount
+		e.totals[entry.Category] += amount
+	} else {
+		e.skipped++
+	}
+	if entry.TripID != "" {
+		dest, ok := e.trips[entry.TripID]
+		if !ok {
+			if trip := e.s.store.GetTrip(entry.TripID); trip != nil {
+				dest = trip.Destination
+			}
+			e.trips[entry.TripID] = dest
+		}
+		row.TripDestination = dest
+	}
+	e.count++
+	return row
+}
+
+func (e *exporter) sortedTotals() []domain.CategorySpend {
+	out := make([]domain.CategorySpend, 0, len(e.totals))
+	for category, spent := range e.totals {
+		out = append(out, domain.CategorySpend{Category: category, Spent: spent})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Category < out[j].Category })
+	return out
+}
+
+// handleBudgetExport streams the caller's entries on
+// GET /api/budget/export?format=csv|json&from=&to=.
+func (s *Server) handleBudgetExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	userID := auth.UserIDFromContext(r.Context())
+	q := r.URL.Query()
+	filter := domain.BudgetEntryFilter{From: q.Get("from"), To: q.Get("to")}
+	for _, d := range []struct{ name, value string }{{"from", filter.From}, {"to", filter.To}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			writeErr(w, http.StatusBadRequest, d.name+" must be YYYY-MM-DD")
+			return
+		}
+	}
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		writeErr(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	rates, currency := s.rates(), s.homeCurrency(userID)
+	e := &exporter{
+		s:        s,
+		currency: currency,
+		trips:    map[string]string{},
+		totals:   map[string]money.Amount{},
+		convert: func(entry domain.BudgetEntry) (money.Amount, error) {
+			converted, err := rates.ConvertMoney(entry.Money(), currency, entry.Date)
+			return converted.Amount, err
+		},
+	}
+
+	name := "budget"
+	for _, part := range []string{filter.From, filter.To} {
+		if part != "" {
+			name += "-" + part
+		}
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	var err error
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = s.exportCSV(w, userID, filter, e)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		err = s.exportJSON(w, userID, filter, e)
+	}
+	if err != nil {
+		// Headers are gone by now; all we can do is cut the stream short.
+		log.Printf("budget export for %s aborted: %v", userID, err)
+	}
+}
+
+// exportCSV writes one row per entry, then a blank line and a category
+// totals section in the converted currency.
+func (s *Server) exportCSV(w http.ResponseWriter, userID string, filter domain.BudgetEntryFilter, e *exporter) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	err := s.store.StreamBudgetEntries(userID, filter, func(entry domain.BudgetEntry) error {
+		row := e.row(entry)
+		converted := ""
+		if row.ConvertedAmount != nil {
+			converted = row.ConvertedAmount.String()
+		}
+		if err := cw.Write([]string{
+			row.ID, row.Date, spreadsheetSafe(row.Category),
+			row.Amount.String(), row.Currency,
+			converted, row.ConvertedCurrency, row.TripID, spreadsheetSafe(row.TripDestination),
+			row.RecurringID, spreadsheetSafe(row.Note),
+		}); err != nil {
+			return err
+		}
+		if e.count%exportFlushEvery == 0 {
+			return flushCSV(cw, w)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Write(nil)
+	cw.Write([]string{"category", "total", "currency"})
+	for _, total := range e.sortedTotals() {
+		cw.Write([]string{spreadsheetSafe(total.Category), total.Spent.String(), e.currency})
+	}
+	return flushCSV(cw, w)
+}
+
+// exportJSON writes {"currency", "entries": [...], "totals", "count", "skipped"},
+// encoding entries one at a time.
+func (s *Server) exportJSON(w http.ResponseWriter, userID string, filter domain.BudgetEntryFilter, e *exporter) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `{"currency":%q,"from":%q,"to":%q,"entries":[`, e.currency, filter.From, filter.To)
+	err := s.store.StreamBudgetEntries(userID, filter, func(entry domain.BudgetEntry) error {
+		b, err := json.Marshal(e.row(entry))
+		if err != nil {
+			return err
+		}
+		if e.count > 1 {
+			bw.WriteByte(',')
+		}
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+		if e.count%exportFlushEvery == 0 {
+			return flushBuffered(bw, w)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	totals, err := json.Marshal(e.sortedTotals())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, `],"totals":%s,"count":%d,"skipped":%d}`+"\n", totals, e.count, e.skipped)
+	return flushBuffered(bw, w)
+}
+
+func flushCSV(cw *csv.Writer, w http.ResponseWriter) error {
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func flushBuffered(bw *bufio.Writer, w http.ResponseWriter) error {
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// spreadsheetSafe defuses cells that spreadsheets would evaluate as formulas.
+func spreadsheetSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"net/http"
+	"slices"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+type entryTotals struct {
+	Currency   string                  `json:"currency"`
+	Total      money.Amount            `json:"total"`
+	ByCategory map[string]money.Amount `json:"byCategory"`
+	Skipped    []string                `json:"skipped,omitempty"`
+}
+
+func (s *Server) rates() *fx.Table {
+	return fx.NewTable(s.store.ListFXRates())
+}
+
+// homeCurrency is the currency a user's totals and costs are reported in.
+func (s *Server) homeCurrency(userID string) string {
+	return fx.Normalize(s.store.GetProfile(userID).HomeCurrency)
+}
+
+// totalEntries sums entries in currency, converting each at its own date.
+func totalEntries(rates *fx.Table, entries []domain.BudgetEntry, currency string) entryTotals {
+	totals := entryTotals{Currency: currency, ByCategory: map[string]money.Amount{}}
+	for _, entry := range entries {
+		converted, err := rates.ConvertMoney(entry.Money(), currency, entry.Date)
+		if err != nil {
+			totals.Skipped = append(totals.Skipped, entry.ID)
+			continue
+		}
+		totals.Total += converted.Amount
+		totals.ByCategory[entry.Category] += converted.Amount
+	}
+	return totals
+}
+
+// convertTripOptions re-expresses EUR optimizer costs in currency. Options
+// are left in EUR when no rate is known.
+func convertTripOptions(rates *fx.Table, options []domain.TripOption, currency string) []domain.TripOption {
+	conv := func(amount money.Amount) (money.Amount, bool) {
+		v, err := rates.ConvertAmount(amount, fx.Base, currency, "")
+		return v, err == nil
+	}
+	for i := range options {
+		opt := &options[i]
+		if fx.Normalize(opt.Currency) == currency {
+			continue
+		}
+		total, ok := conv(opt.TotalEstimatedCost)
+		if !ok {
+			continue
+		}
+		opt.TotalEstimatedCost = total
+		opt.StayCost, _ = conv(opt.StayCost)
+		opt.Currency = currency
+		for j := range opt.TransportOptions {
+			t := &opt.TransportOptions[j]
+			t.Price, _ = conv(t.Price)
+			t.BaggageCost, _ = conv(t.BaggageCost)
+			if t.Baggage != nil {
+				// Fare rules may be shared with cached provider results.
+				baggage := *t.Baggage
+				baggage.CabinBag.Fee, _ = conv(baggage.CabinBag.Fee)
+				baggage.CheckedBag.Fee, _ = conv(baggage.CheckedBag.Fee)
+				t.Baggage = &baggage
+			}
+			if len(t.Legs) > 0 {
+				// Legs may be shared with cached provider results too.
+				t.Legs = slices.Clone(t.Legs)
+				for k := range t.Legs {
+					t.Legs[k].ReservationFee, _ = conv(t.Legs[k].ReservationFee)
+				}
+			}
+		}
+		for j := range opt.StayOptions {
+			opt.StayOptions[j].NightlyPrice, _ = conv(opt.StayOptions[j].NightlyPrice)
+			opt.StayOptions[j].TotalPrice, _ = conv(opt.StayOptions[j].TotalPrice)
+		}
+	}
+	return options
+}
+
+// convertGroupOptions re-expresses EUR group costs and member headroom in
+// currency. Options are left in EUR when no rate is known.
+func convertGroupOptions(rates *fx.Table, options []domain.GroupTripOption, currency string) []domain.GroupTripOption {
+	conv := func(amount money.Amount) (money.Amount, bool) {
+		v, err := rates.ConvertAmount(amount, fx.Base, currency, "")
+		return v, err == nil
+	}
+	for i := range options {
+		opt := &options[i]
+		if fx.Normalize(opt.Currency) == currency {
+			continue
+		}
+		total, ok := conv(opt.TotalEstimatedCost)
+		if !ok {
+			continue
+		}
+		opt.TotalEstimatedCost = total
+		opt.Currency = currency
+		for j := range opt.Members {
+			fit := &opt.Members[j]
+			fit.EstimatedCost, _ = conv(fit.EstimatedCost)
+			fit.BudgetHeadroom, _ = conv(fit.BudgetHeadroom)
+		}
+	}
+	return options
+}
+
+func (s *Server) handleFXRates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	date := r.URL.Query().Get("date")
+	writeJSON(w, http.StatusOK, map[string]any{"base": fx.Base, "rates": s.rates().Effective(date)})
+}
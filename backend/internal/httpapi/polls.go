@@ -0,0 +1,200 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+const defaultPollCandidates = 3
+
+// handleTripPolls serves /api/trips/{tripId}/polls[/{pollId}[/votes|/close]].
+func (s *Server) handleTripPolls(w http.ResponseWriter, r *http.Request, tripID string, rest []string) {
+	userID := auth.UserIDFromContext(r.Context())
+	trip := s.store.GetTrip(tripID)
+	if trip == nil {
+		writeErr(w, http.StatusNotFound, "trip not found")
+		return
+	}
+	if !slices.Contains(trip.Members, userID) {
+		writeErr(w, http.StatusForbidden, "not a trip member")
+		return
+	}
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"polls": s.store.ListPolls(tripID)})
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		s.createPoll(w, r, trip, userID)
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		poll, err := s.store.GetPoll(tripID, rest[0])
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, poll)
+	case len(rest) == 2 && rest[1] == "votes" && r.Method == http.MethodPost:
+		var req struct {
+			OptionID string `json:"optionId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		if req.OptionID == "" {
+			writeErr(w, http.StatusBadRequest, "missing optionId")
+			return
+		}
+		poll, err := s.store.VotePoll(tripID, rest[0], userID, req.OptionID)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		s.checkAppliedPoll(poll)
+		writeJSON(w, http.StatusOK, poll)
+	case len(rest) == 2 && rest[1] == "close" && r.Method == http.MethodPost:
+		poll, err := s.store.GetPoll(tripID, rest[0])
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		if poll.CreatedBy != userID && trip.OwnerID != userID {
+			writeErr(w, http.StatusForbidden, "only the poll creator or trip owner can close a poll")
+			return
+		}
+		poll, err = s.store.ClosePoll(tripID, rest[0])
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		s.checkAppliedPoll(poll)
+		writeJSON(w, http.StatusOK, poll)
+	default:
+		writeErr(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) createPoll(w http.ResponseWriter, r *http.Request, trip *domain.Trip, userID string) {
+	var req struct {
+		Question   string                 `json:"question"`
+		Kind       domain.PollKind        `json:"kind"`
+		Options    []domain.PollOption    `json:"options"`
+		CloseRule  domain.PollCloseRule   `json:"closeRule"`
+		ClosesAt   string                 `json:"closesAt"`
+		AutoApply  bool                   `json:"autoApply"`
+		Constraint *domain.TripConstraint `json:"constraint"`
+		Candidates int                    `json:"candidates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.Kind == "" {
+		req.Kind = domain.PollFreeform
+	}
+	switch req.Kind {
+	case domain.PollDestination, domain.PollWindow, domain.PollFreeform:
+	default:
+		writeErr(w, http.StatusBadRequest, "unknown kind")
+		return
+	}
+	if req.CloseRule == "" {
+		req.CloseRule = domain.PollCloseManual
+	}
+	switch req.CloseRule {
+	case domain.PollCloseManual, domain.PollCloseAllVoted, domain.PollCloseMajority:
+	default:
+		writeErr(w, http.StatusBadRequest, "unknown closeRule")
+		return
+	}
+	if req.Candidates <= 0 {
+		req.Candidates = defaultPollCandidates
+	}
+
+	options := req.Options
+	if len(options) == 0 {
+		switch req.Kind {
+		case domain.PollDestination:
+			if req.Constraint == nil {
+				writeErr(w, http.StatusBadRequest, "destination polls need options or a constraint")
+				return
+			}
+			rates := s.rates()
+			constraint, currency, err := s.optimizerConstraint(rates, userID, *req.Constraint)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, "unsupported currency "+currency)
+				return
+			}
+			for _, opt := range convertTripOptions(rates, s.store.OptimizeTrips(constraint), currency) {
+				if len(options) == req.Candidates {
+					break
+				}
+				options = append(options, domain.PollOption{
+					Label:       fmt.Sprintf("%s (~%.0f %s)", opt.Destination, opt.TotalEstimatedCost.Float(), opt.Currency),
+					Destination: opt.Destination,
+				})
+			}
+		case domain.PollWindow:
+			// Only windows not over yet are worth voting on.
+			for _, window := range s.store.ListTravelWindows(s.now().UTC().Format("2006-01-02"), "") {
+				if len(options) == req.Candidates {
+					break
+				}
+				options = append(options, domain.PollOption{
+					Label:    window.StartDate + " – " + window.EndDate,
+					WindowID: window.ID,
+				})
+			}
+		}
+	}
+	if len(options) < 2 {
+		writeErr(w, http.StatusBadRequest, "a poll needs at least two options")
+		return
+	}
+	for i := range options {
+		options[i].ID = fmt.Sprintf("o%d", i+1)
+		if options[i].Label == "" {
+			options[i].Label = options[i].Destination + options[i].WindowID
+		}
+		if options[i].Label == "" {
+			writeErr(w, http.StatusBadRequest, "option label required")
+			return
+		}
+	}
+	if req.Question == "" {
+		switch req.Kind {
+		case domain.PollDestination:
+			req.Question = "Where should we go?"
+		case domain.PollWindow:
+			req.Question = "When should we go?"
+		default:
+			writeErr(w, http.StatusBadRequest, "missing question")
+			return
+		}
+	}
+
+	poll := domain.Poll{
+		TripID:    trip.ID,
+		CreatedBy: userID,
+		Question:  req.Question,
+		Kind:      req.Kind,
+		Options:   options,
+		CloseRule: req.CloseRule,
+		ClosesAt:  req.ClosesAt,
+		AutoApply: req.AutoApply,
+	}
+	if _, ok := poll.Deadline(); req.ClosesAt != "" && !ok {
+		writeErr(w, http.StatusBadRequest, "closesAt must be a date or RFC3339 timestamp")
+		return
+	}
+	created, err := s.store.CreatePoll(poll)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
@@ -0,0 +1,150 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func TestCreatePoll_FromOptimizer(t *testing.T) {
+	_, h := setup()
+	body := `{"kind":"destination","candidates":2,"constraint":{"budgetCap":300,"maxTravelHours":6,"partySize":1,"style":"culture"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var poll domain.Poll
+	json.NewDecoder(w.Body).Decode(&poll)
+	if len(poll.Options) != 2 {
+		t.Fatalf("expected 2 options, got %d", len(poll.Options))
+	}
+	if poll.Options[0].ID != "o1" || poll.Options[0].Destination == "" {
+		t.Fatalf("unexpected option %+v", poll.Options[0])
+	}
+}
+
+func TestCreatePoll_ConvertsTheBudgetCap(t *testing.T) {
+	_, h := setup()
+	// 2520 CZK is EUR 100: every seeded trip is over it, Prague the most.
+	body := `{"kind":"destination","candidates":2,"constraint":{"budgetCap":2520,"currency":"CZK","maxTravelHours":6,"partySize":1,"style":"culture"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var poll domain.Poll
+	json.NewDecoder(w.Body).Decode(&poll)
+	if w.Code != 201 || len(poll.Options) != 2 {
+		t.Fatalf("expected 2 options, got %d: %s", w.Code, w.Body.String())
+	}
+	if poll.Options[0].Destination != "Krakow" || !strings.HasSuffix(poll.Options[0].Label, " CZK)") {
+		t.Fatalf("expected Krakow first, priced in CZK, got %+v", poll.Options)
+	}
+}
+
+func TestCreatePoll_OffersUpcomingWindows(t *testing.T) {
+	s, h := setup()
+	s.now = func() time.Time { return time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC) }
+	create := func(body string) domain.Poll {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var poll domain.Poll
+		json.NewDecoder(w.Body).Decode(&poll)
+		if w.Code != 201 {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		return poll
+	}
+	// w-1 ended on March 8th.
+	if poll := create(`{"kind":"window"}`); len(poll.Options) != 2 || poll.Options[0].WindowID != "w-2" {
+		t.Fatalf("expected w-2 and w-3, got %+v", poll.Options)
+	}
+	s.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	if poll := create(`{"kind":"window","candidates":2}`); len(poll.Options) != 2 || poll.Options[1].WindowID != "w-2" {
+		t.Fatalf("expected w-1 and w-2, got %+v", poll.Options)
+	}
+}
+
+func TestCreatePoll_NeedsTwoOptions(t *testing.T) {
+	_, h := setup()
+	body := `{"kind":"freeform","question":"Dinner?","options":[{"label":"Pizza"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestVotePoll_AutoApplyWindow(t *testing.T) {
+	s, h := setup()
+	s.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	body := `{"kind":"window","closeRule":"all-voted","autoApply":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var poll domain.Poll
+	json.NewDecoder(w.Body).Decode(&poll)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls/"+poll.ID+"/votes", bytes.NewBufferString(`{"optionId":"o3"}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&poll)
+	if poll.Status != domain.PollClosed || !poll.Applied {
+		t.Fatalf("expected closed and applied poll, got %+v", poll)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/trips/trip-1", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var trip domain.Trip
+	json.NewDecoder(w.Body).Decode(&trip)
+	if trip.WindowID != "w-3" {
+		t.Fatalf("expected window w-3, got %s", trip.WindowID)
+	}
+}
+
+func TestPolls_TripNotFound(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodGet, "/api/trips/nonexistent/polls", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestVotePoll_ClosedConflict(t *testing.T) {
+	_, h := setup()
+	body := `{"kind":"freeform","question":"Dinner?","options":[{"label":"Pizza"},{"label":"Sushi"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var poll domain.Poll
+	json.NewDecoder(w.Body).Decode(&poll)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls/"+poll.ID+"/close", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/polls/"+poll.ID+"/votes", bytes.NewBufferString(`{"optionId":"o1"}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 409 {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
@@ -0,0 +1,62 @@
+package httpapi
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+)
+
+// handleTripReconciliation serves /api/trips/{tripId}/reconciliation: GET for
+// the estimated-vs-actual report, POST (trip owner, after the trip) to
+// finalise it and feed the observed costs back into future estimates.
+func (s *Server) handleTripReconciliation(w http.ResponseWriter, r *http.Request, tripID string) {
+	userID := auth.UserIDFromContext(r.Context())
+	trip := s.store.GetTrip(tripID)
+	if trip == nil {
+		writeErr(w, http.StatusNotFound, "trip not found")
+		return
+	}
+	if !slices.Contains(trip.Members, userID) {
+		writeErr(w, http.StatusForbidden, "not a trip member")
+		return
+	}
+	currency := s.homeCurrency(userID)
+
+	switch r.Method {
+	case http.MethodGet:
+		report, err := s.store.ReconcileTrip(tripID, currency)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	case http.MethodPost:
+		if trip.OwnerID != userID {
+			writeErr(w, http.StatusForbidden, "only the trip owner can finalise the reconciliation")
+			return
+		}
+		report, err := s.store.ReconcileTrip(tripID, currency)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		if today := time.Now().UTC().Format("2006-01-02"); report.EndDate != "" && report.EndDate >= today {
+			writeErr(w, http.StatusConflict, "trip has not ended yet")
+			return
+		}
+		if report.EntryCount == 0 {
+			writeErr(w, http.StatusConflict, "no budget entries are linked to this trip")
+			return
+		}
+		report, err = s.store.FinalizeTripReconciliation(tripID, currency)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	default:
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
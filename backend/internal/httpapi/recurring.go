@@ -0,0 +1,135 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+)
+
+const maxRecurringInterval = 12
+
+// handleRecurringEntries serves GET and POST on /api/budget/recurring.
+func (s *Server) handleRecurringEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.store.ListRecurringEntries(userID))
+	case http.MethodPost:
+		var req domain.RecurringEntry
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		req.UserID = userID
+		if msg := s.validateRecurring(&req); msg != "" {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		created := s.store.CreateRecurringEntry(req)
+		// Generate occurrences that are already due rather than waiting for the job.
+		s.store.MaterializeRecurring(time.Now().UTC().Format("2006-01-02"))
+		for _, entry := range s.store.ListRecurringEntries(userID) {
+			if entry.ID == created.ID {
+				created = entry
+			}
+		}
+		writeJSON(w, http.StatusCreated, created)
+	default:
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleRecurringEntry serves DELETE on /api/budget/recurring/{id} and PUT on
+// /api/budget/recurring/{id}/occurrences/{date}.
+func (s *Server) handleRecurringEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/budget/recurring/"), "/"), "/")
+	id := parts[0]
+
+	switch {
+	case len(parts) == 1 && id != "":
+		if r.Method != http.MethodDelete {
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if err := s.store.DeleteRecurringEntry(userID, id); err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 3 && parts[1] == "occurrences":
+		if r.Method != http.MethodPut {
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var override domain.RecurringOverride
+		if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		override.Date = parts[2]
+		if msg := validateOverride(override); msg != "" {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		entry, err := s.store.OverrideRecurringOccurrence(userID, id, override)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, entry)
+	default:
+		writeErr(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) validateRecurring(req *domain.RecurringEntry) string {
+	req.Category = strings.TrimSpace(req.Category)
+	req.Currency = fx.Normalize(req.Currency)
+	if req.Category == "" || req.Amount <= 0 || req.StartDate == "" {
+		return "missing required fields"
+	}
+	if req.Frequency != domain.RecurringMonthly && req.Frequency != domain.RecurringWeekly {
+		return "frequency must be monthly or weekly"
+	}
+	if req.Interval == 0 {
+		req.Interval = 1
+	}
+	if req.Interval < 1 || req.Interval > maxRecurringInterval {
+		return "interval must be between 1 and 12"
+	}
+	if _, err := time.Parse("2006-01-02", req.StartDate); err != nil {
+		return "startDate must be YYYY-MM-DD"
+	}
+	if req.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
+			return "endDate must be YYYY-MM-DD"
+		}
+		if req.EndDate < req.StartDate {
+			return "endDate must not be before startDate"
+		}
+	}
+	if _, err := s.rates().Rate(req.Currency, req.StartDate); err != nil {
+		return "unsupported currency " + req.Currency
+	}
+	return ""
+}
+
+func validateOverride(o domain.RecurringOverride) string {
+	if _, err := time.Parse("2006-01-02", o.Date); err != nil {
+		return "occurrence date must be YYYY-MM-DD"
+	}
+	if o.Category != nil && strings.TrimSpace(*o.Category) == "" {
+		return "category must not be empty"
+	}
+	if o.Amount != nil && *o.Amount <= 0 {
+		return "amount must be positive"
+	}
+	return ""
+}
@@ -2,24 +2,61 @@ package httpapi
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"exchange-travel-planner/backend/internal/auth"
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/planner"
 	"exchange-travel-planner/backend/internal/provider"
 )
 
+const (
+	maxGuests     = 20
+	maxPassengers = 9
+	// stayProviderSource names the configured accommodation provider in the
+	// sources of a stay search.
+	stayProviderSource = "stays"
+)
+
 type Server struct {
-	store             domain.DataStore
-	transportProvider provider.TransportProvider
+	store     domain.DataStore
+	transport *provider.Registry
+	// stays is nil unless STAY_PROVIDER is set; the store's seeded stays
+	// are used then.
+	stays provider.AccommodationProvider
+	// now is the clock for "today"; tests pin it.
+	now func() time.Time
 }
 
 func NewServer(s domain.DataStore) *Server {
-	return &Server{
-		store:             s,
-		transportProvider: provider.NewOpenTransportProviderFromEnv(),
+	transport, err := provider.NewRegistryFromEnv()
+	if err != nil {
+		log.Printf("transport providers: %v", err)
+	}
+	stays, err := provider.NewAccommodationProviderFromEnv()
+	if err != nil {
+		log.Printf("stay provider: %v", err)
 	}
+	return &Server{store: s, transport: transport, stays: stays, now: time.Now}
+}
+
+// UseSearchCache caches transport provider answers in cache, with the TTLs
+// set in the environment.
+func (s *Server) UseSearchCache(cache provider.SearchCache) {
+	config, err := provider.CacheConfigFromEnv()
+	if err != nil {
+		log.Printf("transport search cache: %v", err)
+	}
+	s.transport.EnableCache(cache, config)
 }
 
 func (s *Server) Routes() http.Handler {
@@ -35,10 +72,25 @@ func (s *Server) Routes() http.Handler {
 	apiMux.HandleFunc("/api/trips/optimize", s.handleTripOptimize)
 	apiMux.HandleFunc("/api/trips/", s.handleTripRoutes)
 	apiMux.HandleFunc("/api/budget/entries", s.handleBudgetEntries)
+	apiMux.HandleFunc("/api/budget/entries/", s.handleBudgetEntry)
+	apiMux.HandleFunc("/api/budget/recurring", s.handleRecurringEntries)
+	apiMux.HandleFunc("/api/budget/recurring/", s.handleRecurringEntry)
+	apiMux.HandleFunc("/api/budget/goals", s.handleSavingsGoals)
+	apiMux.HandleFunc("/api/budget/goals/", s.handleSavingsGoal)
+	apiMux.HandleFunc("/api/budget/import", s.handleBudgetImport)
+	apiMux.HandleFunc("/api/budget/export", s.handleBudgetExport)
+	apiMux.HandleFunc("/api/budget/import/profiles", s.handleImportProfiles)
+	apiMux.HandleFunc("/api/budget/import/rules", s.handleImportRules)
 	apiMux.HandleFunc("/api/budget/forecast", s.handleBudgetForecast)
+	apiMux.HandleFunc("/api/budget/settings", s.handleBudgetSettings)
+	apiMux.HandleFunc("/api/alerts", s.handleAlerts)
+	apiMux.HandleFunc("/api/alerts/", s.handleAlertRoutes)
 	apiMux.HandleFunc("/api/search/transport", s.handleSearchTransport)
 	apiMux.HandleFunc("/api/search/stays", s.handleSearchStays)
+	apiMux.HandleFunc("/api/search/providers", s.handleProviderHealth)
 	apiMux.HandleFunc("/api/conflicts/evaluate", s.handleConflicts)
+	apiMux.HandleFunc("/api/profile", s.handleProfile)
+	apiMux.HandleFunc("/api/fx/rates", s.handleFXRates)
 
 	mux.Handle("/api/", auth.RequireAuth(apiMux))
 
@@ -65,6 +117,10 @@ func (s *Server) handleCalendarImport(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusBadRequest, "invalid json")
 		return
 	}
+	userID := auth.UserIDFromContext(r.Context())
+	for i := range req.Events {
+		req.Events[i].UserID = userID
+	}
 	writeJSON(w, http.StatusOK, map[string]any{"events": s.store.ImportAcademicEvents(req.Events)})
 }
 
@@ -83,12 +139,75 @@ func (s *Server) handleTripOptimize(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
-	var req domain.TripConstraint
+	var req struct {
+		domain.TripConstraint
+		Mode      string   `json:"mode"`
+		TripID    string   `json:"tripId"`
+		WindowIDs []string `json:"windowIds"`
+	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeErr(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"options": s.store.OptimizeTrips(req)})
+	userID := auth.UserIDFromContext(r.Context())
+	rates := s.rates()
+	constraint, currency, err := s.optimizerConstraint(rates, userID, req.TripConstraint)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "unsupported currency "+currency)
+		return
+	}
+	if req.Mode == "group" {
+		if req.TripID == "" {
+			writeErr(w, http.StatusBadRequest, "missing tripId")
+			return
+		}
+		trip := s.store.GetTrip(req.TripID)
+		if trip == nil {
+			writeErr(w, http.StatusNotFound, "trip not found")
+			return
+		}
+		if !slices.Contains(trip.Members, userID) {
+			writeErr(w, http.StatusForbidden, "not a trip member")
+			return
+		}
+		options := planner.OptimizeGroup(r.Context(), s.store, s.transport, *trip, planner.GroupRequest{
+			WindowIDs: req.WindowIDs,
+			Defaults:  req.TripConstraint,
+		})
+		options = convertGroupOptions(rates, options, currency)
+		writeJSON(w, http.StatusOK, map[string]any{"mode": "group", "tripId": trip.ID, "options": options})
+		return
+	}
+	profile := s.store.GetProfile(userID)
+	passes := fares.Passes(profile.Passes)
+	stayQuery := s.stayQuery(constraint.WindowID, constraint.PartySize)
+	options := planner.EnrichStays(r.Context(), s.stays, s.store.OptimizeTrips(constraint), stayQuery, constraint.BudgetCap)
+	// Live transport is searched from home on the window's first day, with a
+	// cabin bag each.
+	partySize := max(constraint.PartySize, 1)
+	transportQuery := provider.TransportQuery{
+		From: profile.HomeCity, Date: stayQuery.CheckIn, Passengers: partySize, CabinBags: partySize, Passes: passes,
+	}
+	options = planner.EnrichTransport(r.Context(), s.transport, options, transportQuery, constraint.BudgetCap)
+	options = planner.ApplyPasses(provider.Fares(), options, transportQuery.Party(), stayQuery.CheckIn, constraint.BudgetCap)
+	options = convertTripOptions(rates, options, currency)
+	writeJSON(w, http.StatusOK, map[string]any{"options": options})
+}
+
+// optimizerConstraint re-expresses c's budget cap in EUR, the optimizer's
+// currency. It also returns the currency c was given in, which is c.Currency
+// or the user's home currency when unset; costs are reported back in it.
+func (s *Server) optimizerConstraint(rates *fx.Table, userID string, c domain.TripConstraint) (domain.TripConstraint, string, error) {
+	currency := fx.Normalize(c.Currency)
+	if c.Currency == "" {
+		currency = s.homeCurrency(userID)
+	}
+	capEUR, err := rates.ConvertAmount(c.BudgetCap, currency, fx.Base, "")
+	if err != nil {
+		return c, currency, err
+	}
+	c.BudgetCap, c.Currency = capEUR, fx.Base
+	return c, currency, nil
 }
 
 func (s *Server) handleTripRoutes(w http.ResponseWriter, r *http.Request) {
@@ -122,10 +241,21 @@ func (s *Server) handleTripRoutes(w http.ResponseWriter, r *http.Request) {
 			writeErr(w, http.StatusNotFound, "trip not found")
 			return
 		}
+		s.checkTripAlerts(trip)
 		writeJSON(w, http.StatusOK, trip)
 		return
 	}
 
+	if len(parts) == 4 && parts[3] == "reconciliation" {
+		s.handleTripReconciliation(w, r, tripID)
+		return
+	}
+
+	if len(parts) >= 4 && parts[3] == "polls" {
+		s.handleTripPolls(w, r, tripID, parts[4:])
+		return
+	}
+
 	writeErr(w, http.StatusNotFound, "not found")
 }
 
@@ -133,7 +263,7 @@ func (s *Server) handleBudgetEntries(w http.ResponseWriter, r *http.Request) {
 	userID := auth.UserIDFromContext(r.Context())
 
 	if r.Method == http.MethodGet {
-		writeJSON(w, http.StatusOK, map[string]any{"entries": s.store.ListBudgetEntries(userID)})
+		s.listBudgetEntries(w, r, userID)
 		return
 	}
 
@@ -144,14 +274,17 @@ func (s *Server) handleBudgetEntries(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		req.UserID = userID
-		if req.Currency == "" {
-			req.Currency = "EUR"
-		}
+		req.Currency = fx.Normalize(req.Currency)
 		if req.Category == "" || req.Amount <= 0 || req.Date == "" {
 			writeErr(w, http.StatusBadRequest, "missing required fields")
 			return
 		}
+		if _, err := s.rates().Rate(req.Currency, req.Date); err != nil {
+			writeErr(w, http.StatusBadRequest, "unsupported currency "+req.Currency)
+			return
+		}
 		entry := s.store.AddBudgetEntry(req)
+		s.checkAlerts(userID, []domain.BudgetEntry{entry}, "")
 		writeJSON(w, http.StatusCreated, entry)
 		return
 	}
@@ -166,7 +299,71 @@ func (s *Server) handleBudgetForecast(w http.ResponseWriter, r *http.Request) {
 	}
 	userID := auth.UserIDFromContext(r.Context())
 	tripID := r.URL.Query().Get("tripId")
-	writeJSON(w, http.StatusOK, s.store.Forecast(userID, tripID))
+	asOf := r.URL.Query().Get("asOf")
+	if asOf != "" {
+		if _, err := time.Parse("2006-01-02", asOf); err != nil {
+			writeErr(w, http.StatusBadRequest, "asOf must be YYYY-MM-DD")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, s.store.Forecast(userID, tripID, asOf))
+}
+
+func (s *Server) handleBudgetSettings(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+
+	if r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.store.GetBudgetSettings(userID))
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var req domain.BudgetSettings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		req.UserID = userID
+		req.Currency = fx.Normalize(req.Currency)
+		if req.StartDay == 0 {
+			req.StartDay = 1
+		}
+		if req.Allocations == nil {
+			req.Allocations = map[string]money.Amount{}
+		}
+		if req.MonthlyBudget <= 0 {
+			writeErr(w, http.StatusBadRequest, "monthlyBudget must be positive")
+			return
+		}
+		if req.StartDay < 1 || req.StartDay > 28 {
+			writeErr(w, http.StatusBadRequest, "startDay must be between 1 and 28")
+			return
+		}
+		if _, err := s.rates().Rate(req.Currency, ""); err != nil {
+			writeErr(w, http.StatusBadRequest, "unsupported currency "+req.Currency)
+			return
+		}
+		var allocated money.Amount
+		for category, amount := range req.Allocations {
+			if category == "" || amount < 0 {
+				writeErr(w, http.StatusBadRequest, "allocations need a category and a non-negative amount")
+				return
+			}
+			allocated += amount
+		}
+		if allocated > req.MonthlyBudget {
+			writeErr(w, http.StatusBadRequest, "allocations exceed monthlyBudget")
+			return
+		}
+		if req.AmberThreshold < 0 || req.AmberThreshold >= req.MonthlyBudget {
+			writeErr(w, http.StatusBadRequest, "amberThreshold must be between 0 and monthlyBudget")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.store.SaveBudgetSettings(req))
+		return
+	}
+
+	writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 }
 
 func (s *Server) handleSearchTransport(w http.ResponseWriter, r *http.Request) {
@@ -174,19 +371,80 @@ func (s *Server) handleSearchTransport(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
-	from := r.URL.Query().Get("from")
-	to := r.URL.Query().Get("to")
-	if to == "" {
+	query := r.URL.Query()
+	q := provider.TransportQuery{
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+		Date:       query.Get("date"),
+		Time:       query.Get("time"),
+		ArriveBy:   query.Get("arriveBy") == "true",
+		Passengers: 1,
+	}
+	if q.To == "" {
 		writeErr(w, http.StatusBadRequest, "missing to")
 		return
 	}
-	if s.transportProvider != nil {
-		if options, err := s.transportProvider.SearchTransport(r.Context(), from, to); err == nil && len(options) > 0 {
-			writeJSON(w, http.StatusOK, map[string]any{"options": options})
+	if q.Date != "" {
+		if _, err := time.Parse("2006-01-02", q.Date); err != nil {
+			writeErr(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+			return
+		}
+	}
+	if q.Time != "" {
+		if _, err := time.Parse("15:04", q.Time); err != nil {
+			writeErr(w, http.StatusBadRequest, "time must be HH:MM")
+			return
+		}
+	}
+	if raw := query.Get("passengers"); raw != "" {
+		passengers, err := strconv.Atoi(raw)
+		if err != nil || passengers < 1 || passengers > maxPassengers {
+			writeErr(w, http.StatusBadRequest, "passengers must be between 1 and 9")
+			return
+		}
+		q.Passengers = passengers
+	}
+	q.CabinBags = -1
+	for _, counted := range []struct {
+		param string
+		count *int
+	}{{"youth", &q.Youth}, {"students", &q.Students}, {"cabinBags", &q.CabinBags}, {"checkedBags", &q.CheckedBags}} {
+		raw := query.Get(counted.param)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeErr(w, http.StatusBadRequest, counted.param+" must be a non-negative number")
 			return
 		}
+		*counted.count = n
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"options": s.store.SearchTransport(from, to)})
+	if q.Youth+q.Students > q.Passengers {
+		writeErr(w, http.StatusBadRequest, "youth and students must not exceed passengers")
+		return
+	}
+	if q.CabinBags < 0 {
+		// Everyone brings a cabin bag unless told otherwise.
+		q.CabinBags = q.Passengers
+	}
+	q.Passes = fares.Passes(s.store.GetProfile(auth.UserIDFromContext(r.Context())).Passes)
+	options, sources := s.transport.Search(r.Context(), q)
+	if len(options) == 0 {
+		// Seeded options are per adult and undated; they are priced for the
+		// party like provider fares, with its discounts and passes.
+		options = s.store.SearchTransport(q.From, q.To)
+		model := provider.Fares()
+		party := q.Party()
+		for i := range options {
+			opt := &options[i]
+			fare := model.PriceFrom(fares.Journey{Mode: opt.Mode, Hours: opt.DurationHours, Date: q.Date},
+				fares.Fare{Amount: opt.Price, Kind: opt.PriceKind}, party)
+			opt.Price, opt.Passes = fare.Amount, fare.Passes
+		}
+		sources = append(sources, provider.SourceStatus{Provider: provider.SeedSource, Status: provider.StatusSynthetic, Options: len(options)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"options": options, "sources": sources, "partial": provider.Partial(sources)})
 }
 
 func (s *Server) handleSearchStays(w http.ResponseWriter, r *http.Request) {
@@ -194,12 +452,67 @@ func (s *Server) handleSearchStays(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
-	city := r.URL.Query().Get("city")
-	if city == "" {
+	query := r.URL.Query()
+	q := provider.StayQuery{City: query.Get("city"), CheckIn: query.Get("checkIn"), CheckOut: query.Get("checkOut"), Guests: 1}
+	if q.City == "" {
 		writeErr(w, http.StatusBadRequest, "missing city")
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"options": s.store.SearchStays(city)})
+	if q.CheckIn != "" || q.CheckOut != "" {
+		in, err1 := time.Parse("2006-01-02", q.CheckIn)
+		out, err2 := time.Parse("2006-01-02", q.CheckOut)
+		if err1 != nil || err2 != nil || !out.After(in) {
+			writeErr(w, http.StatusBadRequest, "checkIn and checkOut must be YYYY-MM-DD with checkOut after checkIn")
+			return
+		}
+	}
+	if raw := query.Get("guests"); raw != "" {
+		guests, err := strconv.Atoi(raw)
+		if err != nil || guests < 1 || guests > maxGuests {
+			writeErr(w, http.StatusBadRequest, "guests must be between 1 and 20")
+			return
+		}
+		q.Guests = guests
+	}
+	sources := []provider.SourceStatus{}
+	if s.stays != nil {
+		start := time.Now()
+		options, err := s.stays.SearchStays(r.Context(), q)
+		source := provider.SourceStatus{Provider: stayProviderSource, Status: provider.StatusOK, Options: len(options), DurationMs: time.Since(start).Milliseconds()}
+		if err == nil && len(options) > 0 {
+			writeJSON(w, http.StatusOK, map[string]any{"options": options, "sources": []provider.SourceStatus{source}, "partial": false})
+			return
+		}
+		if err != nil {
+			log.Printf("stay provider for %s: %v", q.City, err)
+			source.Status, source.Error = provider.StatusError, err.Error()
+		}
+		sources = append(sources, source)
+	}
+	options := s.store.SearchStays(q.City)
+	sources = append(sources, provider.SourceStatus{Provider: provider.SeedSource, Status: provider.StatusSynthetic, Options: len(options)})
+	writeJSON(w, http.StatusOK, map[string]any{"options": options, "sources": sources, "partial": provider.Partial(sources)})
+}
+
+// handleProviderHealth reports retries, failures and circuit state for each
+// outbound provider.
+func (s *Server) handleProviderHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"providers": provider.Health()})
+}
+
+// stayQuery dates a stay by the travel window, when one was chosen.
+func (s *Server) stayQuery(windowID string, partySize int) provider.StayQuery {
+	q := provider.StayQuery{Guests: max(partySize, 1)}
+	for _, window := range s.store.ListTravelWindows("", "") {
+		if window.ID == windowID {
+			q.CheckIn, q.CheckOut = window.StartDate, window.EndDate
+		}
+	}
+	return q
 }
 
 func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
@@ -207,8 +520,12 @@ func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
+	// Outbound and Return are the chosen journeys, optional; their real times
+	// can reach past the window's dates.
 	var req struct {
-		WindowID string `json:"windowId"`
+		WindowID string                  `json:"windowId"`
+		Outbound *domain.TransportOption `json:"outbound"`
+		Return   *domain.TransportOption `json:"return"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeErr(w, http.StatusBadRequest, "invalid json")
@@ -218,14 +535,67 @@ func (s *Server) handleConflicts(w http.ResponseWriter, r *http.Request) {
 		writeErr(w, http.StatusBadRequest, "missing windowId")
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"alerts": s.store.EvaluateConflicts(req.WindowID)})
+	userID := auth.UserIDFromContext(r.Context())
+	alerts := s.store.EvaluateMemberConflicts(userID, req.WindowID)
+	if req.Outbound != nil || req.Return != nil {
+		for _, window := range s.store.ListTravelWindows("", "") {
+			if window.ID == req.WindowID {
+				alerts = append(alerts, planner.JourneyConflicts(s.store.ListAcademicEvents(userID), window, req.Outbound, req.Return)...)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"alerts": alerts})
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+
+	if r.Method == http.MethodGet {
+		profile := s.store.GetProfile(userID)
+		if profile.Passes == nil {
+			profile.Passes = []domain.TravelPass{}
+		}
+		writeJSON(w, http.StatusOK, profile)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var req domain.UserProfile
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		if req.MaxTravelHours < 0 {
+			writeErr(w, http.StatusBadRequest, "maxTravelHours must not be negative")
+			return
+		}
+		for _, pass := range req.Passes {
+			if _, err := fares.PassFor(pass); err != nil {
+				writeErr(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		req.HomeCurrency = fx.Normalize(req.HomeCurrency)
+		if _, err := s.rates().Rate(req.HomeCurrency, ""); err != nil {
+			writeErr(w, http.StatusBadRequest, "unsupported homeCurrency "+req.HomeCurrency)
+			return
+		}
+		if req.Passes == nil {
+			req.Passes = []domain.TravelPass{}
+		}
+		req.UserID = userID
+		writeJSON(w, http.StatusOK, s.store.SaveProfile(req))
+		return
+	}
+
+	writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -234,6 +604,22 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// writeStoreErr maps domain sentinel errors returned by the store to HTTP codes.
+func writeStoreErr(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeErr(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		writeErr(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrInvalid):
+		writeErr(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		writeErr(w, http.StatusConflict, err.Error())
+	default:
+		writeErr(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
 func writeErr(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
@@ -2,11 +2,22 @@ package httpapi
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
 	"exchange-travel-planner/backend/internal/store"
 )
 
@@ -257,6 +268,33 @@ func TestConflictsEvaluate(t *testing.T) {
 	}
 }
 
+func TestConflictsEvaluate_JourneyTimes(t *testing.T) {
+	_, h := setup()
+	body := `{"windowId":"w-2","outbound":{"mode":"train","departure":"2026-03-18T07:05:00+01:00"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/conflicts/evaluate", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Alerts []domain.ConflictAlert `json:"alerts"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, alert := range resp.Alerts {
+		if alert.RelatedEventID == "ev-1" && alert.Severity == domain.SeverityHighRisk {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the early outbound to clash with the midterm, got %+v", resp.Alerts)
+	}
+}
+
 func TestConflictsEvaluate_MissingWindowID(t *testing.T) {
 	_, h := setup()
 	body := `{"windowId":""}`
@@ -278,3 +316,1128 @@ func TestTripRoutes_ShortPath(t *testing.T) {
 		t.Fatalf("expected 404, got %d", w.Code)
 	}
 }
+
+func TestTripOptimize_GroupMode(t *testing.T) {
+	_, h := setup()
+	body := `{"mode":"group","tripId":"trip-1","windowIds":["w-3"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Mode    string                   `json:"mode"`
+		Options []map[string]interface{} `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Mode != "group" || len(resp.Options) != 4 {
+		t.Fatalf("expected 4 group options, got %+v", resp)
+	}
+}
+
+func TestTripOptimize_GroupModeConvertsCurrency(t *testing.T) {
+	_, h := setup()
+	optimize := func(currency string) domain.GroupTripOption {
+		t.Helper()
+		body := `{"mode":"group","tripId":"trip-1","windowIds":["w-3"],"currency":"` + currency + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Options []domain.GroupTripOption `json:"options"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		if w.Code != 200 || len(resp.Options) == 0 {
+			t.Fatalf("expected group options, got %d %s", w.Code, w.Body.String())
+		}
+		return resp.Options[0]
+	}
+	eur, czk := optimize("EUR"), optimize("CZK")
+	if eur.Currency != "EUR" || czk.Currency != "CZK" {
+		t.Fatalf("expected labelled currencies, got %q and %q", eur.Currency, czk.Currency)
+	}
+	if czk.TotalEstimatedCost != eur.TotalEstimatedCost.Mul(25.2) || czk.Members[0].EstimatedCost != eur.Members[0].EstimatedCost.Mul(25.2) {
+		t.Fatalf("expected costs at 25.2 CZK/EUR, got %+v and %+v", eur, czk)
+	}
+}
+
+func TestTripOptimize_GroupModeMissingTrip(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(`{"mode":"group"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestProfile_PutAndGet(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPut, "/api/profile", bytes.NewBufferString(`{"homeCity":"Munich","style":"nature"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var profile map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&profile)
+	if profile["homeCity"] != "Munich" || profile["userId"] != "demo-user" {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+}
+
+func TestProfile_PassesPriceSearches(t *testing.T) {
+	s, h := setup()
+	s.transport = provider.NewRegistry(time.Second)
+	req := httptest.NewRequest(http.MethodPut, "/api/profile", bytes.NewBufferString(`{"passes":[{"type":"golden-ticket"}]}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400 for an unknown pass, got %d", w.Code)
+	}
+	req = httptest.NewRequest(http.MethodPut, "/api/profile", bytes.NewBufferString(`{"passes":[{"type":"interrail","validUntil":"2026-06-30"}]}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 || len(s.store.GetProfile("demo-user").Passes) != 1 {
+		t.Fatalf("expected the pass to be saved, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The holder's seat on the seeded train is covered; the flight is not.
+	seeded := s.store.SearchTransport("Berlin", "Prague")
+	req = httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague&date=2026-03-06&passengers=2", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var body struct {
+		Options []domain.TransportOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if len(body.Options) != 2 || body.Options[0].Price != seeded[0].Price || len(body.Options[0].Passes) != 1 {
+		t.Fatalf("expected the train priced for one, got %+v", body.Options)
+	}
+	if body.Options[1].Price != seeded[1].Price*2 || body.Options[1].Passes != nil {
+		t.Fatalf("expected the flight priced for two, got %+v", body.Options[1])
+	}
+
+	// After the pass expires both travellers pay.
+	req = httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague&date=2026-07-06&passengers=2", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Options[0].Price != seeded[0].Price*2 {
+		t.Fatalf("expected the expired pass to be ignored, got %+v", body.Options[0])
+	}
+}
+
+func TestBudgetEntries_GetTotalsConverted(t *testing.T) {
+	_, h := setup()
+	body := `{"category":"food","amount":2520,"currency":"CZK","date":"2026-02-12"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/entries", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		Totals struct {
+			Currency   string             `json:"currency"`
+			Total      float64            `json:"total"`
+			ByCategory map[string]float64 `json:"byCategory"`
+		} `json:"totals"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Totals.Currency != "EUR" || resp.Totals.Total != 580 || resp.Totals.ByCategory["food"] != 100 {
+		t.Fatalf("unexpected totals %+v", resp.Totals)
+	}
+}
+
+func TestBudgetEntries_PostUnsupportedCurrency(t *testing.T) {
+	_, h := setup()
+	body := `{"category":"food","amount":5,"currency":"XYZ","date":"2026-02-12"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTripOptimize_ConvertsCurrency(t *testing.T) {
+	_, h := setup()
+	body := `{"budgetCap":8000,"maxTravelHours":6,"partySize":1,"style":"culture","currency":"CZK"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		Options []struct {
+			Currency           string   `json:"currency"`
+			TotalEstimatedCost float64  `json:"totalEstimatedCost"`
+			ReasonTags         []string `json:"reasonTags"`
+		} `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Options) == 0 || resp.Options[0].Currency != "CZK" {
+		t.Fatalf("expected CZK options, got %+v", resp.Options)
+	}
+	if resp.Options[0].TotalEstimatedCost < 1000 {
+		t.Fatalf("expected cost in CZK, got %v", resp.Options[0].TotalEstimatedCost)
+	}
+}
+
+func TestConvertTripOptions_ConvertsReservationFees(t *testing.T) {
+	legs := []domain.TransportLeg{{Kind: domain.LegRide, Mode: "train", CoveredBy: "Interrail", ReservationFee: money.FromFloat(10)}}
+	options := []domain.TripOption{{
+		Destination: "Milan", TotalEstimatedCost: money.FromFloat(100), Currency: "EUR",
+		TransportOptions: []domain.TransportOption{{Mode: "train", Price: money.FromFloat(10), Legs: legs}},
+	}}
+	rates := fx.NewTable([]domain.FXRate{{Date: "2026-01-02", Currency: "CHF", Rate: 0.94}})
+	out := convertTripOptions(rates, options, "CHF")
+	if fee := out[0].TransportOptions[0].Legs[0].ReservationFee; fee != money.FromFloat(9.4) {
+		t.Fatalf("expected the reservation fee in CHF, got %s", fee)
+	}
+	if legs[0].ReservationFee != money.FromFloat(10) {
+		t.Fatalf("expected the provider's legs untouched, got %+v", legs)
+	}
+}
+
+func TestFXRates(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodGet, "/api/fx/rates?date=2026-02-01", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestBudgetSettings_PutAndGet(t *testing.T) {
+	_, h := setup()
+	body := `{"monthlyBudget":1000,"allocations":{"living":600,"travel":300},"currency":"eur","startDay":15}`
+	req := httptest.NewRequest(http.MethodPut, "/api/budget/settings", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/settings", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var settings struct {
+		MonthlyBudget float64            `json:"monthlyBudget"`
+		Allocations   map[string]float64 `json:"allocations"`
+		Currency      string             `json:"currency"`
+		StartDay      int                `json:"startDay"`
+	}
+	json.NewDecoder(w.Body).Decode(&settings)
+	if settings.MonthlyBudget != 1000 || settings.Currency != "EUR" || settings.StartDay != 15 || settings.Allocations["travel"] != 300 {
+		t.Fatalf("unexpected settings %+v", settings)
+	}
+}
+
+func TestBudgetSettings_Validation(t *testing.T) {
+	_, h := setup()
+	cases := []string{
+		`{"monthlyBudget":0}`,
+		`{"monthlyBudget":500,"startDay":31}`,
+		`{"monthlyBudget":500,"currency":"XYZ"}`,
+		`{"monthlyBudget":500,"allocations":{"living":400,"travel":200}}`,
+	}
+	for _, body := range cases {
+		req := httptest.NewRequest(http.MethodPut, "/api/budget/settings", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestBudgetForecast_AsOf(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodGet, "/api/budget/forecast?asOf=2026-02-10", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var f struct {
+		PeriodStart string            `json:"periodStart"`
+		SpentToDate float64           `json:"spentToDate"`
+		Daily       []json.RawMessage `json:"daily"`
+	}
+	json.NewDecoder(w.Body).Decode(&f)
+	if f.PeriodStart != "2026-02-01" || f.SpentToDate != 480 || len(f.Daily) != 28 {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/forecast?asOf=tomorrow", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestBudgetEntries_GetFiltered(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodGet, "/api/budget/entries?category=travel&limit=1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Entries []map[string]interface{} `json:"entries"`
+		Total   int                      `json:"total"`
+		Limit   int                      `json:"limit"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Total != 1 || len(resp.Entries) != 1 || resp.Limit != 1 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
+func TestBudgetEntries_GetBadFilter(t *testing.T) {
+	_, h := setup()
+	for _, q := range []string{"from=yesterday", "minAmount=lots", "limit=0", "offset=-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/budget/entries?"+q, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestBudgetEntry_PatchAndDelete(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPatch, "/api/budget/entries/b-2", bytes.NewBufferString(`{"amount":65,"note":"Train to Vienna (return)"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var entry map[string]interface{}
+	json.NewDecoder(w.Body).Decode(&entry)
+	if entry["amount"] != 65.0 || entry["category"] != "travel" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/budget/entries/b-2", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 204 {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/budget/entries/b-2", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestBudgetEntry_PatchValidation(t *testing.T) {
+	_, h := setup()
+	for _, body := range []string{`{"amount":-1}`, `{"date":"Feb 5"}`, `{"category":""}`, `{"currency":"XYZ"}`} {
+		req := httptest.NewRequest(http.MethodPatch, "/api/budget/entries/b-1", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestRecurringEntries_CreateMaterializesDue(t *testing.T) {
+	_, h := setup()
+	body := `{"category":"living","amount":35,"currency":"eur","note":"Transit pass","frequency":"monthly","startDate":"2026-01-10","endDate":"2026-02-10"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/recurring", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created domain.RecurringEntry
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.ID == "" || created.Interval != 1 || created.Currency != "EUR" || created.MaterializedThrough == "" {
+		t.Fatalf("unexpected template %+v", created)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/entries?q=transit", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var page domain.BudgetEntryPage
+	json.NewDecoder(w.Body).Decode(&page)
+	if page.Total != 2 || page.Entries[0].RecurringID != created.ID {
+		t.Fatalf("expected two generated entries, got %+v", page)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/budget/recurring/"+created.ID+"/occurrences/2026-02-10", bytes.NewBufferString(`{"skip":true}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/budget/recurring/"+created.ID, nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 204 {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/entries?q=transit", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	page = domain.BudgetEntryPage{}
+	json.NewDecoder(w.Body).Decode(&page)
+	if page.Total != 1 {
+		t.Fatalf("expected the skipped entry removed and the other kept, got %+v", page)
+	}
+}
+
+func TestRecurringEntries_Validation(t *testing.T) {
+	_, h := setup()
+	for _, body := range []string{
+		`{"category":"living","amount":35,"frequency":"daily","startDate":"2026-01-10"}`,
+		`{"category":"living","amount":35,"frequency":"weekly","startDate":"10/01/2026"}`,
+		`{"category":"living","amount":35,"frequency":"weekly","startDate":"2026-01-10","endDate":"2026-01-01"}`,
+		`{"category":"living","amount":35,"frequency":"weekly","interval":13,"startDate":"2026-01-10"}`,
+		`{"category":"living","amount":0,"frequency":"weekly","startDate":"2026-01-10"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/budget/recurring", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+}
+
+func postImport(t *testing.T, h http.Handler, payload map[string]any) (int, map[string]any) {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/import", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp map[string]any
+	json.NewDecoder(w.Body).Decode(&resp)
+	return w.Code, resp
+}
+
+func TestBudgetImport_DryRunThenCommit(t *testing.T) {
+	_, h := setup()
+	data := "date,amount,currency,description\n" +
+		"2026-02-05,-420.00,EUR,Rent split\n" +
+		"2026-02-10,-18.40,EUR,Lidl Neukoelln\n" +
+		"2026-02-11,-350,CZK,Vinarna Prague\n" +
+		"2026-02-12,25.00,EUR,Refund\n"
+	payload := map[string]any{"data": data, "dryRun": true, "rules": []map[string]string{{"keyword": "vinarna", "category": "food"}}}
+
+	code, resp := postImport(t, h, payload)
+	if code != 200 {
+		t.Fatalf("expected 200, got %d: %v", code, resp)
+	}
+	summary := resp["summary"].(map[string]any)
+	if summary["new"] != 2.0 || summary["duplicates"] != 1.0 || summary["ignored"] != 1.0 {
+		t.Fatalf("unexpected summary %v", summary)
+	}
+	if len(resp["imported"].([]any)) != 0 {
+		t.Fatalf("dry run must not import")
+	}
+
+	payload["dryRun"] = false
+	code, resp = postImport(t, h, payload)
+	if code != 201 || len(resp["imported"].([]any)) != 2 {
+		t.Fatalf("expected 2 imported entries, got %d: %v", code, resp)
+	}
+
+	// Importing the same statement again finds only duplicates.
+	code, resp = postImport(t, h, payload)
+	summary = resp["summary"].(map[string]any)
+	if code != 200 || summary["new"] != 0.0 || summary["duplicates"] != 3.0 {
+		t.Fatalf("expected re-import to be all duplicates, got %d: %v", code, summary)
+	}
+}
+
+func TestBudgetImport_BadRequests(t *testing.T) {
+	_, h := setup()
+	for _, payload := range []map[string]any{
+		{"data": ""},
+		{"data": "x", "format": "ofx"},
+		{"data": "x", "profile": "unknown-bank"},
+		{"data": "foo,bar\n1,2\n"},
+		{"data": "<Document>", "format": "camt053"},
+	} {
+		if code, resp := postImport(t, h, payload); code != 400 {
+			t.Fatalf("expected 400 for %v, got %d: %v", payload, code, resp)
+		}
+	}
+}
+
+func TestImportRules_SavedRulesApply(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPut, "/api/budget/import/rules", bytes.NewBufferString(`{"rules":[{"keyword":"spotify","category":"subscriptions"}]}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	_, resp := postImport(t, h, map[string]any{"data": "date,amount,currency,description\n2026-02-14,-10.99,EUR,SPOTIFY AB\n", "dryRun": true})
+	rows := resp["rows"].([]any)
+	if category := rows[0].(map[string]any)["category"]; category != "subscriptions" {
+		t.Fatalf("expected saved rule to apply, got %v", category)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/budget/import/rules", bytes.NewBufferString(`{"rules":[{"keyword":" ","category":"x"}]}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 400 {
+		t.Fatalf("expected 400 for an empty keyword, got %d", w.Code)
+	}
+}
+
+func addExportEntries(t *testing.T, h http.Handler) {
+	t.Helper()
+	for _, body := range []string{
+		`{"category":"food","amount":252,"currency":"CZK","date":"2026-03-07","tripId":"trip-1","note":"Dinner in Prague"}`,
+		`{"category":"food","amount":5,"currency":"EUR","date":"2026-03-09","note":"=HYPERLINK(\"x\")"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 201 {
+			t.Fatalf("add entry: %d %s", w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestBudgetExport_CSV(t *testing.T) {
+	_, h := setup()
+	addExportEntries(t, h)
+	req := httptest.NewRequest(http.MethodGet, "/api/budget/export?from=2026-03-01&to=2026-03-31", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected csv, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "budget-2026-03-01-2026-03-31.csv") {
+		t.Fatalf("unexpected Content-Disposition %q", cd)
+	}
+	reader := csv.NewReader(strings.NewReader(w.Body.String()))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	// header, 2 entries, blank line skipped by the reader, totals header, 1 total
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d: %v", len(records), records)
+	}
+	if row := records[1]; row[5] != "10.00" || row[6] != "EUR" || row[8] != "Prague" {
+		t.Fatalf("unexpected converted row %v", row)
+	}
+	if note := records[2][10]; note != `'=HYPERLINK("x")` {
+		t.Fatalf("expected formula to be escaped, got %q", note)
+	}
+	if total := records[4]; total[0] != "food" || total[1] != "15.00" {
+		t.Fatalf("unexpected totals %v", total)
+	}
+}
+
+func TestBudgetExport_JSON(t *testing.T) {
+	_, h := setup()
+	addExportEntries(t, h)
+	req := httptest.NewRequest(http.MethodGet, "/api/budget/export?format=json", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Currency string                 `json:"currency"`
+		Entries  []map[string]any       `json:"entries"`
+		Totals   []domain.CategorySpend `json:"totals"`
+		Count    int                    `json:"count"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Count != 4 || len(resp.Entries) != 4 || resp.Currency != "EUR" {
+		t.Fatalf("unexpected export %+v", resp)
+	}
+	if resp.Entries[0]["date"] != "2026-02-05" || resp.Entries[2]["tripDestination"] != "Prague" {
+		t.Fatalf("expected entries in date order with destinations, got %v", resp.Entries)
+	}
+	if len(resp.Totals) != 3 || resp.Totals[0].Category != "food" || resp.Totals[0].Spent != money.FromFloat(15) {
+		t.Fatalf("unexpected totals %+v", resp.Totals)
+	}
+}
+
+func TestBudgetExport_BadParams(t *testing.T) {
+	_, h := setup()
+	for _, q := range []string{"format=xlsx", "from=March"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/budget/export?"+q, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestTripReconciliation(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/reconciliation", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 409 {
+		t.Fatalf("expected 409 without linked entries, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(`{"category":"hostel","amount":70,"currency":"EUR","date":"2026-03-06","tripId":"trip-1"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/trips/trip-1/reconciliation", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var report domain.TripReconciliation
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.Finalized || report.EstimatedTotal != money.FromFloat(220) || report.ActualTotal != money.FromFloat(70) || len(report.Lines) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/reconciliation", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	report = domain.TripReconciliation{}
+	json.NewDecoder(w.Body).Decode(&report)
+	if w.Code != 200 || !report.Finalized {
+		t.Fatalf("expected finalised report, got %d %+v", w.Code, report)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/trips/missing/reconciliation", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestAlerts_TriggeredByEntry(t *testing.T) {
+	_, h := setup()
+	for _, body := range []string{
+		`{"kind":"expense-over","threshold":100}`,
+		`{"kind":"expense-over","threshold":100,"currency":"PLN"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/alerts/rules", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 201 {
+			t.Fatalf("expected 201 for %s, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	// 60 EUR is over 100 PLN but not over 100 EUR.
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(`{"category":"food","amount":60,"currency":"EUR","date":"2026-03-05"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodPost, "/api/budget/entries", bytes.NewBufferString(`{"category":"food","amount":5,"currency":"EUR","date":"2026-03-05"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/alerts?unread=true", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		Alerts []domain.Alert `json:"alerts"`
+		Unread int            `json:"unread"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != 200 || resp.Unread != 1 || len(resp.Alerts) != 1 || resp.Alerts[0].Kind != domain.AlertExpenseOver {
+		t.Fatalf("expected one expense alert, got %d %+v", w.Code, resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/alerts/read", bytes.NewBufferString(`{}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"marked":1`) {
+		t.Fatalf("expected 1 marked, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAlerts_TripIntoRedOnShare(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPut, "/api/budget/settings", bytes.NewBufferString(`{"monthlyBudget":100,"currency":"EUR"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodPost, "/api/alerts/rules", bytes.NewBufferString(`{"kind":"trip-into-red"}`))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	for i := 0; i < 2; i++ {
+		req = httptest.NewRequest(http.MethodPost, "/api/trips/trip-1/share", bytes.NewBufferString(`{"memberIds":["friend-1"]}`))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/alerts", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		Alerts []domain.Alert `json:"alerts"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Alerts) != 1 || resp.Alerts[0].TripID != "trip-1" || resp.Alerts[0].Severity != domain.SeverityHighRisk {
+		t.Fatalf("expected a single trip alert, got %+v", resp.Alerts)
+	}
+}
+
+func TestAlertRules_Validation(t *testing.T) {
+	_, h := setup()
+	for _, body := range []string{
+		`{"kind":"nope"}`,
+		`{"kind":"category-over","threshold":80}`,
+		`{"kind":"expense-over","threshold":0}`,
+		`{"kind":"remaining-under","threshold":50,"currency":"XXX"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/alerts/rules", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", body, w.Code)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPut, "/api/alerts/rules/missing", bytes.NewBufferString(`{"kind":"trip-into-red"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestSavingsGoals_DefaultsFromTripAndForecast(t *testing.T) {
+	_, h := setup()
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/goals", bytes.NewBufferString(`{"tripId":"trip-1"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var goal domain.SavingsGoal
+	json.NewDecoder(w.Body).Decode(&goal)
+	if goal.Name != "Prague" || goal.Target.String() != "220.00" || goal.TargetDate != "2026-03-06" || goal.Currency != "EUR" {
+		t.Fatalf("expected defaults from trip-1, got %+v", goal)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/budget/goals/"+goal.ID+"/contributions", bytes.NewBufferString(`{"amount":20,"date":"2026-02-01"}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 201 {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/budget/forecast?asOf=2026-02-10", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var f domain.ForecastResult
+	json.NewDecoder(w.Body).Decode(&f)
+	// 220 was missing on February 1st, spread over February and March.
+	if len(f.Goals) != 1 || f.Goals[0].MonthlyNeeded != money.FromFloat(110) || f.Goals[0].SavedThisPeriod != money.FromFloat(20) || f.SavingsNeeded != money.FromFloat(90) {
+		t.Fatalf("unexpected goal progress %+v", f.Goals)
+	}
+}
+
+func TestSavingsGoals_Validation(t *testing.T) {
+	_, h := setup()
+	for body, code := range map[string]int{
+		`{"tripId":"missing"}`:                          404,
+		`{"tripId":"trip-1","target":-5}`:               400,
+		`{"tripId":"trip-1","targetDate":"next month"}`: 400,
+		`{"tripId":"trip-1","currency":"XXX"}`:          400,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/budget/goals", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Fatalf("expected %d for %s, got %d", code, body, w.Code)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/budget/goals/nope/contributions", bytes.NewBufferString(`{"amount":5}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 404 {
+		t.Fatalf("expected 404 for unknown goal, got %d", w.Code)
+	}
+}
+
+func TestSearchTransport_ReportsSources(t *testing.T) {
+	s, h := setup()
+	s.transport = provider.NewRegistry(time.Second)
+	s.transport.Register("failing", failingProvider{})
+	req := httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var body struct {
+		Options []domain.TransportOption `json:"options"`
+		Sources []provider.SourceStatus  `json:"sources"`
+		Partial bool                     `json:"partial"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	// The failing provider is reported and the seeded adapters fill in,
+	// labelled as synthetic.
+	if len(body.Options) == 0 || len(body.Sources) != 2 || !body.Partial {
+		t.Fatalf("unexpected response %+v", body)
+	}
+	if body.Sources[0].Status != provider.StatusError || body.Sources[0].Error != "provider unavailable" {
+		t.Fatalf("expected the failure and its reason, got %+v", body.Sources[0])
+	}
+	if seed := body.Sources[1]; seed.Provider != provider.SeedSource || seed.Status != provider.StatusSynthetic || seed.Options != len(body.Options) {
+		t.Fatalf("expected the seed fallback as a synthetic source, got %+v", seed)
+	}
+	for _, option := range body.Options {
+		if !option.Synthetic {
+			t.Fatalf("expected fallback options to be flagged synthetic, got %+v", option)
+		}
+	}
+}
+
+func TestSearchTransport_LiveOptionsAreNotSynthetic(t *testing.T) {
+	s, h := setup()
+	s.transport = provider.NewRegistry(time.Second)
+	s.transport.Register("failing", failingProvider{})
+	s.transport.Register("rail", providerOptions{{Provider: "rail", Mode: "train", Price: money.FromFloat(30)}})
+	req := httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var body struct {
+		Options []domain.TransportOption `json:"options"`
+		Sources []provider.SourceStatus  `json:"sources"`
+		Partial bool                     `json:"partial"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if len(body.Options) != 1 || body.Options[0].Synthetic || len(body.Sources) != 2 || !body.Partial {
+		t.Fatalf("expected one live option with a partial result, got %+v", body)
+	}
+}
+
+type providerOptions []domain.TransportOption
+
+func (p providerOptions) SearchTransport(context.Context, provider.TransportQuery) ([]domain.TransportOption, error) {
+	return p, nil
+}
+
+type failingProvider struct{}
+
+func (failingProvider) SearchTransport(context.Context, provider.TransportQuery) ([]domain.TransportOption, error) {
+	return nil, errors.New("provider unavailable")
+}
+
+func TestSearchStays_UsesProvider(t *testing.T) {
+	s, h := setup()
+	stays, err := provider.NewFixtureStayProvider([]byte(`{"Prague":[{"name":"Bunks","kind":"hostel","nightlyPrice":20,"rating":4}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.stays = stays
+	req := httptest.NewRequest(http.MethodGet, "/api/search/stays?city=Prague&checkIn=2026-03-06&checkOut=2026-03-09&guests=2", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var body struct {
+		Options []domain.StayOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if w.Code != 200 || len(body.Options) != 1 || body.Options[0].TotalPrice.String() != "120.00" {
+		t.Fatalf("expected the fixture stay for 2 guests x 3 nights, got %d %+v", w.Code, body.Options)
+	}
+
+	// Cities the provider does not know fall back to the seeded stays.
+	req = httptest.NewRequest(http.MethodGet, "/api/search/stays?city=Budapest", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var fallback struct {
+		Options []domain.StayOption     `json:"options"`
+		Sources []provider.SourceStatus `json:"sources"`
+		Partial bool                    `json:"partial"`
+	}
+	json.NewDecoder(w.Body).Decode(&fallback)
+	if len(fallback.Options) != 2 || fallback.Options[0].Provider != "HostelGraph" || !fallback.Options[0].Synthetic {
+		t.Fatalf("expected synthetic seeded stays, got %+v", fallback.Options)
+	}
+	if len(fallback.Sources) != 2 || fallback.Sources[0].Status != provider.StatusError || fallback.Sources[1].Status != provider.StatusSynthetic || !fallback.Partial {
+		t.Fatalf("expected the provider failure and the seed fallback, got %+v", fallback)
+	}
+
+	for _, q := range []string{"city=Prague&checkIn=2026-03-06", "city=Prague&guests=0", "city=Prague&checkIn=2026-03-09&checkOut=2026-03-06"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/search/stays?"+q, nil)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", q, w.Code)
+		}
+	}
+}
+
+func TestOptimize_EnrichesStaysForWindow(t *testing.T) {
+	s, h := setup()
+	stays, _ := provider.NewFixtureStayProvider([]byte(`{"Prague":[{"name":"Bunks","kind":"hostel","nightlyPrice":10}]}`))
+	s.stays = stays
+	body := `{"budgetCap":400,"maxTravelHours":6,"partySize":2,"style":"culture","windowId":"w-3","currency":"EUR"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var resp struct {
+		Options []domain.TripOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	for _, opt := range resp.Options {
+		if opt.Destination != "Prague" {
+			continue
+		}
+		// Window w-3 is three nights: 10 x 2 guests x 3 nights.
+		if opt.StayCost.String() != "60.00" || opt.StayOptions[0].Name != "Bunks" {
+			t.Fatalf("expected provider stays on Prague, got %+v", opt)
+		}
+		return
+	}
+	t.Fatalf("no Prague option in %+v", resp.Options)
+}
+
+func TestOptimize_PricesLiveFlightsWithBaggage(t *testing.T) {
+	optimize := func(h http.Handler) domain.TripOption {
+		t.Helper()
+		body := `{"budgetCap":400,"maxTravelHours":6,"partySize":2,"style":"culture","windowId":"w-3","currency":"EUR"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		var resp struct {
+			Options []domain.TripOption `json:"options"`
+		}
+		json.NewDecoder(w.Body).Decode(&resp)
+		for _, opt := range resp.Options {
+			if opt.Destination == "Prague" {
+				return opt
+			}
+		}
+		t.Fatalf("no Prague option in %+v", resp.Options)
+		return domain.TripOption{}
+	}
+	_, h := setup()
+	seeded := optimize(h)
+
+	s, h := setup()
+	fixture, err := provider.FlightFixtureHandler(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(fixture)
+	defer srv.Close()
+	s.transport = provider.NewRegistry(time.Second)
+	s.transport.Register("flights", provider.NewFlightProvider("SkyAPI", srv.URL, srv.Client()))
+	live := optimize(h)
+
+	// The cheapest flight from Berlin is two fares of 34.99 and a cabin bag
+	// each at 14.
+	cheapest := seeded.TransportOptions[0].Price
+	for _, o := range seeded.TransportOptions {
+		cheapest = min(cheapest, o.Price)
+	}
+	if len(live.TransportOptions) != 3 || live.TransportOptions[0].Mode != "flight" || live.TransportOptions[0].BaggageCost != money.FromFloat(28) {
+		t.Fatalf("expected flights with baggage, got %+v", live.TransportOptions)
+	}
+	if want := seeded.TotalEstimatedCost - cheapest + money.FromFloat(97.98); live.TotalEstimatedCost != want {
+		t.Fatalf("expected total %s, got %s", want, live.TotalEstimatedCost)
+	}
+}
+
+func TestSearchTransport_DatedQueryValidation(t *testing.T) {
+	s, h := setup()
+	var got provider.TransportQuery
+	s.transport = provider.NewRegistry(time.Second)
+	s.transport.Register("capture", captureProvider{&got})
+	req := httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague&date=2026-03-06&time=08:15&arriveBy=true&passengers=3&youth=1&students=1&checkedBags=1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := provider.TransportQuery{From: "Berlin", To: "Prague", Date: "2026-03-06", Time: "08:15", ArriveBy: true, Passengers: 3, Youth: 1, Students: 1, CabinBags: 3, CheckedBags: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("provider got %+v, want %+v", got, want)
+	}
+	var body struct {
+		Options []domain.TransportOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	// The seeded fallback is priced for all three passengers: an adult, a
+	// youth at 20% off and a student at 10% off.
+	seeded := s.store.SearchTransport("Berlin", "Prague")
+	adult := seeded[0].Price
+	if want := adult + adult.Mul(0.8) + adult.Mul(0.9); len(body.Options) == 0 || body.Options[0].Price != want {
+		t.Fatalf("expected fallback priced %s for the party, got %+v", want, body.Options)
+	}
+
+	for _, q := range []string{"date=06.03.2026", "time=8pm", "passengers=0", "passengers=10", "youth=-1", "passengers=2&youth=2&students=1", "cabinBags=-1", "checkedBags=x"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/search/transport?to=Prague&"+q, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != 400 {
+			t.Fatalf("expected 400 for %s, got %d", q, w.Code)
+		}
+	}
+}
+
+type captureProvider struct{ got *provider.TransportQuery }
+
+func (p captureProvider) SearchTransport(_ context.Context, q provider.TransportQuery) ([]domain.TransportOption, error) {
+	*p.got = q
+	return nil, errors.New("no journeys")
+}
+
+// replaySetup builds the server from the environment with every outbound
+// provider replayed from testdata/provider-fixtures. Running the tests with
+// PROVIDER_FIXTURE_MODE=record and the providers' base URLs set records the
+// fixtures afresh.
+func replaySetup(t *testing.T) http.Handler {
+	t.Helper()
+	recording := os.Getenv("PROVIDER_FIXTURE_MODE") == string(provider.FixtureRecord)
+	for key, value := range map[string]string{
+		"PROVIDER_FIXTURE_MODE":    string(provider.FixtureReplay),
+		"PROVIDER_FIXTURE_DIR":     "testdata/provider-fixtures",
+		"TRANSPORT_PROVIDERS":      "opendata,flights",
+		"REAL_PROVIDER_ENABLED":    "true",
+		"REAL_PROVIDER_BASE_URL":   "http://opendata.invalid",
+		"FLIGHT_PROVIDER_BASE_URL": "http://flights.invalid",
+		"STAY_PROVIDER":            "http",
+		"STAY_PROVIDER_BASE_URL":   "http://stays.invalid",
+	} {
+		if recording && os.Getenv(key) != "" {
+			continue
+		}
+		t.Setenv(key, value)
+	}
+	return NewServer(store.New()).Routes()
+}
+
+func TestReplay_SearchTransport(t *testing.T) {
+	search := func() ([]domain.TransportOption, []provider.SourceStatus) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/search/transport?from=Berlin&to=Prague&date=2026-03-06&passengers=2", nil)
+		w := httptest.NewRecorder()
+		replaySetup(t).ServeHTTP(w, req)
+		var body struct {
+			Options []domain.TransportOption `json:"options"`
+			Sources []provider.SourceStatus  `json:"sources"`
+		}
+		json.NewDecoder(w.Body).Decode(&body)
+		if w.Code != 200 {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return body.Options, body.Sources
+	}
+	options, sources := search()
+	if len(sources) != 2 {
+		t.Fatalf("expected both providers as sources, got %+v", sources)
+	}
+	for _, source := range sources {
+		if source.Status != provider.StatusOK || source.Options == 0 {
+			t.Fatalf("expected every provider to answer from its fixtures, got %+v", sources)
+		}
+	}
+	modes := map[string]bool{}
+	for _, o := range options {
+		if o.Synthetic {
+			t.Fatalf("expected only live options, got %+v", o)
+		}
+		modes[o.Mode] = true
+	}
+	if !modes["train"] || !modes["flight"] {
+		t.Fatalf("expected trains and flights, got %+v", options)
+	}
+	// Replays answer the same every time.
+	if again, _ := search(); !reflect.DeepEqual(again, options) {
+		t.Fatalf("replay changed between runs:\n%+v\n%+v", options, again)
+	}
+}
+
+func TestReplay_SearchStays(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/search/stays?city=Prague&checkIn=2026-03-06&checkOut=2026-03-09&guests=2", nil)
+	w := httptest.NewRecorder()
+	replaySetup(t).ServeHTTP(w, req)
+	var body struct {
+		Options []domain.StayOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if w.Code != 200 || len(body.Options) == 0 {
+		t.Fatalf("expected recorded stays, got %d %s", w.Code, w.Body.String())
+	}
+	for _, o := range body.Options {
+		if o.Provider != "HostelAPI" || o.Synthetic || o.TotalPrice == 0 {
+			t.Fatalf("expected priced stays from the provider, got %+v", o)
+		}
+	}
+}
+
+func TestReplay_Optimize(t *testing.T) {
+	body := `{"budgetCap":400,"maxTravelHours":6,"partySize":2,"style":"culture","windowId":"w-3","currency":"EUR"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	replaySetup(t).ServeHTTP(w, req)
+	var resp struct {
+		Options []domain.TripOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, opt := range resp.Options {
+		if opt.Destination != "Prague" {
+			continue
+		}
+		for _, o := range opt.TransportOptions {
+			if o.Mode == "flight" && o.Provider == "FlightAPI" && o.BaggageCost > 0 {
+				return
+			}
+		}
+		t.Fatalf("expected recorded flights with baggage to Prague, got %+v", opt.TransportOptions)
+	}
+	t.Fatalf("no Prague option in %+v", resp.Options)
+}
+
+func TestReplay_OptimizeConvertsBaggage(t *testing.T) {
+	body := `{"budgetCap":400,"maxTravelHours":6,"partySize":2,"style":"culture","windowId":"w-3","currency":"GBP"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/trips/optimize", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	replaySetup(t).ServeHTTP(w, req)
+	var resp struct {
+		Options []domain.TripOption `json:"options"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, opt := range resp.Options {
+		if opt.Destination != "Prague" || opt.Currency != "GBP" {
+			continue
+		}
+		for _, o := range opt.TransportOptions {
+			if o.Provider != "FlightAPI" || o.Baggage == nil || o.Baggage.CabinBag.Included {
+				continue
+			}
+			// SkySaver's EUR 14 cabin and EUR 29 checked bags at 0.84, two cabin bags booked.
+			if o.Baggage.CabinBag.Fee != money.FromFloat(11.76) || o.Baggage.CheckedBag.Fee != money.FromFloat(24.36) ||
+				o.BaggageCost != money.FromFloat(23.52) {
+				t.Fatalf("expected baggage in GBP, got %+v %+v", o.Baggage, o)
+			}
+			return
+		}
+		t.Fatalf("expected recorded flights with paid bags to Prague, got %+v", opt.TransportOptions)
+	}
+	t.Fatalf("no Prague option in GBP in %+v", resp.Options)
+}
@@ -0,0 +1,140 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/auth"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+)
+
+const maxSavingsGoals = 20
+
+// handleSavingsGoals serves GET and POST on /api/budget/goals.
+func (s *Server) handleSavingsGoals(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.store.ListSavingsGoals(userID))
+	case http.MethodPost:
+		var req domain.SavingsGoal
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		req.UserID = userID
+		trip := s.store.GetTrip(req.TripID)
+		if trip == nil {
+			writeErr(w, http.StatusNotFound, "trip not found")
+			return
+		}
+		if !slices.Contains(trip.Members, userID) {
+			writeErr(w, http.StatusForbidden, "not a trip member")
+			return
+		}
+		if msg := s.validateSavingsGoal(&req, trip); msg != "" {
+			writeErr(w, http.StatusBadRequest, msg)
+			return
+		}
+		if len(s.store.ListSavingsGoals(userID)) >= maxSavingsGoals {
+			writeErr(w, http.StatusBadRequest, "too many savings goals")
+			return
+		}
+		writeJSON(w, http.StatusCreated, s.store.CreateSavingsGoal(req))
+	default:
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleSavingsGoal serves DELETE on /api/budget/goals/{id} and POST on
+// /api/budget/goals/{id}/contributions.
+func (s *Server) handleSavingsGoal(w http.ResponseWriter, r *http.Request) {
+	userID := auth.UserIDFromContext(r.Context())
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/budget/goals/"), "/"), "/")
+	id := parts[0]
+
+	switch {
+	case len(parts) == 1 && id != "":
+		if r.Method != http.MethodDelete {
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if err := s.store.DeleteSavingsGoal(userID, id); err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "contributions":
+		if r.Method != http.MethodPost {
+			writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var c domain.SavingsContribution
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			writeErr(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		if c.Amount == 0 {
+			writeErr(w, http.StatusBadRequest, "amount must not be zero")
+			return
+		}
+		if c.Date == "" {
+			c.Date = time.Now().UTC().Format("2006-01-02")
+		}
+		if _, err := time.Parse("2006-01-02", c.Date); err != nil {
+			writeErr(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+			return
+		}
+		c.Note = strings.TrimSpace(c.Note)
+		goal, err := s.store.AddSavingsContribution(userID, id, c)
+		if err != nil {
+			writeStoreErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, goal)
+	default:
+		writeErr(w, http.StatusNotFound, "not found")
+	}
+}
+
+// validateSavingsGoal fills in what the trip implies: its destination as the
+// name, its estimated cost as the target and its window's start as the date.
+func (s *Server) validateSavingsGoal(goal *domain.SavingsGoal, trip *domain.Trip) string {
+	goal.Name = strings.TrimSpace(goal.Name)
+	if goal.Name == "" {
+		goal.Name = trip.Destination
+	}
+	if goal.Currency == "" {
+		goal.Currency = s.homeCurrency(goal.UserID)
+	}
+	goal.Currency = fx.Normalize(goal.Currency)
+	if _, err := s.rates().Rate(goal.Currency, ""); err != nil {
+		return "unsupported currency " + goal.Currency
+	}
+	if goal.Target == 0 {
+		target, err := s.rates().ConvertAmount(trip.EstimatedCost, fx.Base, goal.Currency, "")
+		if err != nil {
+			return "target could not be derived from the trip estimate"
+		}
+		goal.Target = target
+	}
+	if goal.Target <= 0 {
+		return "target must be positive"
+	}
+	if goal.TargetDate == "" {
+		for _, window := range s.store.ListTravelWindows("", "") {
+			if window.ID == trip.WindowID {
+				goal.TargetDate = window.StartDate
+			}
+		}
+	}
+	if _, err := time.Parse("2006-01-02", goal.TargetDate); err != nil {
+		return "targetDate must be YYYY-MM-DD"
+	}
+	return ""
+}
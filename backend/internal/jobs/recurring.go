@@ -0,0 +1,35 @@
+// Package jobs runs periodic background work against the data store.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Materializer generates the budget entries recurring templates owe up to a day.
+type Materializer interface {
+	MaterializeRecurring(asOf string) int
+}
+
+// RunRecurring materialises recurring entries once immediately and then on
+// every tick until ctx is cancelled. now supplies the current day (UTC).
+func RunRecurring(ctx context.Context, m Materializer, every time.Duration, now func() time.Time) {
+	run := func() {
+		asOf := now().UTC().Format("2006-01-02")
+		if n := m.MaterializeRecurring(asOf); n > 0 {
+			log.Printf("recurring: generated %d budget entries through %s", n, asOf)
+		}
+	}
+	run()
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
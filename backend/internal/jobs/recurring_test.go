@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeMaterializer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeMaterializer) MaterializeRecurring(asOf string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, asOf)
+	return 1
+}
+
+func (f *fakeMaterializer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func TestRunRecurring_RunsImmediatelyAndOnTick(t *testing.T) {
+	f := &fakeMaterializer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	now := func() time.Time { return time.Date(2026, 3, 5, 23, 30, 0, 0, time.FixedZone("CET", 3600)) }
+	go func() {
+		RunRecurring(ctx, f, 5*time.Millisecond, now)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for f.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if f.count() < 3 {
+		t.Fatalf("expected at least 3 runs, got %d", f.count())
+	}
+	if f.calls[0] != "2026-03-05" {
+		t.Fatalf("expected the UTC day, got %s", f.calls[0])
+	}
+}
@@ -0,0 +1,231 @@
+// Package money holds monetary amounts exactly, as integer minor units, so
+// sums and splits never drift by a fraction of a cent.
+//
+// Amounts are kept in hundredths of the currency unit, the minor unit of
+// every currency the app budgets in. They encode to JSON as plain decimal
+// numbers (12.5 becomes 12.50), so clients that sent and read float64
+// amounts keep working.
+//
+// Domain types keep an Amount next to the Currency field it is in, or in a
+// currency their docs name (EUR for optimizer costs). Amounts in different
+// currencies are never added directly: they travel as Money until fx
+// converts them into one currency, and only then are summed.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of minor units in one currency unit.
+const Scale = 100
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	errSyntax           = errors.New("invalid amount")
+)
+
+// Amount is a quantity of money in minor units (cents).
+type Amount int64
+
+// FromFloat rounds v to the nearest cent, halves away from zero.
+func FromFloat(v float64) Amount {
+	return Amount(math.Round(v * Scale))
+}
+
+// Cents returns an Amount of n minor units.
+func Cents(n int64) Amount { return Amount(n) }
+
+// Parse reads a decimal string such as "12", "-0.5" or "1234.567". Digits
+// beyond the cent are rounded half away from zero.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "eE") {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) || math.Abs(v) > math.MaxInt64/Scale {
+			return 0, fmt.Errorf("%w %q", errSyntax, s)
+		}
+		return FromFloat(v), nil
+	}
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) || len(whole) > 16 {
+		return 0, fmt.Errorf("%w %q", errSyntax, s)
+	}
+	var n int64
+	for _, r := range whole {
+		n = n*10 + int64(r-'0')
+	}
+	for i := 0; i < 2; i++ {
+		n *= 10
+		if i < len(frac) {
+			n += int64(frac[i] - '0')
+		}
+	}
+	if len(frac) > 2 && frac[2] >= '5' {
+		n++
+	}
+	if neg {
+		n = -n
+	}
+	return Amount(n), nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Float returns the amount in currency units, for ratios and display maths.
+func (a Amount) Float() float64 { return float64(a) / Scale }
+
+// String formats the amount with exactly two decimals, e.g. "-12.05".
+func (a Amount) String() string {
+	sign, n := "", int64(a)
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, n/Scale, n%Scale)
+}
+
+// Abs returns the magnitude of a.
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Mul scales a by factor, rounding to the nearest cent.
+func (a Amount) Mul(factor float64) Amount {
+	return Amount(math.Round(float64(a) * factor))
+}
+
+// Round rounds a to whole currency units, halves away from zero.
+func (a Amount) Round() Amount {
+	return Amount(math.Round(float64(a)/Scale)) * Scale
+}
+
+// Split divides a into n parts that differ by at most one cent and sum to
+// exactly a; earlier parts take the leftover cents.
+func (a Amount) Split(n int) []Amount {
+	if n <= 0 {
+		return nil
+	}
+	parts := make([]Amount, n)
+	share, rest := int64(a)/int64(n), int64(a)%int64(n)
+	for i := range parts {
+		parts[i] = Amount(share)
+		switch {
+		case rest > 0 && int64(i) < rest:
+			parts[i]++
+		case rest < 0 && int64(i) < -rest:
+			parts[i]--
+		}
+	}
+	return parts
+}
+
+// Sum adds amounts exactly.
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 1 && b[0] == '"' {
+		s, err := strconv.Unquote(string(b))
+		if err != nil {
+			return fmt.Errorf("%w %s", errSyntax, b)
+		}
+		b = []byte(s)
+	}
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value stores the amount as an exact decimal string for NUMERIC columns.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case float64:
+		*a = FromFloat(v)
+	case int64:
+		*a = Amount(v * Scale)
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Money is an Amount in a given ISO 4217 currency.
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New pairs amount with currency.
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add sums two amounts of the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if !strings.EqualFold(m.Currency, o.Currency) {
+		return m, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,122 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Amount
+	}{
+		{"12", 1200},
+		{"12.5", 1250},
+		{"-0.05", -5},
+		{"+3.10", 310},
+		{".5", 50},
+		{"1.005", 101},
+		{"-1.004", -100},
+		{"1e2", 10000},
+	}
+	for _, tc := range cases {
+		if got, err := Parse(tc.in); err != nil || got != tc.want {
+			t.Fatalf("Parse(%q) = %d, %v; want %d", tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "-", "1.2.3", "12a", "1,5", "99999999999999999999"} {
+		if _, err := Parse(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for a, want := range map[Amount]string{0: "0.00", 5: "0.05", -5: "-0.05", 123456: "1234.56", -1200: "-12.00"} {
+		if got := a.String(); got != want {
+			t.Fatalf("Amount(%d).String() = %q, want %q", a, got, want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	for a, want := range map[Amount]Amount{1249: 1200, 1250: 1300, -1250: -1300, 99: 100, 0: 0} {
+		if got := a.Round(); got != want {
+			t.Fatalf("Amount(%d).Round() = %d, want %d", a, got, want)
+		}
+	}
+}
+
+func TestSplitIsExact(t *testing.T) {
+	parts := FromFloat(100).Split(3)
+	if len(parts) != 3 || parts[0] != 3334 || parts[1] != 3333 || parts[2] != 3333 || Sum(parts...) != 10000 {
+		t.Fatalf("unexpected split %v", parts)
+	}
+	neg := Cents(-10).Split(4)
+	if Sum(neg...) != -10 || neg[0] != -3 || neg[3] != -2 {
+		t.Fatalf("unexpected negative split %v", neg)
+	}
+	if Cents(5).Split(0) != nil {
+		t.Fatal("expected nil for zero parts")
+	}
+}
+
+func TestSumAvoidsFloatDrift(t *testing.T) {
+	total := Sum(FromFloat(0.1), FromFloat(0.2))
+	if total != FromFloat(0.3) || total.String() != "0.30" {
+		t.Fatalf("expected exactly 0.30, got %s", total)
+	}
+}
+
+func TestJSONIsBackwardCompatible(t *testing.T) {
+	var v struct {
+		A Amount  `json:"a"`
+		B Amount  `json:"b"`
+		C *Amount `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a": 12.5, "b": "7.25", "c": null}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.A != 1250 || v.B != 725 || v.C != nil {
+		t.Fatalf("unexpected decode %+v", v)
+	}
+	out, _ := json.Marshal(v)
+	if string(out) != `{"a":12.50,"b":7.25,"c":null}` {
+		t.Fatalf("unexpected encoding %s", out)
+	}
+	var f struct {
+		A float64 `json:"a"`
+	}
+	if err := json.Unmarshal(out, &f); err != nil || f.A != 12.5 {
+		t.Fatalf("float clients should still read amounts, got %v %v", f.A, err)
+	}
+	if err := json.Unmarshal([]byte(`{"a": "abc"}`), &v); err == nil {
+		t.Fatal("expected error for non-numeric string")
+	}
+}
+
+func TestScan(t *testing.T) {
+	var a Amount
+	for _, src := range []any{[]byte("12.34"), "12.34", 12.34} {
+		if err := a.Scan(src); err != nil || a != 1234 {
+			t.Fatalf("Scan(%v) = %d, %v", src, a, err)
+		}
+	}
+	if err := a.Scan(int64(3)); err != nil || a != 300 {
+		t.Fatalf("Scan(int64) = %d, %v", a, err)
+	}
+	if v, _ := a.Value(); v != "3.00" {
+		t.Fatalf("Value() = %v", v)
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	sum, err := New(150, "EUR").Add(New(25, "eur"))
+	if err != nil || sum.Amount != 175 || sum.String() != "1.75 EUR" {
+		t.Fatalf("unexpected sum %v, %v", sum, err)
+	}
+	if _, err := New(1, "EUR").Add(New(1, "PLN")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package planner
+
+import (
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+// lateArrivalHour is the local hour from which a return counts as late when
+// an exam or deadline follows the next day.
+const lateArrivalHour = 21
+
+// JourneyConflicts checks the real departure and arrival times of the chosen
+// journeys, which the window's dates alone cannot show. It reports events the
+// journeys pull into the trip (an outbound leaving before the window starts,
+// a return arriving after it ends) and exams or deadlines the day after a
+// late return. Events inside the window are left to the window check.
+// Dates are taken in each timestamp's own zone, the local time at that end.
+func JourneyConflicts(events []domain.AcademicEvent, window domain.TravelWindow, outbound, ret *domain.TransportOption) []domain.ConflictAlert {
+	const layout = "2006-01-02"
+	alerts := []domain.ConflictAlert{}
+	spanStart, spanEnd := window.StartDate, window.EndDate
+	if outbound != nil {
+		if t, ok := outbound.DepartureTime(); ok && t.Format(layout) < spanStart {
+			spanStart = t.Format(layout)
+		}
+	}
+	var arrival time.Time
+	hasArrival := false
+	if ret != nil {
+		arrival, hasArrival = ret.ArrivalTime()
+		if hasArrival && arrival.Format(layout) > spanEnd {
+			spanEnd = arrival.Format(layout)
+		}
+	}
+
+	for _, event := range events {
+		if event.Start < window.StartDate && event.Start >= spanStart {
+			alerts = append(alerts, domain.ConflictAlert{
+				Severity:       eventSeverity(event.Type),
+				Reason:         string(event.Type) + " before the window, after the outbound departs: " + event.Title,
+				RelatedEventID: event.ID,
+			})
+			continue
+		}
+		if event.Start > window.EndDate && event.Start <= spanEnd {
+			alerts = append(alerts, domain.ConflictAlert{
+				Severity:       eventSeverity(event.Type),
+				Reason:         string(event.Type) + " after the window, before the return arrives: " + event.Title,
+				RelatedEventID: event.ID,
+			})
+			continue
+		}
+		if !hasArrival || arrival.Hour() < lateArrivalHour {
+			continue
+		}
+		if event.Type != domain.AcademicExam && event.Type != domain.AcademicDeadline {
+			continue
+		}
+		if event.Start == arrival.AddDate(0, 0, 1).Format(layout) {
+			alerts = append(alerts, domain.ConflictAlert{
+				Severity:       domain.SeverityWarning,
+				Reason:         "late return at " + arrival.Format("15:04") + " before " + string(event.Type) + ": " + event.Title,
+				RelatedEventID: event.ID,
+			})
+		}
+	}
+	return alerts
+}
+
+func eventSeverity(kind domain.AcademicEventType) domain.Severity {
+	switch kind {
+	case domain.AcademicExam:
+		return domain.SeverityHighRisk
+	case domain.AcademicDeadline:
+		return domain.SeverityWarning
+	default:
+		return domain.SeverityInfo
+	}
+}
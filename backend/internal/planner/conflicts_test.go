@@ -0,0 +1,40 @@
+package planner
+
+import (
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func TestJourneyConflicts_UsesRealJourneyTimes(t *testing.T) {
+	events := []domain.AcademicEvent{
+		{ID: "exam", Type: domain.AcademicExam, Title: "Midterm", Start: "2026-03-19"},
+		{ID: "deadline", Type: domain.AcademicDeadline, Title: "Essay", Start: "2026-03-24"},
+		{ID: "class", Type: domain.AcademicClass, Title: "Seminar", Start: "2026-03-24"},
+		{ID: "inside", Type: domain.AcademicExam, Title: "Inside", Start: "2026-03-21"},
+	}
+	window := domain.TravelWindow{ID: "w-2", StartDate: "2026-03-20", EndDate: "2026-03-22"}
+	outbound := &domain.TransportOption{Departure: "2026-03-19T18:10:00+01:00"}
+	ret := &domain.TransportOption{Legs: []domain.TransportLeg{
+		{Kind: domain.LegRide, To: domain.LegStop{Time: "2026-03-23T21:40:00+01:00"}},
+	}}
+
+	alerts := JourneyConflicts(events, window, outbound, ret)
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %+v", alerts)
+	}
+	if alerts[0].RelatedEventID != "exam" || alerts[0].Severity != domain.SeverityHighRisk {
+		t.Fatalf("expected the early departure to clash with the exam, got %+v", alerts[0])
+	}
+	if alerts[1].RelatedEventID != "deadline" || alerts[1].Severity != domain.SeverityWarning {
+		t.Fatalf("expected a late-return warning before the deadline, got %+v", alerts[1])
+	}
+}
+
+func TestJourneyConflicts_NoTimesNoAlerts(t *testing.T) {
+	events := []domain.AcademicEvent{{ID: "exam", Type: domain.AcademicExam, Start: "2026-03-19"}}
+	window := domain.TravelWindow{StartDate: "2026-03-20", EndDate: "2026-03-22"}
+	if alerts := JourneyConflicts(events, window, &domain.TransportOption{}, nil); len(alerts) != 0 {
+		t.Fatalf("expected no alerts without journey times, got %+v", alerts)
+	}
+}
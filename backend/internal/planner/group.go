@@ -0,0 +1,216 @@
+// Package planner combines store queries into multi-member trip planning.
+package planner
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+const defaultMaxTravelHours = 6
+
+// GroupRequest tunes a group optimization run for a trip.
+type GroupRequest struct {
+	// WindowIDs restricts candidate windows; empty means every known window.
+	WindowIDs []string
+	// Defaults apply to members whose profile leaves a field empty.
+	Defaults domain.TripConstraint
+}
+
+type memberContext struct {
+	userID   string
+	city     string
+	style    string
+	maxHours float64
+	headroom money.Amount
+	passes   []fares.Pass
+	options  map[string]domain.TripOption
+}
+
+// OptimizeGroup ranks destination/window pairs for every member of trip,
+// using each member's profile, budget headroom and calendar conflicts. Each
+// member's transport is searched on transport (nil for seeded costs only)
+// from their own departure city, and no member's home city is offered as a
+// destination.
+func OptimizeGroup(ctx context.Context, ds domain.DataStore, transport provider.TransportProvider, trip domain.Trip, req GroupRequest) []domain.GroupTripOption {
+	rates := fx.NewTable(ds.ListFXRates())
+	members := make([]memberContext, 0, len(trip.Members))
+	destinations := []string{}
+	for _, userID := range trip.Members {
+		mc := loadMember(ds, rates, userID, req.Defaults)
+		options := slices.DeleteFunc(ds.OptimizeTrips(domain.TripConstraint{
+			BudgetCap:      mc.headroom,
+			MaxTravelHours: mc.maxHours,
+			PartySize:      1,
+			Style:          mc.style,
+			DepartureCity:  mc.city,
+		}), func(opt domain.TripOption) bool { return isHome(mc.city, opt.Destination) })
+		options = EnrichTransport(ctx, transport, options, provider.TransportQuery{
+			From: mc.city, Passengers: 1, CabinBags: 1, Passes: mc.passes,
+		}, mc.headroom)
+		for _, opt := range options {
+			mc.options[opt.Destination] = opt
+			if !slices.Contains(destinations, opt.Destination) {
+				destinations = append(destinations, opt.Destination)
+			}
+		}
+		members = append(members, mc)
+	}
+	destinations = slices.DeleteFunc(destinations, func(dest string) bool {
+		return slices.ContainsFunc(members, func(mc memberContext) bool { return isHome(mc.city, dest) })
+	})
+
+	windows := ds.ListTravelWindows("", "")
+	if len(req.WindowIDs) > 0 {
+		windows = slices.DeleteFunc(windows, func(w domain.TravelWindow) bool {
+			return !slices.Contains(req.WindowIDs, w.ID)
+		})
+	}
+
+	out := make([]domain.GroupTripOption, 0, len(windows)*len(destinations))
+	for _, window := range windows {
+		conflicts := make(map[string][]domain.ConflictAlert, len(members))
+		for _, mc := range members {
+			conflicts[mc.userID] = ds.EvaluateMemberConflicts(mc.userID, window.ID)
+		}
+		for _, dest := range destinations {
+			option := domain.GroupTripOption{
+				Destination:     dest,
+				WindowID:        window.ID,
+				StartDate:       window.StartDate,
+				EndDate:         window.EndDate,
+				Currency:        fx.Base,
+				StrainedMembers: []string{},
+				Members:         make([]domain.MemberFit, 0, len(members)),
+			}
+			minScore, sum := math.Inf(1), 0.0
+			for _, mc := range members {
+				fit := evaluateMember(mc, dest, conflicts[mc.userID])
+				option.TotalEstimatedCost += fit.EstimatedCost
+				if len(fit.Strains) > 0 {
+					option.StrainedMembers = append(option.StrainedMembers, mc.userID)
+				}
+				minScore = math.Min(minScore, fit.Score)
+				sum += fit.Score
+				option.Members = append(option.Members, fit)
+			}
+			if len(members) > 0 {
+				// Weight the worst-off member as heavily as the average so one
+				// member's exam or empty wallet sinks an otherwise popular pick.
+				option.Score = math.Round((minScore+sum/float64(len(members)))/2*10) / 10
+			}
+			option.WorksForEveryone = len(option.StrainedMembers) == 0
+			out = append(out, option)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].WorksForEveryone != out[j].WorksForEveryone {
+			return out[i].WorksForEveryone
+		}
+		return out[i].Score > out[j].Score
+	})
+	return out
+}
+
+func loadMember(ds domain.DataStore, rates *fx.Table, userID string, defaults domain.TripConstraint) memberContext {
+	profile := ds.GetProfile(userID)
+	mc := memberContext{
+		userID:   userID,
+		city:     profile.HomeCity,
+		style:    profile.Style,
+		maxHours: profile.MaxTravelHours,
+		passes:   fares.Passes(profile.Passes),
+		options:  map[string]domain.TripOption{},
+	}
+	// Optimizer costs are in EUR; bring the member's headroom onto the same scale.
+	forecast := ds.Forecast(userID, "", "")
+	headroom := forecast.RemainingBudget
+	if eur, err := rates.ConvertAmount(forecast.RemainingBudget, forecast.Currency, fx.Base, ""); err == nil {
+		headroom = eur
+	}
+	mc.headroom = headroom
+	if mc.city == "" {
+		mc.city = defaults.DepartureCity
+	}
+	if mc.style == "" {
+		mc.style = defaults.Style
+	}
+	if mc.maxHours <= 0 {
+		mc.maxHours = defaults.MaxTravelHours
+	}
+	if mc.maxHours <= 0 {
+		mc.maxHours = defaultMaxTravelHours
+	}
+	return mc
+}
+
+func evaluateMember(mc memberContext, dest string, conflicts []domain.ConflictAlert) domain.MemberFit {
+	fit := domain.MemberFit{
+		UserID:         mc.userID,
+		DepartureCity:  mc.city,
+		BudgetHeadroom: mc.headroom,
+		Conflicts:      conflicts,
+		Strains:        []string{},
+		Score:          100,
+	}
+	if opt, ok := mc.options[dest]; ok {
+		fit.EstimatedCost = opt.TotalEstimatedCost
+		if opt.TotalEstimatedCost > mc.headroom {
+			fit.Strains = append(fit.Strains, domain.StrainOverBudget)
+			fit.Score -= (opt.TotalEstimatedCost - max(mc.headroom, 0)).Float() / 3
+		}
+		if hours := fastestTransport(opt.TransportOptions); hours > mc.maxHours {
+			fit.Strains = append(fit.Strains, domain.StrainLongTransit)
+			fit.Score -= (hours - mc.maxHours) * 18
+		}
+		if mc.style != "" && !slices.Contains(opt.ReasonTags, "style-match") {
+			fit.Strains = append(fit.Strains, domain.StrainStyleMismatch)
+			fit.Score -= 10
+		}
+	}
+
+	exam, deadline := false, false
+	for _, alert := range conflicts {
+		switch alert.Severity {
+		case domain.SeverityHighRisk:
+			exam = true
+			fit.Score -= 40
+		case domain.SeverityWarning:
+			deadline = true
+			fit.Score -= 15
+		}
+	}
+	if exam {
+		fit.Strains = append(fit.Strains, domain.StrainExamConflict)
+	}
+	if deadline {
+		fit.Strains = append(fit.Strains, domain.StrainDeadline)
+	}
+	fit.Score = math.Round(fit.Score*10) / 10
+	return fit
+}
+
+// isHome reports whether dest is the city a member departs from.
+func isHome(city, dest string) bool {
+	return city != "" && strings.EqualFold(strings.TrimSpace(city), strings.TrimSpace(dest))
+}
+
+func fastestTransport(options []domain.TransportOption) float64 {
+	if len(options) == 0 {
+		return 0
+	}
+	best := options[0].DurationHours
+	for _, opt := range options[1:] {
+		best = math.Min(best, opt.DurationHours)
+	}
+	return best
+}
@@ -0,0 +1,136 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
+	"exchange-travel-planner/backend/internal/store"
+)
+
+func groupStore() (*store.Store, domain.Trip) {
+	s := store.New()
+	s.SaveProfile(domain.UserProfile{UserID: "alice", HomeCity: "Vienna", Style: "city", MaxTravelHours: 5})
+	s.ImportAcademicEvents([]domain.AcademicEvent{
+		{UserID: "alice", Type: domain.AcademicExam, Title: "Alice Exam", Start: "2026-03-07", End: "2026-03-07", Priority: 5},
+	})
+	trip := s.ShareTrip("trip-1", []string{"alice"})
+	return s, *trip
+}
+
+func TestOptimizeGroup_RanksOptionsThatWorkForEveryoneFirst(t *testing.T) {
+	s, trip := groupStore()
+	opts := OptimizeGroup(context.Background(), s, nil, trip, GroupRequest{})
+	if len(opts) != 12 { // 3 windows x 4 destinations
+		t.Fatalf("expected 12 options, got %d", len(opts))
+	}
+	if !opts[0].WorksForEveryone {
+		t.Fatalf("expected top option to work for everyone, got %+v", opts[0])
+	}
+	if len(opts[0].Members) != 2 {
+		t.Fatalf("expected 2 member fits, got %d", len(opts[0].Members))
+	}
+	for i := 1; i < len(opts); i++ {
+		if opts[i].WorksForEveryone && !opts[i-1].WorksForEveryone {
+			t.Fatal("options that work for everyone must rank first")
+		}
+	}
+}
+
+func TestOptimizeGroup_ReportsStrainedMember(t *testing.T) {
+	s, trip := groupStore()
+	opts := OptimizeGroup(context.Background(), s, nil, trip, GroupRequest{WindowIDs: []string{"w-1"}})
+	if len(opts) != 4 {
+		t.Fatalf("expected 4 options for one window, got %d", len(opts))
+	}
+	for _, opt := range opts {
+		if opt.WindowID != "w-1" {
+			t.Fatalf("unexpected window %s", opt.WindowID)
+		}
+		if !slices.Contains(opt.StrainedMembers, "alice") {
+			t.Fatalf("expected alice strained by her exam in %s", opt.Destination)
+		}
+		for _, fit := range opt.Members {
+			if fit.UserID == "demo-user" && slices.Contains(fit.Strains, domain.StrainExamConflict) {
+				t.Fatal("alice's exam must not strain demo-user")
+			}
+			if fit.UserID == "alice" && !slices.Contains(fit.Strains, domain.StrainExamConflict) {
+				t.Fatalf("expected exam-conflict strain for alice, got %v", fit.Strains)
+			}
+		}
+	}
+}
+
+func TestOptimizeGroup_StyleMismatch(t *testing.T) {
+	s, trip := groupStore()
+	opts := OptimizeGroup(context.Background(), s, nil, trip, GroupRequest{WindowIDs: []string{"w-3"}})
+	for _, opt := range opts {
+		if opt.Destination != "Budapest" {
+			continue
+		}
+		for _, fit := range opt.Members {
+			if fit.UserID == "demo-user" && !slices.Contains(fit.Strains, domain.StrainStyleMismatch) {
+				t.Fatalf("expected culture-loving demo-user to be strained by Budapest, got %v", fit.Strains)
+			}
+		}
+	}
+}
+
+// stubTransport answers searches from the cities it knows.
+type stubTransport map[string]domain.TransportOption
+
+func (s stubTransport) SearchTransport(_ context.Context, q provider.TransportQuery) ([]domain.TransportOption, error) {
+	if opt, ok := s[q.From]; ok {
+		return []domain.TransportOption{opt}, nil
+	}
+	return nil, errors.New("no transport")
+}
+
+func TestOptimizeGroup_PricesEachMemberFromTheirCity(t *testing.T) {
+	s, trip := groupStore()
+	transport := stubTransport{
+		"Vienna": {Provider: "RailJet", Mode: "train", DurationHours: 4, Price: money.FromFloat(30)},
+		"Berlin": {Provider: "NightBus", Mode: "bus", DurationHours: 9, Price: money.FromFloat(90)},
+	}
+	opts := OptimizeGroup(context.Background(), s, transport, trip, GroupRequest{WindowIDs: []string{"w-3"}})
+	for _, opt := range opts {
+		if opt.Destination != "Prague" {
+			continue
+		}
+		fits := map[string]domain.MemberFit{}
+		for _, fit := range opt.Members {
+			fits[fit.UserID] = fit
+		}
+		alice, demo := fits["alice"], fits["demo-user"]
+		if alice.DepartureCity != "Vienna" || demo.DepartureCity != "Berlin" {
+			t.Fatalf("unexpected departure cities %+v", opt.Members)
+		}
+		// The seeded costs are the same for both; their transport is not.
+		if demo.EstimatedCost-alice.EstimatedCost != money.FromFloat(60) {
+			t.Fatalf("expected demo-user to pay 60 more than alice, got %s and %s", demo.EstimatedCost, alice.EstimatedCost)
+		}
+		if !slices.Contains(demo.Strains, domain.StrainLongTransit) || slices.Contains(alice.Strains, domain.StrainLongTransit) {
+			t.Fatalf("expected only demo-user's 9h bus to strain, got %v and %v", demo.Strains, alice.Strains)
+		}
+		return
+	}
+	t.Fatalf("no Prague option in %+v", opts)
+}
+
+func TestOptimizeGroup_SkipsMembersHomeCities(t *testing.T) {
+	s, trip := groupStore()
+	s.SaveProfile(domain.UserProfile{UserID: "alice", HomeCity: "prague", Style: "city", MaxTravelHours: 5})
+	opts := OptimizeGroup(context.Background(), s, nil, trip, GroupRequest{WindowIDs: []string{"w-3"}})
+	if len(opts) != 3 {
+		t.Fatalf("expected 3 destinations besides alice's home, got %d", len(opts))
+	}
+	for _, opt := range opts {
+		if opt.Destination == "Prague" {
+			t.Fatalf("alice's home city offered as a destination: %+v", opt)
+		}
+	}
+}
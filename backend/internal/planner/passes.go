@@ -0,0 +1,58 @@
+package planner
+
+import (
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// ApplyPasses re-prices the seeded transport of each EUR-priced option for
+// party travelling on date (YYYY-MM-DD, empty when unknown), with its youth
+// and student discounts and the searching traveller's passes. Each option's
+// total moves by what its cheapest transport saves, and budgetCap (EUR, zero
+// for none) re-checks it.
+func ApplyPasses(model *fares.Model, options []domain.TripOption, party fares.Party, date string, budgetCap money.Amount) []domain.TripOption {
+	if len(party.Passes) == 0 && party.Youth+party.Students == 0 {
+		// Seeded options are already priced for a party of adults.
+		return options
+	}
+	for i := range options {
+		opt := &options[i]
+		if len(opt.TransportOptions) == 0 {
+			continue
+		}
+		before := cheapest(opt.TransportOptions)
+		for j := range opt.TransportOptions {
+			t := &opt.TransportOptions[j]
+			if !t.Synthetic {
+				// Providers priced their options for the passes already.
+				continue
+			}
+			// Seeded options price the whole party as adults.
+			adult := t.Price.Mul(1 / float64(party.Size()))
+			fare := model.PriceFrom(fares.Journey{Mode: t.Mode, Hours: t.DurationHours, Date: date, Legs: t.Legs},
+				fares.Fare{Amount: adult, Kind: t.PriceKind}, party)
+			if fare.Amount < t.Price {
+				t.Price, t.Passes = fare.Amount, fare.Passes
+				fare.Annotate(t.Legs)
+			}
+		}
+		after := cheapest(opt.TransportOptions)
+		if after < before {
+			opt.TotalEstimatedCost -= before - after
+			if budgetCap > 0 {
+				recheckBudget(opt, budgetCap)
+			}
+		}
+	}
+	return options
+}
+
+// cheapest is the lowest cost, baggage included, among options.
+func cheapest(options []domain.TransportOption) money.Amount {
+	best := options[0].TotalCost()
+	for _, o := range options[1:] {
+		best = min(best, o.TotalCost())
+	}
+	return best
+}
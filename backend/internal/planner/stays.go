@@ -0,0 +1,65 @@
+package planner
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+const withinBudget = "within-budget"
+
+// EnrichStays replaces the seeded stays of each EUR-priced option with what p
+// offers for its destination and dates, and re-prices the option with the
+// cheapest of them. budgetCap (EUR, zero for none) re-checks the option's
+// within-budget tag and risk against the new total. Destinations p cannot
+// serve keep their seeded stays.
+func EnrichStays(ctx context.Context, p provider.AccommodationProvider, options []domain.TripOption, q provider.StayQuery, budgetCap money.Amount) []domain.TripOption {
+	if p == nil {
+		return options
+	}
+	var wg sync.WaitGroup
+	for i := range options {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opt := &options[i]
+			query := q
+			query.City = opt.Destination
+			stays, err := p.SearchStays(ctx, query)
+			if err != nil || len(stays) == 0 {
+				return
+			}
+			// Stays come back cheapest first.
+			cost := stays[0].TotalPrice
+			opt.TotalEstimatedCost += cost - opt.StayCost
+			opt.StayCost = cost
+			opt.StayOptions = stays
+			if budgetCap > 0 {
+				recheckBudget(opt, budgetCap)
+			}
+		}()
+	}
+	wg.Wait()
+	return options
+}
+
+func recheckBudget(opt *domain.TripOption, budgetCap money.Amount) {
+	opt.ReasonTags = slices.DeleteFunc(opt.ReasonTags, func(tag string) bool {
+		return tag == withinBudget || tag == "stretch-choice"
+	})
+	if opt.TotalEstimatedCost <= budgetCap {
+		opt.ReasonTags = append(opt.ReasonTags, withinBudget)
+		if opt.RiskLevel == domain.SeverityWarning {
+			opt.RiskLevel = domain.SeverityInfo
+		}
+	} else if opt.RiskLevel == domain.SeverityInfo {
+		opt.RiskLevel = domain.SeverityWarning
+	}
+	if len(opt.ReasonTags) == 0 {
+		opt.ReasonTags = []string{"stretch-choice"}
+	}
+}
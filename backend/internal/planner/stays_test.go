@@ -0,0 +1,90 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+type stubStays map[string][]domain.StayOption
+
+func (s stubStays) SearchStays(_ context.Context, q provider.StayQuery) ([]domain.StayOption, error) {
+	if stays, ok := s[q.City]; ok {
+		return stays, nil
+	}
+	return nil, errors.New("no stays")
+}
+
+func TestEnrichStays_RepricesFromProvider(t *testing.T) {
+	options := []domain.TripOption{
+		{Destination: "Prague", TotalEstimatedCost: money.FromFloat(200), StayCost: money.FromFloat(112),
+			ReasonTags: []string{"short-transit", "within-budget"}, RiskLevel: domain.SeverityInfo},
+		{Destination: "Krakow", TotalEstimatedCost: money.FromFloat(150), StayCost: money.FromFloat(88),
+			ReasonTags: []string{"short-transit"}, RiskLevel: domain.SeverityWarning},
+	}
+	stays := stubStays{"Prague": {{Provider: "LocalStays", TotalPrice: money.FromFloat(180)}}}
+	out := EnrichStays(context.Background(), stays, options, provider.StayQuery{Guests: 2}, money.FromFloat(230))
+
+	prague := out[0]
+	if prague.TotalEstimatedCost != money.FromFloat(268) || prague.StayCost != money.FromFloat(180) || prague.StayOptions[0].Provider != "LocalStays" {
+		t.Fatalf("unexpected Prague option %+v", prague)
+	}
+	if prague.RiskLevel != domain.SeverityWarning || len(prague.ReasonTags) != 1 {
+		t.Fatalf("expected Prague to drop out of budget, got %+v", prague)
+	}
+	// Krakow has no provider stays and keeps its seeded price.
+	if out[1].TotalEstimatedCost != money.FromFloat(150) || out[1].RiskLevel != domain.SeverityWarning {
+		t.Fatalf("expected Krakow unchanged, got %+v", out[1])
+	}
+	if got := EnrichStays(context.Background(), nil, options, provider.StayQuery{}, 0); len(got) != 2 {
+		t.Fatal("expected options back without a provider")
+	}
+}
+
+func TestApplyPasses_RepricesTheHoldersSeat(t *testing.T) {
+	options := []domain.TripOption{{
+		Destination: "Prague", TotalEstimatedCost: money.FromFloat(300), RiskLevel: domain.SeverityWarning,
+		ReasonTags: []string{"stretch-choice"},
+		TransportOptions: []domain.TransportOption{
+			{Provider: "EuroRail Connect", Mode: "train", DurationHours: 4, Price: money.FromFloat(100), Synthetic: true},
+			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: 5, Price: money.FromFloat(80), Synthetic: true},
+		},
+	}}
+	interrail, _ := fares.PassFor(domain.TravelPass{Type: "interrail"})
+	out := ApplyPasses(fares.NewModel(fares.DefaultTable()), options, fares.Party{Adults: 2, Passes: []fares.Pass{interrail}}, "2026-03-06", money.FromFloat(260))
+
+	// The holder's half of the train is covered, so it undercuts the bus.
+	train := out[0].TransportOptions[0]
+	if train.Price != money.FromFloat(50) || len(train.Passes) != 1 || out[0].TransportOptions[1].Price != money.FromFloat(80) {
+		t.Fatalf("unexpected transport %+v", out[0].TransportOptions)
+	}
+	if out[0].TotalEstimatedCost != money.FromFloat(270) || out[0].RiskLevel != domain.SeverityWarning {
+		t.Fatalf("expected the total to drop by 30, got %+v", out[0])
+	}
+	if out := ApplyPasses(fares.NewModel(fares.DefaultTable()), options, fares.Party{Adults: 2}, "", 0); out[0].TotalEstimatedCost != money.FromFloat(270) {
+		t.Fatal("expected no change for adults without passes")
+	}
+}
+
+func TestApplyPasses_DiscountsYouthWithoutPasses(t *testing.T) {
+	options := []domain.TripOption{{
+		Destination: "Prague", TotalEstimatedCost: money.FromFloat(300),
+		TransportOptions: []domain.TransportOption{
+			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: 5, Price: money.FromFloat(80), Synthetic: true},
+		},
+	}}
+	out := ApplyPasses(fares.NewModel(fares.DefaultTable()), options, fares.Party{Adults: 1, Youth: 1}, "", 0)
+
+	// The adult pays 40 and the youth 20% less.
+	if bus := out[0].TransportOptions[0]; bus.Price != money.FromFloat(72) || len(bus.Passes) != 0 {
+		t.Fatalf("unexpected transport %+v", out[0].TransportOptions)
+	}
+	if out[0].TotalEstimatedCost != money.FromFloat(292) {
+		t.Fatalf("expected the total to drop by 8, got %+v", out[0])
+	}
+}
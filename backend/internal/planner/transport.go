@@ -0,0 +1,44 @@
+package planner
+
+import (
+	"context"
+	"sync"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+	"exchange-travel-planner/backend/internal/provider"
+)
+
+// EnrichTransport replaces the seeded transport of each EUR-priced option
+// with what p finds from q.From to its destination. The total moves by the
+// difference between the cheapest options, baggage included, and budgetCap
+// (EUR, zero for none) re-checks it. Destinations p cannot reach, and all
+// of them when q.From is empty, keep their seeded transport.
+func EnrichTransport(ctx context.Context, p provider.TransportProvider, options []domain.TripOption, q provider.TransportQuery, budgetCap money.Amount) []domain.TripOption {
+	if p == nil || q.From == "" {
+		return options
+	}
+	var wg sync.WaitGroup
+	for i := range options {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opt := &options[i]
+			query := q
+			query.To = opt.Destination
+			found, err := p.SearchTransport(ctx, query)
+			if err != nil || len(found) == 0 {
+				return
+			}
+			if len(opt.TransportOptions) > 0 {
+				opt.TotalEstimatedCost += cheapest(found) - cheapest(opt.TransportOptions)
+			}
+			opt.TransportOptions = found
+			if budgetCap > 0 {
+				recheckBudget(opt, budgetCap)
+			}
+		}()
+	}
+	wg.Wait()
+	return options
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+const (
+	defaultCacheTTL   = 5 * time.Minute
+	defaultCacheStale = 30 * time.Minute
+	// refreshTimeout bounds a shared provider call, which outlives the
+	// request that started it so its answer can still be cached.
+	refreshTimeout   = 15 * time.Second
+	maxMemoryEntries = 1000
+)
+
+// CachedSearch is a provider's answer to one query and when it was fetched.
+type CachedSearch struct {
+	Options  []domain.TransportOption
+	StoredAt time.Time
+}
+
+// SearchCache stores provider answers by CacheKey. Entries may be dropped
+// once keepUntil has passed.
+type SearchCache interface {
+	Get(ctx context.Context, key string) (CachedSearch, bool, error)
+	Put(ctx context.Context, key string, entry CachedSearch, keepUntil time.Time) error
+}
+
+// CacheKey normalises q so that searches differing only in spelling case,
+// spacing or an implied single passenger share an entry. The party's
+// discount categories, passes and bags are part of the key, since they
+// change the price.
+func CacheKey(provider string, q TransportQuery) string {
+	direction := "dep"
+	if q.ArriveBy {
+		direction = "arr"
+	}
+	parts := []string{
+		strings.ToLower(provider),
+		strings.ToLower(strings.Join(strings.Fields(q.From), " ")),
+		strings.ToLower(strings.Join(strings.Fields(q.To), " ")),
+		q.Date, q.Time, direction,
+		strconv.Itoa(q.passengers()), strconv.Itoa(q.Youth), strconv.Itoa(q.Students),
+	}
+	if q.CabinBags > 0 || q.CheckedBags > 0 {
+		parts = append(parts, "bags="+strconv.Itoa(q.CabinBags)+"/"+strconv.Itoa(q.CheckedBags))
+	}
+	if len(q.Passes) > 0 {
+		// Options are priced for the passes, so travellers holding
+		// different ones cannot share answers.
+		parts = append(parts, fmt.Sprint(q.Passes))
+	}
+	return strings.Join(parts, "|")
+}
+
+// CacheConfig sets how long answers are served. Within TTL an answer is
+// fresh; for Stale after that it is still served while a refresh runs in
+// the background. ProviderTTL overrides TTL by provider name, and a zero
+// TTL turns caching off for that provider.
+type CacheConfig struct {
+	TTL         time.Duration
+	Stale       time.Duration
+	ProviderTTL map[string]time.Duration
+}
+
+// CacheConfigFromEnv reads TRANSPORT_CACHE_TTL and TRANSPORT_CACHE_STALE
+// (Go durations, "5m" and "30m" by default) and per-provider overrides such
+// as TRANSPORT_CACHE_TTL_OPENDATA.
+func CacheConfigFromEnv() (CacheConfig, error) {
+	c := CacheConfig{TTL: defaultCacheTTL, Stale: defaultCacheStale, ProviderTTL: map[string]time.Duration{}}
+	var err error
+	if c.TTL, err = envDuration("TRANSPORT_CACHE_TTL", c.TTL); err != nil {
+		return c, err
+	}
+	if c.Stale, err = envDuration("TRANSPORT_CACHE_STALE", c.Stale); err != nil {
+		return c, err
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		provider, ok := strings.CutPrefix(name, "TRANSPORT_CACHE_TTL_")
+		if !ok || provider == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return c, fmt.Errorf("invalid %s %q", name, value)
+		}
+		c.ProviderTTL[strings.ToLower(provider)] = d
+	}
+	return c, nil
+}
+
+func envDuration(name string, fallback time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return fallback, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	return d, nil
+}
+
+func (c CacheConfig) ttl(provider string) time.Duration {
+	if d, ok := c.ProviderTTL[strings.ToLower(provider)]; ok {
+		return d
+	}
+	return c.TTL
+}
+
+// CachedProvider answers from cache when it can and otherwise calls the
+// provider it wraps. Concurrent identical searches share one call.
+type CachedProvider struct {
+	name  string
+	next  TransportProvider
+	cache SearchCache
+	ttl   time.Duration
+	stale time.Duration
+	now   func() time.Time
+	group singleflight.Group
+}
+
+func NewCachedProvider(name string, next TransportProvider, cache SearchCache, ttl, stale time.Duration) *CachedProvider {
+	return &CachedProvider{name: name, next: next, cache: cache, ttl: ttl, stale: stale, now: time.Now}
+}
+
+func (p *CachedProvider) SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error) {
+	options, _, err := p.search(ctx, q)
+	return options, err
+}
+
+// search also reports whether the options came from the cache. A cache
+// that fails is treated as empty; the provider is still asked.
+func (p *CachedProvider) search(ctx context.Context, q TransportQuery) ([]domain.TransportOption, bool, error) {
+	key := CacheKey(p.name, q)
+	entry, ok, err := p.cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("search cache get %s: %v", p.name, err)
+	}
+	if ok {
+		age := p.now().Sub(entry.StoredAt)
+		if age < p.ttl {
+			return entry.Options, true, nil
+		}
+		if age < p.ttl+p.stale {
+			// Nobody waits on the refresh; errors only cost a fresh answer.
+			p.group.DoChan(key, func() (any, error) { return p.fetch(ctx, key, q) })
+			return entry.Options, true, nil
+		}
+	}
+
+	results := p.group.DoChan(key, func() (any, error) { return p.fetch(ctx, key, q) })
+	select {
+	case res := <-results:
+		if res.Err != nil {
+			return nil, false, res.Err
+		}
+		return res.Val.([]domain.TransportOption), false, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// fetch calls the provider on behalf of every caller waiting on key, so it
+// is not tied to the cancellation of whichever request started it.
+func (p *CachedProvider) fetch(ctx context.Context, key string, q TransportQuery) ([]domain.TransportOption, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+	defer cancel()
+	options, err := p.next.SearchTransport(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(options) > 0 {
+		now := p.now()
+		entry := CachedSearch{Options: options, StoredAt: now}
+		if err := p.cache.Put(ctx, key, entry, now.Add(p.ttl+p.stale)); err != nil {
+			log.Printf("search cache put %s: %v", p.name, err)
+		}
+	}
+	return options, nil
+}
+
+type memoryEntry struct {
+	search    CachedSearch
+	keepUntil time.Time
+}
+
+// MemorySearchCache keeps entries in process. It holds at most 1000 and
+// drops expired ones, then the oldest, to make room.
+type MemorySearchCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+func NewMemorySearchCache() *MemorySearchCache {
+	return &MemorySearchCache{entries: map[string]memoryEntry{}, now: time.Now}
+}
+
+func (c *MemorySearchCache) Get(_ context.Context, key string) (CachedSearch, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return CachedSearch{}, false, nil
+	}
+	if !c.now().Before(entry.keepUntil) {
+		delete(c.entries, key)
+		return CachedSearch{}, false, nil
+	}
+	search := entry.search
+	search.Options = slices.Clone(search.Options)
+	return search, true, nil
+}
+
+func (c *MemorySearchCache) Put(_ context.Context, key string, entry CachedSearch, keepUntil time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxMemoryEntries {
+		c.evict()
+	}
+	entry.Options = slices.Clone(entry.Options)
+	c.entries[key] = memoryEntry{search: entry, keepUntil: keepUntil}
+	return nil
+}
+
+func (c *MemorySearchCache) evict() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.keepUntil) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < maxMemoryEntries {
+		return
+	}
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].search.StoredAt.Before(c.entries[keys[j]].search.StoredAt)
+	})
+	for _, key := range keys[:len(keys)-maxMemoryEntries+1] {
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+// countingProvider answers with a price that rises on every call, so tests
+// can tell a cached answer from a fresh one.
+type countingProvider struct {
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (p *countingProvider) SearchTransport(ctx context.Context, _ TransportQuery) ([]domain.TransportOption, error) {
+	n := p.calls.Add(1)
+	time.Sleep(p.delay)
+	return []domain.TransportOption{{Provider: "rail", Price: money.FromFloat(float64(10 * n))}}, nil
+}
+
+func TestCacheKey_Normalises(t *testing.T) {
+	a := CacheKey("opendata", TransportQuery{From: " Zurich  HB", To: "Bern"})
+	b := CacheKey("OpenData", TransportQuery{From: "zurich hb", To: "BERN", Passengers: 1})
+	if a != b {
+		t.Fatalf("expected equal keys, got %q and %q", a, b)
+	}
+	if a == CacheKey("opendata", TransportQuery{From: "Zurich HB", To: "Bern", Passengers: 2}) {
+		t.Fatal("party size must be part of the key")
+	}
+	if a == CacheKey("opendata", TransportQuery{From: "Zurich HB", To: "Bern", Date: "2026-03-20"}) {
+		t.Fatal("date must be part of the key")
+	}
+}
+
+func TestCachedProvider_FreshStaleAndExpired(t *testing.T) {
+	upstream := &countingProvider{}
+	cache := NewMemorySearchCache()
+	p := NewCachedProvider("rail", upstream, cache, time.Minute, 10*time.Minute)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+	cache.now = p.now
+	q := TransportQuery{From: "Berlin", To: "Prague"}
+	ctx := context.Background()
+
+	price := func() (money.Amount, bool) {
+		options, cached, err := p.search(ctx, q)
+		if err != nil || len(options) != 1 {
+			t.Fatalf("unexpected search result %+v, %v", options, err)
+		}
+		return options[0].Price, cached
+	}
+
+	if got, cached := price(); got != money.FromFloat(10) || cached {
+		t.Fatalf("expected a fresh first answer, got %v cached=%v", got, cached)
+	}
+	now = now.Add(30 * time.Second)
+	if got, cached := price(); got != money.FromFloat(10) || !cached || upstream.calls.Load() != 1 {
+		t.Fatalf("expected a cache hit within the TTL, got %v cached=%v calls=%d", got, cached, upstream.calls.Load())
+	}
+
+	// Past the TTL the stale answer is served while a refresh runs.
+	now = now.Add(2 * time.Minute)
+	if got, cached := price(); got != money.FromFloat(10) || !cached {
+		t.Fatalf("expected the stale answer, got %v cached=%v", got, cached)
+	}
+	key := CacheKey("rail", q)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if entry, ok, _ := cache.Get(ctx, key); ok && entry.Options[0].Price != money.FromFloat(10) {
+			break
+		}
+	}
+	if got, _ := price(); got != money.FromFloat(20) {
+		t.Fatalf("expected the refreshed answer, got %v", got)
+	}
+
+	// Past the stale window the provider is asked and the caller waits.
+	now = now.Add(time.Hour)
+	if got, cached := price(); got != money.FromFloat(30) || cached {
+		t.Fatalf("expected a fresh answer after expiry, got %v cached=%v", got, cached)
+	}
+}
+
+func TestCachedProvider_CoalescesConcurrentSearches(t *testing.T) {
+	upstream := &countingProvider{delay: 50 * time.Millisecond}
+	p := NewCachedProvider("rail", upstream, NewMemorySearchCache(), time.Minute, time.Minute)
+	q := TransportQuery{From: "Berlin", To: "Prague"}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.SearchTransport(context.Background(), q); err != nil {
+				t.Errorf("search failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := upstream.calls.Load(); calls != 1 {
+		t.Fatalf("expected one upstream call for identical searches, got %d", calls)
+	}
+}
+
+func TestCachedProvider_CachesLateAnswers(t *testing.T) {
+	upstream := &countingProvider{delay: 50 * time.Millisecond}
+	p := NewCachedProvider("rail", upstream, NewMemorySearchCache(), time.Minute, time.Minute)
+	q := TransportQuery{From: "Berlin", To: "Prague"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.SearchTransport(ctx, q); err == nil {
+		t.Fatal("expected the caller to give up at its deadline")
+	}
+	time.Sleep(80 * time.Millisecond)
+	if _, cached, err := p.search(context.Background(), q); err != nil || !cached {
+		t.Fatalf("expected the late answer to be cached, got cached=%v err=%v", cached, err)
+	}
+}
+
+func TestRegistry_EnableCacheMarksCachedSources(t *testing.T) {
+	r := NewRegistry(time.Second)
+	r.Register("rail", &countingProvider{})
+	r.EnableCache(NewMemorySearchCache(), CacheConfig{TTL: time.Minute, ProviderTTL: map[string]time.Duration{"live": 0}})
+	live := &countingProvider{}
+	r.Register("live", live)
+	q := TransportQuery{From: "Berlin", To: "Prague"}
+
+	r.Search(context.Background(), q)
+	_, sources := r.Search(context.Background(), q)
+	if !sources[0].Cached || sources[1].Cached {
+		t.Fatalf("expected only rail to be served from cache, got %+v", sources)
+	}
+	if live.calls.Load() != 2 {
+		t.Fatalf("expected caching to be off for live, got %d calls", live.calls.Load())
+	}
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	defaultFlightProvider = "FlightAPI"
+	defaultFlightTimeout  = 2500 * time.Millisecond
+	flightCurrency        = "EUR"
+)
+
+//go:embed fixtures/flights.json
+var defaultFlightFixture []byte
+
+// FlightProvider queries a JSON budget-flight API:
+//
+//	GET {baseURL}/flights?from=&to=&date=&adults=
+//	{"flights": [{"carrier", "flightNumber", "from", "to", "departure", "arrival",
+//	  "stops", "price", "currency", "cabinBag": {"included", "fee"},
+//	  "checkedBag": {"included", "fee"}, "refundable", "url"}]}
+//
+// Times are RFC 3339 and prices and bag fees are per passenger. Fares come
+// with their baggage rules, and the bags the query brings are priced into
+// each option's BaggageCost. Only EUR fares are kept, like stays.
+type FlightProvider struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+func NewFlightProvider(name, baseURL string, client *http.Client) *FlightProvider {
+	return &FlightProvider{name: name, baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// NewFlightProviderFromEnv builds the provider for the API at
+// FLIGHT_PROVIDER_BASE_URL, named FLIGHT_PROVIDER_NAME, with a
+// FLIGHT_PROVIDER_TIMEOUT_MS timeout.
+func NewFlightProviderFromEnv() (*FlightProvider, error) {
+	baseURL := strings.TrimSpace(os.Getenv("FLIGHT_PROVIDER_BASE_URL"))
+	if baseURL == "" {
+		return nil, fmt.Errorf("FLIGHT_PROVIDER_BASE_URL is not set")
+	}
+	timeout := defaultFlightTimeout
+	if raw := strings.TrimSpace(os.Getenv("FLIGHT_PROVIDER_TIMEOUT_MS")); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+	}
+	name := strings.TrimSpace(os.Getenv("FLIGHT_PROVIDER_NAME"))
+	if name == "" {
+		name = defaultFlightProvider
+	}
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: outboundTransport(name),
+	}
+	return NewFlightProvider(name, baseURL, client), nil
+}
+
+type flightBag struct {
+	Included bool         `json:"included"`
+	Fee      money.Amount `json:"fee"`
+}
+
+func (b flightBag) allowance() domain.BagAllowance {
+	return domain.BagAllowance{Included: b.Included, Fee: b.Fee}
+}
+
+type flightFare struct {
+	Carrier      string       `json:"carrier"`
+	FlightNumber string       `json:"flightNumber"`
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	Departure    string       `json:"departure"`
+	Arrival      string       `json:"arrival"`
+	Stops        int          `json:"stops"`
+	Price        money.Amount `json:"price"`
+	Currency     string       `json:"currency"`
+	CabinBag     flightBag    `json:"cabinBag"`
+	CheckedBag   flightBag    `json:"checkedBag"`
+	Refundable   bool         `json:"refundable"`
+	URL          string       `json:"url"`
+}
+
+func (p *FlightProvider) SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error) {
+	if strings.TrimSpace(q.From) == "" || strings.TrimSpace(q.To) == "" {
+		return nil, fmt.Errorf("flight search needs both from and to")
+	}
+	u, err := url.Parse(p.baseURL + "/flights")
+	if err != nil {
+		return nil, fmt.Errorf("parse flight provider url: %w", err)
+	}
+	params := u.Query()
+	params.Set("from", q.From)
+	params.Set("to", q.To)
+	params.Set("adults", strconv.Itoa(q.passengers()))
+	if q.Date != "" {
+		params.Set("date", q.Date)
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create flight provider request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("flight provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("flight provider returned status %d", resp.StatusCode)
+	}
+	var payload struct {
+		Flights []flightFare `json:"flights"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode flight provider response: %w", err)
+	}
+
+	options := make([]domain.TransportOption, 0, len(payload.Flights))
+	for _, f := range payload.Flights {
+		if f.Price <= 0 || (f.Currency != "" && !strings.EqualFold(f.Currency, flightCurrency)) {
+			continue
+		}
+		dep, err1 := time.Parse(time.RFC3339, f.Departure)
+		arr, err2 := time.Parse(time.RFC3339, f.Arrival)
+		if err1 != nil || err2 != nil || !arr.After(dep) || !withinTime(q, dep, arr) {
+			continue
+		}
+		baggage := &domain.Baggage{CabinBag: f.CabinBag.allowance(), CheckedBag: f.CheckedBag.allowance()}
+		refundable := f.Refundable
+		options = append(options, domain.TransportOption{
+			Provider:      p.name,
+			Mode:          "flight",
+			DurationHours: math.Round(arr.Sub(dep).Hours()*10) / 10,
+			Price:         f.Price.Mul(float64(q.passengers())),
+			PriceKind:     domain.PriceQuoted,
+			Deeplink:      f.URL,
+			Departure:     f.Departure,
+			Arrival:       f.Arrival,
+			Transfers:     max(f.Stops, 0),
+			Legs: []domain.TransportLeg{{
+				Kind: domain.LegRide, Mode: "flight", Line: f.FlightNumber, Operator: f.Carrier,
+				From: domain.LegStop{Station: f.From, Time: f.Departure},
+				To:   domain.LegStop{Station: f.To, Time: f.Arrival},
+			}},
+			Baggage:     baggage,
+			Refundable:  &refundable,
+			BaggageCost: baggage.Cost(q.CabinBags, q.CheckedBags),
+		})
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("flight provider returned no usable flights")
+	}
+	return options, nil
+}
+
+// withinTime keeps flights leaving at or after q.Time, or arriving by it
+// when q.ArriveBy is set, in their local times.
+func withinTime(q TransportQuery, dep, arr time.Time) bool {
+	if q.Time == "" {
+		return true
+	}
+	if q.ArriveBy {
+		return arr.Format("15:04") <= q.Time
+	}
+	return dep.Format("15:04") >= q.Time
+}
+
+// fixtureFlight is a daily flight in a fixture; Departs and Arrives are
+// local times in Timezone.
+type fixtureFlight struct {
+	flightFare
+	Departs  string `json:"departs"`
+	Arrives  string `json:"arrives"`
+	Timezone string `json:"timezone"`
+}
+
+// FlightFixtureHandler serves the flight API from a JSON fixture of daily
+// flights (the bundled one when data is nil), so the flight provider can be
+// developed and tested without a network.
+func FlightFixtureHandler(data []byte) (http.Handler, error) {
+	if data == nil {
+		data = defaultFlightFixture
+	}
+	var flights []fixtureFlight
+	if err := json.Unmarshal(data, &flights); err != nil {
+		return nil, fmt.Errorf("decode flight fixture: %w", err)
+	}
+	for _, f := range flights {
+		if _, err := time.LoadLocation(f.Timezone); err != nil {
+			return nil, fmt.Errorf("flight %s: %w", f.FlightNumber, err)
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /flights", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		date := query.Get("date")
+		if date == "" {
+			date = time.Now().AddDate(0, 0, 1).Format(stayDateLayout)
+		}
+		out := []flightFare{}
+		for _, f := range flights {
+			if !strings.EqualFold(f.From, query.Get("from")) || !strings.EqualFold(f.To, query.Get("to")) {
+				continue
+			}
+			loc, _ := time.LoadLocation(f.Timezone)
+			dep, err1 := time.ParseInLocation(stayDateLayout+" 15:04", date+" "+f.Departs, loc)
+			arr, err2 := time.ParseInLocation(stayDateLayout+" 15:04", date+" "+f.Arrives, loc)
+			if err1 != nil || err2 != nil {
+				http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			if !arr.After(dep) {
+				arr = arr.AddDate(0, 0, 1)
+			}
+			fare := f.flightFare
+			fare.Departure, fare.Arrival = dep.Format(time.RFC3339), arr.Format(time.RFC3339)
+			if fare.Currency == "" {
+				fare.Currency = flightCurrency
+			}
+			if fare.URL == "" {
+				fare.URL = "https://example.com/flights/" + strings.ReplaceAll(strings.ToLower(f.FlightNumber), " ", "") + "/" + date
+			}
+			out = append(out, fare)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"flights": out})
+	})
+	return mux, nil
+}
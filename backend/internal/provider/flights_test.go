@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func fixtureFlights(t *testing.T) *FlightProvider {
+	t.Helper()
+	handler, err := FlightFixtureHandler(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewFlightProvider("SkyAPI", srv.URL, srv.Client())
+}
+
+func TestFlightProvider_NormalisesFareRules(t *testing.T) {
+	p := fixtureFlights(t)
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "prague", Date: "2026-03-06", Passengers: 2, CabinBags: 2, CheckedBags: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 3 {
+		t.Fatalf("expected three Prague flights, got %+v", opts)
+	}
+	budget := opts[0]
+	// Two fares, two cabin bags at 14 and a checked bag at 29.
+	if budget.Price != money.FromFloat(69.98) || budget.BaggageCost != money.FromFloat(57) || budget.TotalCost() != money.FromFloat(126.98) {
+		t.Fatalf("unexpected budget fare %+v", budget)
+	}
+	if budget.Departure != "2026-03-06T07:05:00+01:00" || budget.DurationHours != 1.1 || budget.Legs[0].Line != "SK 211" || budget.Legs[0].Operator != "SkySaver" {
+		t.Fatalf("unexpected budget flight %+v", budget)
+	}
+	if budget.Refundable == nil || *budget.Refundable || budget.Baggage.CabinBag.Included {
+		t.Fatalf("expected a non-refundable fare without bags, got %+v", budget)
+	}
+	full := opts[2]
+	if full.BaggageCost != 0 || full.Refundable == nil || !*full.Refundable || !full.Baggage.CheckedBag.Included {
+		t.Fatalf("expected an all-in refundable fare, got %+v", full)
+	}
+
+	// Bags change the ranking: with two checked bags the all-in fare beats
+	// the late budget flight.
+	opts, _ = p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Prague", Date: "2026-03-06", Time: "12:00", Passengers: 2, CabinBags: 2, CheckedBags: 2})
+	merged := Merge(opts)
+	if len(merged) != 2 || merged[0].Legs[0].Line != "CC 722" {
+		t.Fatalf("expected the afternoon flights with the all-in fare first, got %+v", merged)
+	}
+}
+
+func TestFlightProvider_Errors(t *testing.T) {
+	p := fixtureFlights(t)
+	if _, err := p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Atlantis", Date: "2026-03-06"}); err == nil {
+		t.Fatal("expected an error without flights")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"flights":[{"carrier":"Abroad","price":900,"currency":"CZK","departure":"2026-03-06T07:00:00+01:00","arrival":"2026-03-06T08:00:00+01:00"}]}`))
+	}))
+	defer srv.Close()
+	p = NewFlightProvider("SkyAPI", srv.URL, srv.Client())
+	if _, err := p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Prague"}); err == nil {
+		t.Fatal("expected non-EUR fares to be dropped")
+	}
+}
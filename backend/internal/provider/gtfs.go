@@ -0,0 +1,839 @@
+package provider
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// Feeds name their timezone; the database is bundled so that does not
+	// depend on the host.
+	_ "time/tzdata"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
+)
+
+const (
+	defaultGTFSName      = "GTFS"
+	defaultGTFSMinChange = 2 * time.Minute
+	gtfsDateLayout       = "20060102"
+	// gtfsResults is how many journeys a search returns, like opendata's
+	// limit; gtfsMaxScans bounds the scans made to find them.
+	gtfsResults  = 3
+	gtfsMaxScans = 200
+	// gtfsArriveByWindow is how long before an arrive-by time journeys are
+	// looked for.
+	gtfsArriveByWindow = 12 * time.Hour
+	unreached          = math.MaxInt32
+)
+
+type gtfsStop struct {
+	name     string
+	norm     string
+	lat, lon float64
+	located  bool
+}
+
+type gtfsRoute struct {
+	line     string
+	mode     string
+	operator string
+}
+
+type gtfsTrip struct {
+	route    int
+	service  string
+	headsign string
+}
+
+// gtfsConnection is one hop of a trip between consecutive stops, times in
+// seconds after midnight of the service day.
+type gtfsConnection struct {
+	from, to int32
+	dep, arr int32
+	trip     int32
+}
+
+// gtfsFootpath is a walk between stops; secs < 0 means the minimum
+// change time, which is only known once the provider is configured.
+type gtfsFootpath struct {
+	to   int
+	secs int
+}
+
+type gtfsService struct {
+	weekdays   [7]bool
+	start, end string
+	added      map[string]bool
+	removed    map[string]bool
+}
+
+// gtfsServiceDay is the trips running on one service day, with offset
+// added to their connection times to make them relative to the searched
+// day: about -24h for the day before, whose trips running past midnight can
+// still be taken.
+type gtfsServiceDay struct {
+	active []bool
+	offset int
+}
+
+func (s *gtfsService) activeOn(day time.Time) bool {
+	date := day.Format(gtfsDateLayout)
+	if s.removed[date] {
+		return false
+	}
+	if s.added[date] {
+		return true
+	}
+	return s.weekdays[day.Weekday()] && date >= s.start && date <= s.end
+}
+
+// GTFSProvider answers journey searches from GTFS timetables loaded into
+// memory, for operators that publish schedules but no live API. Journeys
+// are found with a connection scan over the day's trips, changing between
+// trips at the same stop or along the feed's transfers.
+type GTFSProvider struct {
+	name      string
+	country   string
+	loc       *time.Location
+	minChange int
+	now       func() time.Time
+
+	stops       []gtfsStop
+	routes      []gtfsRoute
+	trips       []gtfsTrip
+	connections []gtfsConnection
+	footpaths   [][]gtfsFootpath
+	services    map[string]*gtfsService
+}
+
+// NewGTFSProviderFromEnv loads the feeds in GTFS_FEEDS (comma-separated zip
+// files or unpacked directories). GTFS_PROVIDER_NAME labels the options,
+// GTFS_COUNTRY picks fares from the fare table and GTFS_MIN_TRANSFER (a Go
+// duration, "2m" by default) is the time allowed to change at a stop.
+func NewGTFSProviderFromEnv() (*GTFSProvider, error) {
+	var paths []string
+	for _, path := range strings.Split(os.Getenv("GTFS_FEEDS"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("GTFS_FEEDS is not set")
+	}
+	feeds := make([]fs.FS, 0, len(paths))
+	for _, path := range paths {
+		feed, closer, err := OpenGTFSFeed(path)
+		if err != nil {
+			return nil, err
+		}
+		if closer != nil {
+			defer closer.Close()
+		}
+		feeds = append(feeds, feed)
+	}
+	p, err := LoadGTFS(feeds...)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(os.Getenv("GTFS_PROVIDER_NAME")); name != "" {
+		p.name = name
+	}
+	p.country = strings.ToUpper(strings.TrimSpace(os.Getenv("GTFS_COUNTRY")))
+	if raw := strings.TrimSpace(os.Getenv("GTFS_MIN_TRANSFER")); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid GTFS_MIN_TRANSFER %q", raw)
+		}
+		p.minChange = int(d.Seconds())
+	}
+	return p, nil
+}
+
+// OpenGTFSFeed opens a GTFS zip, or a directory holding the unpacked files.
+// The closer is nil for directories.
+func OpenGTFSFeed(path string) (fs.FS, io.Closer, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open gtfs feed: %w", err)
+	}
+	if info.IsDir() {
+		return os.DirFS(path), nil, nil
+	}
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open gtfs feed %s: %w", path, err)
+	}
+	return r, r, nil
+}
+
+// LoadGTFS indexes the feeds. IDs only need to be unique within a feed;
+// the timezone is the first feed's agency timezone.
+func LoadGTFS(feeds ...fs.FS) (*GTFSProvider, error) {
+	p := &GTFSProvider{
+		name: defaultGTFSName, loc: time.UTC, now: time.Now,
+		minChange: int(defaultGTFSMinChange.Seconds()), services: map[string]*gtfsService{},
+	}
+	for i, feed := range feeds {
+		if err := p.load(feed, strconv.Itoa(i)+":"); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.connections) == 0 {
+		return nil, fmt.Errorf("gtfs feeds have no timetabled trips")
+	}
+	sort.Slice(p.connections, func(i, j int) bool {
+		a, b := p.connections[i], p.connections[j]
+		if a.dep != b.dep {
+			return a.dep < b.dep
+		}
+		return a.arr < b.arr
+	})
+	return p, nil
+}
+
+func (p *GTFSProvider) load(feed fs.FS, prefix string) error {
+	agencies := map[string]string{}
+	defaultAgency := ""
+	err := readGTFS(feed, "agency.txt", false, func(row gtfsRow) error {
+		agencies[row.get("agency_id")] = row.get("agency_name")
+		if defaultAgency == "" {
+			defaultAgency = row.get("agency_name")
+		}
+		if tz := row.get("agency_timezone"); tz != "" && p.loc == time.UTC && len(p.stops) == 0 {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				return fmt.Errorf("agency timezone: %w", err)
+			}
+			p.loc = loc
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	stopIndex := map[string]int{}
+	parents := map[int]string{}
+	err = readGTFS(feed, "stops.txt", true, func(row gtfsRow) error {
+		stop := gtfsStop{name: row.get("stop_name")}
+		stop.norm = normalizeStopName(stop.name)
+		lat, err1 := strconv.ParseFloat(row.get("stop_lat"), 64)
+		lon, err2 := strconv.ParseFloat(row.get("stop_lon"), 64)
+		if err1 == nil && err2 == nil {
+			stop.lat, stop.lon, stop.located = lat, lon, true
+		}
+		stopIndex[row.get("stop_id")] = len(p.stops)
+		if parent := row.get("parent_station"); parent != "" {
+			parents[len(p.stops)] = parent
+		}
+		p.stops = append(p.stops, stop)
+		p.footpaths = append(p.footpaths, nil)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	routeIndex := map[string]int{}
+	err = readGTFS(feed, "routes.txt", true, func(row gtfsRow) error {
+		line := row.get("route_short_name")
+		if line == "" {
+			line = row.get("route_long_name")
+		}
+		operator, ok := agencies[row.get("agency_id")]
+		if !ok {
+			operator = defaultAgency
+		}
+		routeType, _ := strconv.Atoi(row.get("route_type"))
+		routeIndex[row.get("route_id")] = len(p.routes)
+		p.routes = append(p.routes, gtfsRoute{line: line, mode: gtfsMode(routeType), operator: operator})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tripIndex := map[string]int{}
+	err = readGTFS(feed, "trips.txt", true, func(row gtfsRow) error {
+		route, ok := routeIndex[row.get("route_id")]
+		if !ok {
+			return fmt.Errorf("trip %s has unknown route %s", row.get("trip_id"), row.get("route_id"))
+		}
+		tripIndex[row.get("trip_id")] = len(p.trips)
+		p.trips = append(p.trips, gtfsTrip{route: route, service: prefix + row.get("service_id"), headsign: row.get("trip_headsign")})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.loadServices(feed, prefix); err != nil {
+		return err
+	}
+
+	type stopTime struct {
+		seq      int
+		stop     int
+		arr, dep int
+	}
+	byTrip := map[int][]stopTime{}
+	err = readGTFS(feed, "stop_times.txt", true, func(row gtfsRow) error {
+		trip, ok := tripIndex[row.get("trip_id")]
+		if !ok {
+			return nil
+		}
+		stop, ok := stopIndex[row.get("stop_id")]
+		if !ok {
+			return fmt.Errorf("stop time has unknown stop %s", row.get("stop_id"))
+		}
+		seq, err := strconv.Atoi(row.get("stop_sequence"))
+		if err != nil {
+			return fmt.Errorf("stop_sequence %q: %w", row.get("stop_sequence"), err)
+		}
+		arr, arrOK := parseGTFSTime(row.get("arrival_time"))
+		dep, depOK := parseGTFSTime(row.get("departure_time"))
+		if !arrOK && !depOK {
+			// Untimed stops are interpolated by the producer or skipped.
+			return nil
+		}
+		if !arrOK {
+			arr = dep
+		}
+		if !depOK {
+			dep = arr
+		}
+		byTrip[trip] = append(byTrip[trip], stopTime{seq: seq, stop: stop, arr: arr, dep: dep})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for trip, times := range byTrip {
+		sort.Slice(times, func(i, j int) bool { return times[i].seq < times[j].seq })
+		for i := 1; i < len(times); i++ {
+			from, to := times[i-1], times[i]
+			if to.arr < from.dep {
+				return fmt.Errorf("trip %d runs backwards in time at stop sequence %d", trip, to.seq)
+			}
+			p.connections = append(p.connections, gtfsConnection{
+				from: int32(from.stop), to: int32(to.stop), dep: int32(from.dep), arr: int32(to.arr), trip: int32(trip),
+			})
+		}
+	}
+
+	// Stops in the same station can be changed between in the minimum
+	// change time; transfers.txt adds or overrides walks.
+	children := map[string][]int{}
+	for stop, parent := range parents {
+		children[parent] = append(children[parent], stop)
+	}
+	for parent, stops := range children {
+		if i, ok := stopIndex[parent]; ok {
+			stops = append(stops, i)
+		}
+		for _, a := range stops {
+			for _, b := range stops {
+				if a != b {
+					p.addFootpath(a, b, -1)
+				}
+			}
+		}
+	}
+	return readGTFS(feed, "transfers.txt", false, func(row gtfsRow) error {
+		from, ok1 := stopIndex[row.get("from_stop_id")]
+		to, ok2 := stopIndex[row.get("to_stop_id")]
+		if !ok1 || !ok2 || from == to || row.get("transfer_type") == "3" {
+			return nil
+		}
+		secs, err := strconv.Atoi(row.get("min_transfer_time"))
+		if err != nil || secs < 0 {
+			secs = -1
+		}
+		p.addFootpath(from, to, secs)
+		return nil
+	})
+}
+
+func (p *GTFSProvider) addFootpath(from, to, secs int) {
+	for i, fp := range p.footpaths[from] {
+		if fp.to == to {
+			p.footpaths[from][i].secs = secs
+			return
+		}
+	}
+	p.footpaths[from] = append(p.footpaths[from], gtfsFootpath{to: to, secs: secs})
+}
+
+func (p *GTFSProvider) loadServices(feed fs.FS, prefix string) error {
+	service := func(id string) *gtfsService {
+		s, ok := p.services[prefix+id]
+		if !ok {
+			s = &gtfsService{added: map[string]bool{}, removed: map[string]bool{}}
+			p.services[prefix+id] = s
+		}
+		return s
+	}
+	days := []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	err := readGTFS(feed, "calendar.txt", false, func(row gtfsRow) error {
+		s := service(row.get("service_id"))
+		for i, day := range days {
+			s.weekdays[i] = row.get(day) == "1"
+		}
+		s.start, s.end = row.get("start_date"), row.get("end_date")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return readGTFS(feed, "calendar_dates.txt", false, func(row gtfsRow) error {
+		s := service(row.get("service_id"))
+		switch row.get("exception_type") {
+		case "1":
+			s.added[row.get("date")] = true
+		case "2":
+			s.removed[row.get("date")] = true
+		}
+		return nil
+	})
+}
+
+func (p *GTFSProvider) SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error) {
+	if strings.TrimSpace(q.From) == "" || strings.TrimSpace(q.To) == "" {
+		return nil, fmt.Errorf("gtfs search needs both from and to")
+	}
+	origins, targets := p.matchStops(q.From), p.matchStops(q.To)
+	if len(origins) == 0 || len(targets) == 0 {
+		return nil, fmt.Errorf("no gtfs stops match %q and %q", q.From, q.To)
+	}
+
+	now := p.now().In(p.loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.loc)
+	wall := now
+	if q.Date != "" {
+		d, err := time.ParseInLocation(queryDateLayout, q.Date, p.loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", q.Date)
+		}
+		day, wall = d, d
+	}
+	if q.Time != "" {
+		t, err := time.Parse("15:04", q.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", q.Time)
+		}
+		wall = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, p.loc)
+	}
+	// Stop times count from the service day's start, which is not midnight
+	// when the clocks change; so is the previous day's, 23-25h earlier.
+	base, prev := p.serviceStart(day), day.AddDate(0, 0, -1)
+	at := wall.Sub(base)
+
+	days := []gtfsServiceDay{
+		p.serviceDay(prev, int(p.serviceStart(prev).Sub(base).Seconds())),
+		p.serviceDay(day, 0),
+	}
+	var journeys [][]gtfsStep
+	if q.ArriveBy {
+		journeys = p.arriveBy(ctx, origins, targets, days, int((at - gtfsArriveByWindow).Seconds()), int(at.Seconds()))
+	} else {
+		journeys = p.departAfter(ctx, origins, targets, days, int(at.Seconds()))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(journeys) == 0 {
+		return nil, fmt.Errorf("no gtfs journeys from %s to %s", q.From, q.To)
+	}
+
+	model := Fares()
+	options := make([]domain.TransportOption, 0, len(journeys))
+	for _, steps := range journeys {
+		option := p.option(base, steps)
+		fare, ok := model.Price(ctx, fares.Journey{
+			Provider: "gtfs", Country: p.country, Mode: option.Mode, Hours: option.DurationHours, Legs: option.Legs,
+		}, q.Party())
+		if !ok {
+			continue
+		}
+		option.Price, option.PriceKind, option.Passes = fare.Amount, fare.Kind, fare.Passes
+		fare.Annotate(option.Legs)
+		options = append(options, option)
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no fares for gtfs journeys from %s to %s", q.From, q.To)
+	}
+	return options, nil
+}
+
+// serviceStart is when GTFS stop times on day count from: noon minus 12h,
+// which is midnight except on the days the clocks change.
+func (p *GTFSProvider) serviceStart(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, p.loc).Add(-12 * time.Hour)
+}
+
+func (p *GTFSProvider) serviceDay(day time.Time, offset int) gtfsServiceDay {
+	active := make([]bool, len(p.trips))
+	for i, trip := range p.trips {
+		if s, ok := p.services[trip.service]; ok {
+			active[i] = s.activeOn(day)
+		}
+	}
+	return gtfsServiceDay{active: active, offset: offset}
+}
+
+// departAfter finds the earliest-arriving journey leaving at or after
+// start, then the next ones, each leaving after the last.
+func (p *GTFSProvider) departAfter(ctx context.Context, origins, targets []int, days []gtfsServiceDay, start int) [][]gtfsStep {
+	var journeys [][]gtfsStep
+	for scans := 0; len(journeys) < gtfsResults && scans < gtfsMaxScans && ctx.Err() == nil; scans++ {
+		steps, ok := p.scan(origins, targets, days, start)
+		if !ok {
+			break
+		}
+		journeys = append(journeys, steps)
+		start = p.firstDeparture(steps) + 1
+	}
+	return journeys
+}
+
+// arriveBy keeps the last journeys that arrive by deadline among those
+// leaving from start on.
+func (p *GTFSProvider) arriveBy(ctx context.Context, origins, targets []int, days []gtfsServiceDay, start, deadline int) [][]gtfsStep {
+	start = max(start, 0)
+	var journeys [][]gtfsStep
+	for scans := 0; scans < gtfsMaxScans && ctx.Err() == nil; scans++ {
+		steps, ok := p.scan(origins, targets, days, start)
+		if !ok || p.lastArrival(steps) > deadline {
+			break
+		}
+		journeys = append(journeys, steps)
+		start = p.firstDeparture(steps) + 1
+	}
+	if len(journeys) > gtfsResults {
+		journeys = journeys[len(journeys)-gtfsResults:]
+	}
+	return journeys
+}
+
+// gtfsStep is how a stop was reached: by riding a trip from the board
+// connection to the alight one, offset like the service day it ran on, or
+// by walking from another stop.
+type gtfsStep struct {
+	walk          bool
+	board, alight int
+	offset        int
+	from, to      int
+	secs          int
+	arrival       int
+}
+
+// scan is an earliest-arrival connection scan: connections of every
+// service day are visited in departure order and relax the stops they
+// reach, boarding a trip wherever a traveller can already be in time.
+func (p *GTFSProvider) scan(origins, targets []int, days []gtfsServiceDay, start int) ([]gtfsStep, bool) {
+	arrival := make([]int, len(p.stops))
+	ready := make([]int, len(p.stops))
+	via := make([]*gtfsStep, len(p.stops))
+	isOrigin, isTarget := make([]bool, len(p.stops)), make([]bool, len(p.stops))
+	for i := range arrival {
+		arrival[i], ready[i] = unreached, unreached
+	}
+	// boarded and next are per service day: the connection each trip was
+	// boarded at, and the next connection to visit.
+	boarded := make([][]int, len(days))
+	next := make([]int, len(days))
+	for d, day := range days {
+		boarded[d] = make([]int, len(p.trips))
+		for i := range boarded[d] {
+			boarded[d][i] = -1
+		}
+		next[d] = sort.Search(len(p.connections), func(i int) bool { return int(p.connections[i].dep)+day.offset >= start })
+	}
+	for _, o := range origins {
+		isOrigin[o] = true
+	}
+	for _, t := range targets {
+		isTarget[t] = true
+	}
+	best, bestStop := unreached, -1
+	reach := func(stop, at, readyAt int, step *gtfsStep) {
+		if at >= arrival[stop] {
+			return
+		}
+		arrival[stop], ready[stop], via[stop] = at, readyAt, step
+		if isTarget[stop] && at < best {
+			best, bestStop = at, stop
+		}
+	}
+	for _, o := range origins {
+		reach(o, start, start, nil)
+	}
+	for _, o := range origins {
+		for _, fp := range p.footpaths[o] {
+			secs := p.walkSecs(fp)
+			reach(fp.to, start+secs, start+secs, &gtfsStep{walk: true, from: o, to: fp.to, secs: secs, arrival: start + secs})
+		}
+	}
+	if bestStop >= 0 && via[bestStop] == nil {
+		// Already there.
+		return nil, false
+	}
+
+	for {
+		d, dep := -1, unreached
+		for k, day := range days {
+			if next[k] < len(p.connections) {
+				if t := int(p.connections[next[k]].dep) + day.offset; t < dep {
+					d, dep = k, t
+				}
+			}
+		}
+		if d < 0 || dep >= best {
+			break
+		}
+		i, offset := next[d], days[d].offset
+		next[d]++
+		c := p.connections[i]
+		if !days[d].active[c.trip] {
+			continue
+		}
+		if boarded[d][c.trip] < 0 {
+			if ready[c.from] > dep {
+				continue
+			}
+			boarded[d][c.trip] = i
+		}
+		board := boarded[d][c.trip]
+		to, arr := int(c.to), int(c.arr)+offset
+		if arr >= arrival[to] {
+			continue
+		}
+		reach(to, arr, arr+p.minChange, &gtfsStep{board: board, alight: i, offset: offset, from: int(p.connections[board].from), to: to, arrival: arr})
+		for _, fp := range p.footpaths[to] {
+			secs := p.walkSecs(fp)
+			reach(fp.to, arr+secs, arr+secs, &gtfsStep{walk: true, from: to, to: fp.to, secs: secs, arrival: arr + secs})
+		}
+	}
+	if bestStop < 0 {
+		return nil, false
+	}
+
+	var steps []gtfsStep
+	for stop := bestStop; via[stop] != nil && len(steps) <= len(p.stops); stop = via[stop].from {
+		steps = append(steps, *via[stop])
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	// Leading and trailing walks within the origin or destination add
+	// nothing to a journey that has a ride; walks to or from other stops do.
+	for len(steps) > 1 && steps[0].walk && isOrigin[steps[0].to] {
+		steps = steps[1:]
+	}
+	for len(steps) > 1 && steps[len(steps)-1].walk && isTarget[steps[len(steps)-1].from] {
+		steps = steps[:len(steps)-1]
+	}
+	return steps, true
+}
+
+func (p *GTFSProvider) walkSecs(fp gtfsFootpath) int {
+	if fp.secs < 0 {
+		return p.minChange
+	}
+	return fp.secs
+}
+
+func (p *GTFSProvider) firstDeparture(steps []gtfsStep) int {
+	for _, s := range steps {
+		if !s.walk {
+			return int(p.connections[s.board].dep) + s.offset
+		}
+	}
+	return steps[0].arrival - steps[0].secs
+}
+
+func (p *GTFSProvider) lastArrival(steps []gtfsStep) int { return steps[len(steps)-1].arrival }
+
+// option turns a scanned journey, its times counted from base, into a
+// TransportOption, unpriced.
+func (p *GTFSProvider) option(base time.Time, steps []gtfsStep) domain.TransportOption {
+	at := func(secs int) string { return base.Add(time.Duration(secs) * time.Second).Format(time.RFC3339) }
+	stop := func(i, secs int) domain.LegStop {
+		return domain.LegStop{Station: p.stops[i].name, Time: at(secs)}
+	}
+	legs := make([]domain.TransportLeg, 0, len(steps))
+	rides, mode, longest := 0, "train", -1
+	for i, s := range steps {
+		if s.walk {
+			start := s.arrival - s.secs
+			if i == 0 && len(steps) > 1 {
+				// A walk to the first ride ends as it leaves.
+				start = p.firstDeparture(steps) - s.secs
+			}
+			legs = append(legs, domain.TransportLeg{
+				Kind: domain.LegWalk, Mode: "walk",
+				From: stop(s.from, start), To: stop(s.to, start+s.secs), WalkMinutes: (s.secs + 59) / 60,
+				DistanceKm: p.distance(s.from, s.to),
+			})
+			continue
+		}
+		board, alight := p.connections[s.board], p.connections[s.alight]
+		trip := p.trips[board.trip]
+		route := p.routes[trip.route]
+		legs = append(legs, domain.TransportLeg{
+			Kind: domain.LegRide, Mode: route.mode, Line: route.line, Operator: route.operator, Direction: trip.headsign,
+			From: stop(int(board.from), int(board.dep)+s.offset), To: stop(int(alight.to), int(alight.arr)+s.offset),
+			DistanceKm: p.distance(int(board.from), int(alight.to)),
+		})
+		rides++
+		if length := int(alight.arr - board.dep); length > longest {
+			longest, mode = length, route.mode
+		}
+	}
+	departure, arrival := legs[0].From.Time, legs[len(legs)-1].To.Time
+	dep, _ := time.Parse(time.RFC3339, departure)
+	arr, _ := time.Parse(time.RFC3339, arrival)
+	return domain.TransportOption{
+		Provider:      p.name,
+		Mode:          mode,
+		DurationHours: math.Round(arr.Sub(dep).Hours()*10) / 10,
+		Departure:     departure,
+		Arrival:       arrival,
+		Transfers:     max(rides-1, 0),
+		Legs:          legs,
+	}
+}
+
+func (p *GTFSProvider) distance(from, to int) float64 {
+	a, b := p.stops[from], p.stops[to]
+	if !a.located || !b.located {
+		return 0
+	}
+	return math.Round(fares.StraightLineKm(a.lat, a.lon, b.lat, b.lon)*10) / 10
+}
+
+// matchStops finds the stops a place name means: those named exactly so,
+// or whose name begins with it as whole words ("Bern" for "Bern, Bahnhof"),
+// ignoring case and accents.
+func (p *GTFSProvider) matchStops(place string) []int {
+	want := normalizeStopName(place)
+	if want == "" {
+		return nil
+	}
+	var matches []int
+	for i, s := range p.stops {
+		if s.norm == want || strings.HasPrefix(s.norm, want+" ") {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+var stripMarks = runes.Remove(runes.In(unicode.Mn))
+
+// normalizeStopName folds case and accents and reduces punctuation to
+// single spaces, so "Zürich HB" and "zurich  hb" compare equal.
+func normalizeStopName(name string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, stripMarks, norm.NFC), name)
+	if err != nil {
+		folded = name
+	}
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// parseGTFSTime reads H:MM:SS, which may run past 24:00 for trips that
+// continue after midnight.
+func parseGTFSTime(raw string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(raw), ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	var secs int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		secs = secs*60 + n
+	}
+	return secs, true
+}
+
+// gtfsMode maps a GTFS route_type, basic or extended, onto our modes.
+func gtfsMode(routeType int) string {
+	switch {
+	case routeType == 0 || routeType == 5 || (routeType >= 900 && routeType < 1000):
+		return "tram"
+	case routeType == 3 || routeType == 11 || (routeType >= 200 && routeType < 300) || (routeType >= 700 && routeType < 800):
+		return "bus"
+	case routeType == 4 || (routeType >= 1000 && routeType < 1300):
+		return "ferry"
+	default:
+		return "train"
+	}
+}
+
+type gtfsRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r gtfsRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// readGTFS calls fn for every row of name in feed. Optional files that are
+// missing are skipped.
+func readGTFS(feed fs.FS, name string, required bool, fn func(gtfsRow) error) error {
+	f, err := feed.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("read %s header: %w", name, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))] = i
+	}
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		if err := fn(gtfsRow{columns: columns, record: record}); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+}
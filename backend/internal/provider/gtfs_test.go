@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func sampleGTFS(t *testing.T) *GTFSProvider {
+	t.Helper()
+	p, err := LoadGTFS(os.DirFS("testdata/gtfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func departures(opts []domain.TransportOption) []string {
+	out := make([]string, len(opts))
+	for i, o := range opts {
+		out[i] = o.Departure + " -> " + o.Arrival
+	}
+	return out
+}
+
+func TestGTFSSearch_ChangesAlongTransfers(t *testing.T) {
+	p := sampleGTFS(t)
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "zurich", To: "Thun", Date: "2026-03-06", Time: "08:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"2026-03-06T08:02:00+01:00 -> 2026-03-06T09:40:00+01:00",
+		"2026-03-06T09:02:00+01:00 -> 2026-03-06T10:40:00+01:00",
+		"2026-03-06T10:02:00+01:00 -> 2026-03-06T11:40:00+01:00",
+	}
+	if got := departures(opts); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("unexpected journeys %v", got)
+	}
+
+	first := opts[0]
+	if first.Provider != "GTFS" || first.Transfers != 1 || first.Mode != "train" || first.PriceKind != domain.PriceEstimated || first.Price <= 0 {
+		t.Fatalf("unexpected option %+v", first)
+	}
+	if len(first.Legs) != 3 {
+		t.Fatalf("expected ride, walk, ride; got %+v", first.Legs)
+	}
+	train, walk, bus := first.Legs[0], first.Legs[1], first.Legs[2]
+	if train.Line != "IR 15" || train.Operator != "SBB" || train.Direction != "Bern" || train.From.Station != "Zürich HB" || train.To.Station != "Bern" || train.DistanceKm < 90 {
+		t.Fatalf("unexpected train leg %+v", train)
+	}
+	if walk.Kind != domain.LegWalk || walk.WalkMinutes != 4 || walk.To.Station != "Bern, Bahnhof" {
+		t.Fatalf("unexpected walk leg %+v", walk)
+	}
+	if bus.Mode != "bus" || bus.Operator != "PostAuto" || bus.From.Time != "2026-03-06T09:10:00+01:00" {
+		t.Fatalf("unexpected bus leg %+v", bus)
+	}
+}
+
+func TestGTFSSearch_KeepsTheWalkToTheFirstRide(t *testing.T) {
+	p := sampleGTFS(t)
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "Wankdorf", To: "Thun", Date: "2026-03-06", Time: "09:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := opts[0]
+	if first.Departure != "2026-03-06T09:05:00+01:00" || len(first.Legs) != 2 {
+		t.Fatalf("expected to leave on foot at 09:05, got %s with %+v", first.Departure, first.Legs)
+	}
+	if walk := first.Legs[0]; walk.Kind != domain.LegWalk || walk.From.Station != "Wankdorf" || walk.To.Station != "Bern, Bahnhof" || walk.WalkMinutes != 5 {
+		t.Fatalf("unexpected walk leg %+v", walk)
+	}
+}
+
+func TestGTFSSearch_ArriveBy(t *testing.T) {
+	p := sampleGTFS(t)
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "Zürich HB", To: "thun", Date: "2026-03-06", Time: "10:45", ArriveBy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := departures(opts); len(got) != 2 || got[1] != "2026-03-06T09:02:00+01:00 -> 2026-03-06T10:40:00+01:00" {
+		t.Fatalf("expected the journeys arriving by 10:45, got %v", got)
+	}
+}
+
+func TestGTFSSearch_OvernightTrips(t *testing.T) {
+	p := sampleGTFS(t)
+	// Friday's last train runs on past midnight into Saturday.
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "Olten", To: "Bern", Date: "2026-03-07", Time: "00:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := departures(opts); len(got) == 0 || got[0] != "2026-03-07T00:22:00+01:00 -> 2026-03-07T00:52:00+01:00" {
+		t.Fatalf("expected Friday's night train first, got %v", got)
+	}
+	// Sunday runs no weekday trains, so Monday starts with the morning ones.
+	opts, err = p.SearchTransport(context.Background(), TransportQuery{From: "Olten", To: "Bern", Date: "2026-03-09", Time: "00:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := departures(opts); len(got) == 0 || got[0] != "2026-03-09T08:32:00+01:00 -> 2026-03-09T09:00:00+01:00" {
+		t.Fatalf("expected Monday's first morning train, got %v", got)
+	}
+}
+
+func TestGTFSSearch_ClockChanges(t *testing.T) {
+	p := sampleGTFS(t)
+	// Clocks go forward on March 29th; stop times still read as wall clock.
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "zurich", To: "Bern", Date: "2026-03-29", Time: "08:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := departures(opts); len(got) == 0 || got[0] != "2026-03-29T08:32:00+02:00 -> 2026-03-29T09:30:00+02:00" {
+		t.Fatalf("expected the 08:32 in summer time, got %v", got)
+	}
+	// And back on October 25th.
+	opts, err = p.SearchTransport(context.Background(), TransportQuery{From: "zurich", To: "Bern", Date: "2026-10-25", Time: "08:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := departures(opts); len(got) == 0 || got[0] != "2026-10-25T08:32:00+01:00 -> 2026-10-25T09:30:00+01:00" {
+		t.Fatalf("expected the 08:32 in winter time, got %v", got)
+	}
+}
+
+func TestGTFSSearch_CalendarExceptions(t *testing.T) {
+	p := sampleGTFS(t)
+	// Christmas runs the weekend timetable, and the weekday bus does not run.
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "zurich", To: "Bern", Date: "2026-12-25", Time: "08:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 1 || opts[0].Departure != "2026-12-25T08:32:00+01:00" {
+		t.Fatalf("expected only the weekend train, got %v", departures(opts))
+	}
+	if _, err := p.SearchTransport(context.Background(), TransportQuery{From: "zurich", To: "Thun", Date: "2026-12-25"}); err == nil {
+		t.Fatal("expected no journeys to Thun on a holiday")
+	}
+	if _, err := p.SearchTransport(context.Background(), TransportQuery{From: "Atlantis", To: "Thun"}); err == nil {
+		t.Fatal("expected an error for an unknown stop")
+	}
+}
+
+func TestNewGTFSProviderFromEnv_LoadsZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	if err := zw.AddFS(os.DirFS("testdata/gtfs")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	t.Setenv("GTFS_FEEDS", path)
+	t.Setenv("GTFS_PROVIDER_NAME", "Timetable")
+	t.Setenv("TRANSPORT_PROVIDERS", "gtfs")
+	r, err := NewRegistryFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, sources := r.Search(context.Background(), TransportQuery{From: "zurich", To: "Thun", Date: "2026-03-06", Time: "08:00"})
+	if len(sources) != 1 || sources[0].Status != StatusOK || len(opts) == 0 {
+		t.Fatalf("unexpected search result %+v", sources)
+	}
+	if opts[0].Provider != "Timetable" || opts[0].Departure != "2026-03-06T08:02:00+01:00" || opts[0].Arrival != "2026-03-06T09:40:00+01:00" {
+		t.Fatalf("unexpected option %+v", opts[0])
+	}
+
+	t.Setenv("GTFS_FEEDS", "")
+	if _, err := NewRegistryFromEnv(); err == nil {
+		t.Fatal("expected an error when the gtfs provider has no feeds")
+	}
+}
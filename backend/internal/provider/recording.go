@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// FixtureMode says whether outbound provider calls are recorded to, or
+// replayed from, fixture files.
+type FixtureMode string
+
+const (
+	FixtureOff    FixtureMode = ""
+	FixtureRecord FixtureMode = "record"
+	FixtureReplay FixtureMode = "replay"
+
+	defaultFixtureDir = "provider-fixtures"
+)
+
+// ErrNoFixture is returned in replay mode for requests nothing was
+// recorded for.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// recordedExchange is what a fixture file holds: the request, for people
+// reading it, and the response that is replayed.
+type recordedExchange struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// RecordingTransport records the responses next gives into dir, one JSON
+// file per request, or serves requests from those files without calling
+// next. Requests are keyed by method, path, query and body but not host, so
+// recordings replay against any base URL.
+type RecordingTransport struct {
+	mode FixtureMode
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewRecordingTransport wraps next (http.DefaultTransport when nil).
+func NewRecordingTransport(mode FixtureMode, dir string, next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordingTransport{mode: mode, dir: dir, next: next}
+}
+
+// FixtureTransportFromEnv is the transport outbound calls for provider name
+// go through: a RecordingTransport in PROVIDER_FIXTURE_MODE ("record" or
+// "replay") keeping fixtures under PROVIDER_FIXTURE_DIR/<name>, or nil, for
+// the network, when the mode is unset.
+func FixtureTransportFromEnv(name string) http.RoundTripper {
+	mode := FixtureMode(strings.ToLower(strings.TrimSpace(os.Getenv("PROVIDER_FIXTURE_MODE"))))
+	switch mode {
+	case FixtureOff:
+		return nil
+	case FixtureRecord, FixtureReplay:
+	default:
+		log.Printf("unknown PROVIDER_FIXTURE_MODE %q; calling providers directly", mode)
+		return nil
+	}
+	dir := strings.TrimSpace(os.Getenv("PROVIDER_FIXTURE_DIR"))
+	if dir == "" {
+		dir = defaultFixtureDir
+	}
+	return NewRecordingTransport(mode, filepath.Join(dir, fixtureName(name)), nil)
+}
+
+// outboundTransport is the transport of provider name's HTTP client.
+func outboundTransport(name string) *ResilientTransport {
+	return NewResilientTransport(name, FixtureTransportFromEnv(name), ResiliencePolicyFromEnv(name))
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	path := filepath.Join(t.dir, FixtureKey(req, body)+".json")
+	if t.mode == FixtureReplay {
+		return t.replay(req, path)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || t.mode != FixtureRecord {
+		return resp, err
+	}
+	recorded, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(recorded))
+	exchange := recordedExchange{
+		Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode,
+		Header: http.Header{"Content-Type": resp.Header.Values("Content-Type")}, Body: string(recorded),
+	}
+	if err := t.save(path, exchange); err != nil {
+		log.Printf("record fixture %s: %v", path, err)
+	}
+	return resp, nil
+}
+
+func (t *RecordingTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s (%s)", ErrNoFixture, req.Method, req.URL, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	var exchange recordedExchange
+	if err := json.Unmarshal(data, &exchange); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+	header := exchange.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+		StatusCode:    exchange.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(exchange.Body)),
+		ContentLength: int64(len(exchange.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *RecordingTransport) save(path string, exchange recordedExchange) error {
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(exchange); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data.Bytes(), 0o644)
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func fixtureName(s string) string {
+	return strings.Trim(unsafeFixtureChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// FixtureKey names the fixture file of a request: its method and path, to
+// be readable, and a hash of the method, path, sorted query and body.
+func FixtureKey(req *http.Request, body []byte) string {
+	sum := sha256.New()
+	// Encode sorts the query by key.
+	fmt.Fprintf(sum, "%s %s?%s\n", req.Method, req.URL.Path, req.URL.Query().Encode())
+	sum.Write(body)
+	name := fixtureName(req.Method + " " + req.URL.Path)
+	return name + "-" + hex.EncodeToString(sum.Sum(nil))[:12]
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRecordingTransport_RecordsThenReplays(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"to":"` + r.URL.Query().Get("to") + `"}`))
+	}))
+	record := &http.Client{Transport: NewRecordingTransport(FixtureRecord, dir, nil)}
+	resp, err := record.Get(srv.URL + "/connections?to=Bern&from=Zurich")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(recorded) != `{"to":"Bern"}` {
+		t.Fatalf("unexpected recorded response %q", recorded)
+	}
+	srv.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected one fixture file, got %v", entries)
+	}
+
+	// Replay needs no server, ignores the host and query order, and fails
+	// for requests that were never recorded.
+	replay := &http.Client{Transport: NewRecordingTransport(FixtureReplay, dir, nil)}
+	r, err := replay.Get("http://provider.invalid/connections?from=Zurich&to=Bern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+	if r.StatusCode != 200 || string(body) != `{"to":"Bern"}` || r.Header.Get("Content-Type") != "application/json" || calls != 1 {
+		t.Fatalf("unexpected replay %d %q %v after %d calls", r.StatusCode, body, r.Header, calls)
+	}
+	if _, err := replay.Get("http://provider.invalid/connections?from=Zurich&to=Basel"); !errors.Is(err, ErrNoFixture) {
+		t.Fatalf("expected ErrNoFixture, got %v", err)
+	}
+}
+
+func TestFixtureTransportFromEnv(t *testing.T) {
+	t.Setenv("PROVIDER_FIXTURE_MODE", "")
+	if FixtureTransportFromEnv("opendata") != nil {
+		t.Fatal("expected no fixture transport by default")
+	}
+	t.Setenv("PROVIDER_FIXTURE_MODE", "replay")
+	t.Setenv("PROVIDER_FIXTURE_DIR", "fixtures")
+	rt, ok := FixtureTransportFromEnv("Flight API").(*RecordingTransport)
+	if !ok || rt.mode != FixtureReplay || rt.dir != "fixtures/flight-api" {
+		t.Fatalf("unexpected transport %+v", rt)
+	}
+}
@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	defaultSearchDeadline = 3 * time.Second
+	// hourValue is what an hour of travel is worth when ranking options, so
+	// a slightly dearer train can beat a much slower bus.
+	hourValue = money.Amount(8 * money.Scale)
+)
+
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusTimeout Status = "timeout"
+	StatusError   Status = "error"
+	// StatusSynthetic marks the seed-data fallback, which answers when no
+	// provider does with options that are estimates, not offers.
+	StatusSynthetic Status = "synthetic"
+)
+
+// SeedSource names the seed-data fallback in a search's sources.
+const SeedSource = "seed-data"
+
+// SourceStatus reports how one registered provider fared in a search.
+type SourceStatus struct {
+	Provider   string `json:"provider"`
+	Status     Status `json:"status"`
+	Options    int    `json:"options"`
+	DurationMs int64  `json:"durationMs"`
+	Cached     bool   `json:"cached,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// transportFactories builds the providers TRANSPORT_PROVIDERS can name.
+var transportFactories = map[string]func() (TransportProvider, error){
+	"opendata": func() (TransportProvider, error) {
+		p := NewOpenTransportProviderFromEnv()
+		p.enabled = true
+		return p, nil
+	},
+	"gtfs": func() (TransportProvider, error) {
+		return NewGTFSProviderFromEnv()
+	},
+	"flights": func() (TransportProvider, error) {
+		return NewFlightProviderFromEnv()
+	},
+}
+
+type registered struct {
+	name     string
+	provider TransportProvider
+	// source is what provider wraps when it is a cache in front of it.
+	source TransportProvider
+}
+
+// Registry fans a transport search out to every registered provider at once
+// and merges what comes back before a shared deadline.
+type Registry struct {
+	deadline    time.Duration
+	providers   []registered
+	cache       SearchCache
+	cacheConfig CacheConfig
+}
+
+func NewRegistry(deadline time.Duration) *Registry {
+	if deadline <= 0 {
+		deadline = defaultSearchDeadline
+	}
+	return &Registry{deadline: deadline}
+}
+
+// NewRegistryFromEnv registers the providers listed in TRANSPORT_PROVIDERS
+// (comma-separated) under a TRANSPORT_SEARCH_DEADLINE_MS deadline. Without
+// the list, the OpenTransportData provider is registered when
+// REAL_PROVIDER_ENABLED=true. Unknown names and providers that fail to
+// start are left out and reported in the error; the registry is usable
+// either way.
+func NewRegistryFromEnv() (*Registry, error) {
+	deadline := defaultSearchDeadline
+	if raw := strings.TrimSpace(os.Getenv("TRANSPORT_SEARCH_DEADLINE_MS")); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			deadline = time.Duration(ms) * time.Millisecond
+		}
+	}
+	r := NewRegistry(deadline)
+
+	names, ok := os.LookupEnv("TRANSPORT_PROVIDERS")
+	if !ok {
+		if open := NewOpenTransportProviderFromEnv(); open.enabled {
+			r.Register("opendata", open)
+		}
+		return r, nil
+	}
+	var unknown []string
+	var failed []error
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		factory, ok := transportFactories[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		p, err := factory()
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		r.Register(name, p)
+	}
+	if len(unknown) > 0 {
+		failed = append(failed, fmt.Errorf("unknown transport providers: %s", strings.Join(unknown, ", ")))
+	}
+	return r, errors.Join(failed...)
+}
+
+// Register adds p under name, replacing any provider already registered
+// under it.
+func (r *Registry) Register(name string, p TransportProvider) {
+	entry := r.wrap(registered{name: name, source: p})
+	for i := range r.providers {
+		if r.providers[i].name == name {
+			r.providers[i] = entry
+			return
+		}
+	}
+	r.providers = append(r.providers, entry)
+}
+
+// EnableCache puts cache in front of every provider, registered now or
+// later, with the TTLs config gives them.
+func (r *Registry) EnableCache(cache SearchCache, config CacheConfig) {
+	r.cache, r.cacheConfig = cache, config
+	for i := range r.providers {
+		r.providers[i] = r.wrap(r.providers[i])
+	}
+}
+
+func (r *Registry) wrap(entry registered) registered {
+	entry.provider = entry.source
+	if ttl := r.cacheConfig.ttl(entry.name); r.cache != nil && ttl > 0 {
+		entry.provider = NewCachedProvider(entry.name, entry.source, r.cache, ttl, r.cacheConfig.Stale)
+	}
+	return entry
+}
+
+// Partial reports whether any provider in sources failed to answer, so the
+// options may be missing some that would otherwise have been offered.
+func Partial(sources []SourceStatus) bool {
+	for _, source := range sources {
+		if source.Status == StatusTimeout || source.Status == StatusError {
+			return true
+		}
+	}
+	return false
+}
+
+// Names lists the registered providers in registration order.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.providers))
+	for i, p := range r.providers {
+		names[i] = p.name
+	}
+	return names
+}
+
+// Search queries every provider concurrently and returns the merged options,
+// best first, along with one status per provider in registration order.
+// Answers served from the cache are marked as such.
+// Providers still running at the deadline are reported as timed out and
+// their late results are dropped.
+func (r *Registry) Search(ctx context.Context, q TransportQuery) ([]domain.TransportOption, []SourceStatus) {
+	ctx, cancel := context.WithTimeout(ctx, r.deadline)
+	defer cancel()
+
+	type result struct {
+		index   int
+		options []domain.TransportOption
+		cached  bool
+		err     error
+		elapsed time.Duration
+	}
+	results := make(chan result, len(r.providers))
+	start := time.Now()
+	for i, p := range r.providers {
+		go func() {
+			var res result
+			if cached, ok := p.provider.(*CachedProvider); ok {
+				res.options, res.cached, res.err = cached.search(ctx, q)
+			} else {
+				res.options, res.err = p.provider.SearchTransport(ctx, q)
+			}
+			res.index, res.elapsed = i, time.Since(start)
+			results <- res
+		}()
+	}
+
+	sources := make([]SourceStatus, len(r.providers))
+	for i, p := range r.providers {
+		sources[i] = SourceStatus{Provider: p.name, Status: StatusTimeout}
+	}
+	var collected [][]domain.TransportOption
+	for pending := len(r.providers); pending > 0; pending-- {
+		select {
+		case res := <-results:
+			source := &sources[res.index]
+			source.DurationMs = res.elapsed.Milliseconds()
+			switch {
+			case res.err == nil:
+				source.Status, source.Options, source.Cached = StatusOK, len(res.options), res.cached
+				collected = append(collected, res.options)
+			case errors.Is(res.err, context.DeadlineExceeded):
+				source.Error = res.err.Error()
+			default:
+				source.Status, source.Error = StatusError, res.err.Error()
+			}
+		case <-ctx.Done():
+			for i := range sources {
+				if sources[i].Status == StatusTimeout && sources[i].Error == "" {
+					sources[i].DurationMs = r.deadline.Milliseconds()
+					sources[i].Error = "no response before the search deadline"
+				}
+			}
+			return Merge(collected...), sources
+		}
+	}
+	return Merge(collected...), sources
+}
+
+// SearchTransport lets a Registry stand in for a single provider. It fails
+// only when no provider returned an option.
+func (r *Registry) SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error) {
+	options, sources := r.Search(ctx, q)
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no transport options from %d providers", len(sources))
+	}
+	return options, nil
+}
+
+// Merge combines option lists, keeps the cheapest of options that describe
+// the same journey (same mode and departure and, to the quarter hour, the
+// same duration) and ranks the rest by price with baggage plus the value of
+// the time spent travelling.
+func Merge(lists ...[]domain.TransportOption) []domain.TransportOption {
+	best := map[string]domain.TransportOption{}
+	var keys []string
+	for _, list := range lists {
+		for _, option := range list {
+			key := dedupeKey(option)
+			existing, seen := best[key]
+			if !seen {
+				keys = append(keys, key)
+			}
+			if !seen || option.TotalCost() < existing.TotalCost() {
+				best[key] = option
+			}
+		}
+	}
+	merged := make([]domain.TransportOption, 0, len(keys))
+	for _, key := range keys {
+		merged = append(merged, best[key])
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return rankCost(merged[i]) < rankCost(merged[j])
+	})
+	return merged
+}
+
+func dedupeKey(option domain.TransportOption) string {
+	quarters := int(math.Round(option.DurationHours * 4))
+	departure := option.Departure
+	if t, err := time.Parse(time.RFC3339, departure); err == nil {
+		departure = t.UTC().Format(time.RFC3339)
+	}
+	return strings.ToLower(option.Mode) + "/" + departure + "/" + strconv.Itoa(quarters)
+}
+
+func rankCost(option domain.TransportOption) money.Amount {
+	return option.TotalCost() + hourValue.Mul(option.DurationHours)
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+type providerFunc func(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error)
+
+func (f providerFunc) SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error) {
+	return f(ctx, q)
+}
+
+func fixed(options ...domain.TransportOption) providerFunc {
+	return func(context.Context, TransportQuery) ([]domain.TransportOption, error) { return options, nil }
+}
+
+func TestRegistrySearch_MergesAndReportsStatus(t *testing.T) {
+	r := NewRegistry(50 * time.Millisecond)
+	r.Register("rail", fixed(
+		domain.TransportOption{Provider: "rail", Mode: "train", DurationHours: 4, Price: money.FromFloat(40)},
+		domain.TransportOption{Provider: "rail", Mode: "bus", DurationHours: 7, Price: money.FromFloat(20)},
+	))
+	r.Register("cheap", fixed(
+		domain.TransportOption{Provider: "cheap", Mode: "TRAIN", DurationHours: 4.1, Price: money.FromFloat(35)},
+	))
+	r.Register("broken", providerFunc(func(context.Context, TransportQuery) ([]domain.TransportOption, error) {
+		return nil, errors.New("boom")
+	}))
+	// Ignores its context entirely; the registry must not wait for it.
+	r.Register("stuck", providerFunc(func(context.Context, TransportQuery) ([]domain.TransportOption, error) {
+		time.Sleep(300 * time.Millisecond)
+		return nil, nil
+	}))
+	r.Register("slow", providerFunc(func(ctx context.Context, _ TransportQuery) ([]domain.TransportOption, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+
+	start := time.Now()
+	options, sources := r.Search(context.Background(), TransportQuery{From: "Berlin", To: "Prague"})
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("search waited %v past its deadline", elapsed)
+	}
+
+	// The two 4h trains are one journey; the cheaper one wins. Train 35 +
+	// 4.1h*8 = 67.80 ranks ahead of bus 20 + 7h*8 = 76.
+	if len(options) != 2 || options[0].Provider != "cheap" || options[1].Mode != "bus" {
+		t.Fatalf("unexpected merged options %+v", options)
+	}
+	want := map[string]Status{"rail": StatusOK, "cheap": StatusOK, "broken": StatusError, "stuck": StatusTimeout, "slow": StatusTimeout}
+	if len(sources) != len(want) {
+		t.Fatalf("expected %d sources, got %+v", len(want), sources)
+	}
+	for i, name := range r.Names() {
+		if sources[i].Provider != name || sources[i].Status != want[name] {
+			t.Fatalf("unexpected status for %s: %+v", name, sources[i])
+		}
+	}
+	if sources[0].Options != 2 || sources[2].Error != "boom" || sources[3].Error == "" {
+		t.Fatalf("unexpected source details %+v", sources)
+	}
+}
+
+func TestRegistrySearch_NoProviders(t *testing.T) {
+	r := NewRegistry(0)
+	options, sources := r.Search(context.Background(), TransportQuery{From: "Berlin", To: "Prague"})
+	if len(options) != 0 || len(sources) != 0 {
+		t.Fatalf("expected nothing, got %+v %+v", options, sources)
+	}
+	if _, err := r.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Prague"}); err == nil {
+		t.Fatal("expected an error without options")
+	}
+}
+
+func TestNewRegistryFromEnv(t *testing.T) {
+	t.Setenv("TRANSPORT_PROVIDERS", "opendata, nope")
+	r, err := NewRegistryFromEnv()
+	if err == nil || len(r.Names()) != 1 || r.Names()[0] != "opendata" {
+		t.Fatalf("expected opendata plus an unknown-provider error, got %v %v", r.Names(), err)
+	}
+	t.Setenv("TRANSPORT_PROVIDERS", "")
+	if r, err := NewRegistryFromEnv(); err != nil || len(r.Names()) != 0 {
+		t.Fatalf("expected an empty registry, got %v %v", r.Names(), err)
+	}
+}
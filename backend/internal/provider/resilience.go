@@ -0,0 +1,389 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling a provider that has been
+// failing, until its cooldown has passed.
+var ErrCircuitOpen = errors.New("provider circuit open")
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ResiliencePolicy sets how hard an outbound provider is tried and how it
+// is protected from us.
+type ResiliencePolicy struct {
+	// MaxRetries is how many times a 5xx, 429 or timed-out request is
+	// repeated, waiting a random share of an exponential backoff between
+	// BaseBackoff and MaxBackoff.
+	MaxRetries     int
+	BaseBackoff    time.Duration
+	MaxBackoff     time.Duration
+	AttemptTimeout time.Duration
+	// BreakerFailures consecutive failed requests open the circuit for
+	// BreakerCooldown, after which a single probe may close it again.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	// RateLimit caps requests per second (zero for none), allowing bursts
+	// of up to RateBurst.
+	RateLimit float64
+	RateBurst int
+}
+
+func DefaultResiliencePolicy() ResiliencePolicy {
+	return ResiliencePolicy{
+		MaxRetries:      2,
+		BaseBackoff:     100 * time.Millisecond,
+		MaxBackoff:      time.Second,
+		AttemptTimeout:  time.Second,
+		BreakerFailures: 5,
+		BreakerCooldown: 30 * time.Second,
+		RateBurst:       1,
+	}
+}
+
+// ResiliencePolicyFromEnv starts from the defaults and applies
+// PROVIDER_MAX_RETRIES, PROVIDER_ATTEMPT_TIMEOUT, PROVIDER_BREAKER_FAILURES,
+// PROVIDER_BREAKER_COOLDOWN, PROVIDER_RATE_LIMIT and PROVIDER_RATE_BURST.
+// Each can be set for one provider by suffixing its name, as in
+// PROVIDER_RATE_LIMIT_OPENDATA. Values that do not parse are ignored.
+func ResiliencePolicyFromEnv(name string) ResiliencePolicy {
+	p := DefaultResiliencePolicy()
+	lookup := func(key string) string {
+		if v := strings.TrimSpace(os.Getenv(key + "_" + strings.ToUpper(name))); v != "" {
+			return v
+		}
+		return strings.TrimSpace(os.Getenv(key))
+	}
+	if n, err := strconv.Atoi(lookup("PROVIDER_MAX_RETRIES")); err == nil && n >= 0 {
+		p.MaxRetries = n
+	}
+	if d, err := time.ParseDuration(lookup("PROVIDER_ATTEMPT_TIMEOUT")); err == nil && d > 0 {
+		p.AttemptTimeout = d
+	}
+	if n, err := strconv.Atoi(lookup("PROVIDER_BREAKER_FAILURES")); err == nil && n > 0 {
+		p.BreakerFailures = n
+	}
+	if d, err := time.ParseDuration(lookup("PROVIDER_BREAKER_COOLDOWN")); err == nil && d > 0 {
+		p.BreakerCooldown = d
+	}
+	if f, err := strconv.ParseFloat(lookup("PROVIDER_RATE_LIMIT"), 64); err == nil && f >= 0 {
+		p.RateLimit = f
+	}
+	if n, err := strconv.Atoi(lookup("PROVIDER_RATE_BURST")); err == nil && n > 0 {
+		p.RateBurst = n
+	}
+	return p
+}
+
+// ProviderHealth is what a resilient transport has seen of its provider.
+type ProviderHealth struct {
+	Provider     string       `json:"provider"`
+	Breaker      BreakerState `json:"breaker"`
+	Requests     int64        `json:"requests"`
+	Retries      int64        `json:"retries"`
+	Failures     int64        `json:"failures"`
+	Rejected     int64        `json:"rejected"`
+	Throttled    int64        `json:"throttled"`
+	LastError    string       `json:"lastError,omitempty"`
+	LastFailedAt string       `json:"lastFailedAt,omitempty"`
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[string]*ResilientTransport{}
+)
+
+// Health reports every provider behind a ResilientTransport, by name.
+func Health() []ProviderHealth {
+	transportsMu.Lock()
+	list := make([]*ResilientTransport, 0, len(transports))
+	for _, t := range transports {
+		list = append(list, t)
+	}
+	transportsMu.Unlock()
+	health := make([]ProviderHealth, len(list))
+	for i, t := range list {
+		health[i] = t.Health()
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Provider < health[j].Provider })
+	return health
+}
+
+// ResilientTransport is an http.RoundTripper for calling one provider. It
+// retries transient failures, stops calling a provider that keeps failing
+// and spaces requests out to stay within the provider's quota.
+type ResilientTransport struct {
+	name   string
+	next   http.RoundTripper
+	policy ResiliencePolicy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openUntil time.Time
+	probing   bool
+	lastErr   string
+	lastFail  time.Time
+	tokens    float64
+	refilled  time.Time
+
+	requests, retries, failed, rejected, throttled atomic.Int64
+}
+
+// NewResilientTransport wraps next (http.DefaultTransport when nil) and
+// makes the result visible through Health under name.
+func NewResilientTransport(name string, next http.RoundTripper, policy ResiliencePolicy) *ResilientTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &ResilientTransport{
+		name: name, next: next, policy: policy, now: time.Now,
+		state: BreakerClosed, tokens: float64(max(policy.RateBurst, 1)),
+	}
+	transportsMu.Lock()
+	transports[name] = t
+	transportsMu.Unlock()
+	return t
+}
+
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	t.requests.Add(1)
+	if !t.allow() {
+		t.rejected.Add(1)
+		return nil, fmt.Errorf("%s: %w", t.name, ErrCircuitOpen)
+	}
+	for attempt := 0; ; attempt++ {
+		if err := t.throttle(ctx); err != nil {
+			t.release()
+			return nil, err
+		}
+		resp, err := t.attempt(req)
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the provider.
+			t.release()
+			return resp, err
+		}
+		retryable, reason := classify(resp, err)
+		if !retryable && err == nil {
+			t.record(nil)
+			return resp, nil
+		}
+		if !retryable || attempt >= t.policy.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			t.record(errors.New(reason))
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		t.retries.Add(1)
+		if err := sleepCtx(ctx, t.backoff(attempt)); err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+}
+
+// attempt sends one copy of req under the per-attempt timeout. The timeout
+// stays in force until the caller closes the response body.
+func (t *ResilientTransport) attempt(req *http.Request) (*http.Response, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if t.policy.AttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), t.policy.AttemptTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+	out := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		out.Body = body
+	}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// classify says whether a failed attempt is worth repeating and describes
+// it. Client errors other than 429 are the request's fault, not the
+// provider's, and are neither retried nor counted against it.
+func classify(resp *http.Response, err error) (bool, string) {
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return true, "timeout: " + err.Error()
+		}
+		return false, err.Error()
+	}
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return true, "status " + strconv.Itoa(resp.StatusCode)
+	}
+	return false, ""
+}
+
+func (t *ResilientTransport) backoff(attempt int) time.Duration {
+	ceiling := t.policy.BaseBackoff << attempt
+	if ceiling <= 0 || ceiling > t.policy.MaxBackoff {
+		ceiling = t.policy.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// allow reports whether the breaker lets a request through. Once the
+// cooldown has passed a single probe is let through; its outcome closes or
+// reopens the circuit.
+func (t *ResilientTransport) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch t.state {
+	case BreakerOpen:
+		if t.now().Before(t.openUntil) {
+			return false
+		}
+		t.state, t.probing = BreakerHalfOpen, true
+		return true
+	case BreakerHalfOpen:
+		if t.probing {
+			return false
+		}
+		t.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// release hands back a probe whose outcome was not decided by the provider.
+func (t *ResilientTransport) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+}
+
+func (t *ResilientTransport) record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+	if err == nil {
+		if t.state != BreakerClosed {
+			log.Printf("provider %s recovered, circuit closed", t.name)
+		}
+		t.state, t.failures = BreakerClosed, 0
+		return
+	}
+	t.failed.Add(1)
+	t.failures++
+	t.lastErr, t.lastFail = err.Error(), t.now()
+	log.Printf("provider %s degraded: %v (%d consecutive failures)", t.name, err, t.failures)
+	if t.state == BreakerHalfOpen || t.failures >= t.policy.BreakerFailures {
+		if t.state != BreakerOpen {
+			log.Printf("provider %s circuit open for %v", t.name, t.policy.BreakerCooldown)
+		}
+		t.state, t.openUntil = BreakerOpen, t.now().Add(t.policy.BreakerCooldown)
+	}
+}
+
+// throttle takes a token from the rate limiter, waiting for one to be
+// refilled when the bucket is empty. A caller that gives up while waiting
+// hands its token back, so later callers are not held up for it.
+func (t *ResilientTransport) throttle(ctx context.Context) error {
+	if t.policy.RateLimit <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	now := t.now()
+	burst := float64(max(t.policy.RateBurst, 1))
+	if !t.refilled.IsZero() {
+		t.tokens = min(burst, t.tokens+now.Sub(t.refilled).Seconds()*t.policy.RateLimit)
+	}
+	t.refilled = now
+	t.tokens--
+	wait := time.Duration(-t.tokens / t.policy.RateLimit * float64(time.Second))
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	t.throttled.Add(1)
+	if err := sleepCtx(ctx, wait); err != nil {
+		t.mu.Lock()
+		t.tokens = min(burst, t.tokens+1)
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (t *ResilientTransport) Health() ProviderHealth {
+	t.mu.Lock()
+	state := t.state
+	if state == BreakerOpen && !t.now().Before(t.openUntil) {
+		state = BreakerHalfOpen
+	}
+	h := ProviderHealth{Provider: t.name, Breaker: state, LastError: t.lastErr}
+	if !t.lastFail.IsZero() {
+		h.LastFailedAt = t.lastFail.UTC().Format(time.RFC3339)
+	}
+	t.mu.Unlock()
+	h.Requests, h.Retries, h.Failures = t.requests.Load(), t.retries.Load(), t.failed.Load()
+	h.Rejected, h.Throttled = t.rejected.Load(), t.throttled.Load()
+	return h
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
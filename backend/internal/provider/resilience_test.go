@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPolicy() ResiliencePolicy {
+	p := DefaultResiliencePolicy()
+	p.BaseBackoff, p.MaxBackoff = time.Millisecond, 5*time.Millisecond
+	p.AttemptTimeout = 100 * time.Millisecond
+	return p
+}
+
+// flakyServer fails the first `failures` requests with status.
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"connections":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestResilientTransport_RetriesServerErrors(t *testing.T) {
+	server, hits := flakyServer(t, 2, http.StatusServiceUnavailable)
+	rt := NewResilientTransport("retry-test", nil, testPolicy())
+	client := &http.Client{Transport: rt}
+
+	resp, err := get(t, client, server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected success after retries, got %v, %v", resp, err)
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", hits.Load())
+	}
+	h := rt.Health()
+	if h.Requests != 1 || h.Retries != 2 || h.Failures != 0 || h.Breaker != BreakerClosed {
+		t.Fatalf("unexpected health %+v", h)
+	}
+}
+
+func TestResilientTransport_DoesNotRetryClientErrors(t *testing.T) {
+	server, hits := flakyServer(t, 5, http.StatusBadRequest)
+	rt := NewResilientTransport("client-error-test", nil, testPolicy())
+
+	resp, err := get(t, &http.Client{Transport: rt}, server.URL)
+	if err != nil || resp.StatusCode != http.StatusBadRequest || hits.Load() != 1 {
+		t.Fatalf("expected one 400 attempt, got %v, %v after %d hits", resp, err, hits.Load())
+	}
+	if h := rt.Health(); h.Failures != 0 {
+		t.Fatalf("a 400 must not count against the provider, got %+v", h)
+	}
+}
+
+func TestResilientTransport_RetriesAttemptTimeouts(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	rt := NewResilientTransport("timeout-test", nil, testPolicy())
+
+	resp, err := get(t, &http.Client{Transport: rt}, server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK || hits.Load() != 2 {
+		t.Fatalf("expected the hung attempt to be retried, got %v, %v after %d hits", resp, err, hits.Load())
+	}
+}
+
+func TestResilientTransport_CircuitBreaker(t *testing.T) {
+	server, hits := flakyServer(t, 1000, http.StatusBadGateway)
+	policy := testPolicy()
+	policy.MaxRetries, policy.BreakerFailures, policy.BreakerCooldown = 0, 2, time.Minute
+	rt := NewResilientTransport("breaker-test", nil, policy)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rt.now = func() time.Time { return now }
+	client := &http.Client{Transport: rt}
+
+	get(t, client, server.URL)
+	get(t, client, server.URL)
+	if _, err := get(t, client, server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	if hits.Load() != 2 || rt.Health().Rejected != 1 || rt.Health().Breaker != BreakerOpen {
+		t.Fatalf("expected the open circuit to spare the server, got %d hits and %+v", hits.Load(), rt.Health())
+	}
+
+	// After the cooldown one probe goes through; it fails and reopens.
+	now = now.Add(2 * time.Minute)
+	get(t, client, server.URL)
+	if hits.Load() != 3 || rt.Health().Breaker != BreakerOpen {
+		t.Fatalf("expected a failed probe to reopen the circuit, got %d hits and %+v", hits.Load(), rt.Health())
+	}
+
+	// A successful probe closes it.
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.Write([]byte("ok")) })
+	now = now.Add(2 * time.Minute)
+	if resp, err := get(t, client, server.URL); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the probe to succeed, got %v, %v", resp, err)
+	}
+	if rt.Health().Breaker != BreakerClosed {
+		t.Fatalf("expected the circuit to close, got %+v", rt.Health())
+	}
+}
+
+func TestResilientTransport_RateLimits(t *testing.T) {
+	server, hits := flakyServer(t, 0, 0)
+	policy := testPolicy()
+	policy.RateLimit, policy.RateBurst = 20, 1
+	rt := NewResilientTransport("rate-test", nil, policy)
+	client := &http.Client{Transport: rt}
+
+	start := time.Now()
+	for range 3 {
+		if _, err := get(t, client, server.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One request from the burst, then one every 50ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected requests to be spaced out, took %v", elapsed)
+	}
+	if hits.Load() != 3 || rt.Health().Throttled != 2 {
+		t.Fatalf("expected 2 throttled requests, got %+v", rt.Health())
+	}
+}
+
+func TestResilientTransport_CancelledWaitsReturnTheirToken(t *testing.T) {
+	policy := testPolicy()
+	policy.RateLimit, policy.RateBurst = 1, 1
+	rt := NewResilientTransport("refund-test", nil, policy)
+	if err := rt.throttle(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 3 {
+		if err := rt.throttle(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the caller's cancellation, got %v", err)
+		}
+	}
+	// Without the refunds the bucket would be three tokens in debt.
+	rt.mu.Lock()
+	tokens := rt.tokens
+	rt.mu.Unlock()
+	if tokens < -0.1 {
+		t.Fatalf("expected cancelled callers to hand their tokens back, bucket at %.2f", tokens)
+	}
+}
+
+func TestResilientTransport_CallerCancellationIsNotAFailure(t *testing.T) {
+	server, _ := flakyServer(t, 1000, http.StatusServiceUnavailable)
+	policy := testPolicy()
+	policy.BaseBackoff, policy.MaxBackoff = time.Second, time.Second
+	rt := NewResilientTransport("cancel-test", nil, policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := (&http.Client{Transport: rt}).Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the caller's deadline, got %v", err)
+	}
+	if h := rt.Health(); h.Failures != 0 {
+		t.Fatalf("expected no failure recorded, got %+v", h)
+	}
+}
+
+func TestHealth_ListsTransports(t *testing.T) {
+	NewResilientTransport("health-test", nil, testPolicy())
+	for _, h := range Health() {
+		if h.Provider == "health-test" {
+			return
+		}
+	}
+	t.Fatal("expected health-test in Health()")
+}
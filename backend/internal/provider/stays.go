@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+const (
+	defaultStayNights    = 2
+	maxStayNights        = 60
+	defaultRoomCapacity  = 2
+	defaultStayProvider  = "HostelAPI"
+	defaultStayTimeout   = 2500 * time.Millisecond
+	fixtureStayProvider  = "LocalStays"
+	stayProviderCurrency = "EUR"
+	stayDateLayout       = "2006-01-02"
+)
+
+//go:embed fixtures/stays.json
+var defaultStayFixture []byte
+
+// StayQuery asks for accommodation in City for Guests people between
+// CheckIn and CheckOut (YYYY-MM-DD). Empty dates mean an unspecified
+// weekend of two nights.
+type StayQuery struct {
+	City     string
+	CheckIn  string
+	CheckOut string
+	Guests   int
+}
+
+// Nights is the length of the stay, two when the dates are missing or do
+// not make sense.
+func (q StayQuery) Nights() int {
+	in, err1 := time.Parse(stayDateLayout, q.CheckIn)
+	out, err2 := time.Parse(stayDateLayout, q.CheckOut)
+	if err1 != nil || err2 != nil || !out.After(in) {
+		return defaultStayNights
+	}
+	return min(int(out.Sub(in).Hours()/24), maxStayNights)
+}
+
+func (q StayQuery) guests() int { return max(q.Guests, 1) }
+
+type AccommodationProvider interface {
+	SearchStays(ctx context.Context, q StayQuery) ([]domain.StayOption, error)
+}
+
+// NewAccommodationProviderFromEnv builds the provider STAY_PROVIDER names:
+// "http" for a JSON hostel API at STAY_PROVIDER_BASE_URL or "fixture" for
+// the local fixture in STAY_FIXTURE_FILE (the bundled one when unset). It
+// returns nil when STAY_PROVIDER is empty, leaving stays to the store.
+func NewAccommodationProviderFromEnv() (AccommodationProvider, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("STAY_PROVIDER"))); kind {
+	case "":
+		return nil, nil
+	case "http":
+		baseURL := strings.TrimRight(strings.TrimSpace(os.Getenv("STAY_PROVIDER_BASE_URL")), "/")
+		if baseURL == "" {
+			return nil, fmt.Errorf("STAY_PROVIDER=http needs STAY_PROVIDER_BASE_URL")
+		}
+		timeout := defaultStayTimeout
+		if raw := strings.TrimSpace(os.Getenv("STAY_PROVIDER_TIMEOUT_MS")); raw != "" {
+			if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+				timeout = time.Duration(ms) * time.Millisecond
+			}
+		}
+		name := strings.TrimSpace(os.Getenv("STAY_PROVIDER_NAME"))
+		if name == "" {
+			name = defaultStayProvider
+		}
+		client := &http.Client{
+			Timeout:   timeout,
+			Transport: outboundTransport(name),
+		}
+		return NewHTTPStayProvider(name, baseURL, client), nil
+	case "fixture":
+		data := defaultStayFixture
+		if path := strings.TrimSpace(os.Getenv("STAY_FIXTURE_FILE")); path != "" {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read stay fixture: %w", err)
+			}
+			data = raw
+		}
+		p, err := NewFixtureStayProvider(data)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown STAY_PROVIDER %q", kind)
+	}
+}
+
+// HTTPStayProvider queries a JSON hostel API:
+//
+//	GET {baseURL}/stays?city=&checkIn=&checkOut=&guests=
+//	{"stays": [{"name", "type", "nightlyPrice", "totalPrice", "currency", "rating", "url"}]}
+//
+// totalPrice is optional and derived from the nightly per-guest price when
+// missing. Only EUR offers are kept, since optimizer costs are in EUR.
+type HTTPStayProvider struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPStayProvider(name, baseURL string, client *http.Client) *HTTPStayProvider {
+	return &HTTPStayProvider{name: name, baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+func (p *HTTPStayProvider) SearchStays(ctx context.Context, q StayQuery) ([]domain.StayOption, error) {
+	u, err := url.Parse(p.baseURL + "/stays")
+	if err != nil {
+		return nil, fmt.Errorf("parse stay provider url: %w", err)
+	}
+	params := u.Query()
+	params.Set("city", q.City)
+	params.Set("guests", strconv.Itoa(q.guests()))
+	if q.CheckIn != "" && q.CheckOut != "" {
+		params.Set("checkIn", q.CheckIn)
+		params.Set("checkOut", q.CheckOut)
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create stay provider request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stay provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("stay provider returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Stays []struct {
+			Name         string        `json:"name"`
+			Type         string        `json:"type"`
+			NightlyPrice money.Amount  `json:"nightlyPrice"`
+			TotalPrice   *money.Amount `json:"totalPrice"`
+			Currency     string        `json:"currency"`
+			Rating       float64       `json:"rating"`
+			URL          string        `json:"url"`
+		} `json:"stays"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode stay provider response: %w", err)
+	}
+
+	options := make([]domain.StayOption, 0, len(payload.Stays))
+	for _, s := range payload.Stays {
+		if s.NightlyPrice <= 0 || (s.Currency != "" && !strings.EqualFold(s.Currency, stayProviderCurrency)) {
+			continue
+		}
+		total := s.NightlyPrice.Mul(float64(q.Nights() * q.guests()))
+		if s.TotalPrice != nil && *s.TotalPrice > 0 {
+			total = *s.TotalPrice
+		}
+		kind := strings.ToLower(strings.TrimSpace(s.Type))
+		if kind == "" {
+			kind = "hostel"
+		}
+		options = append(options, domain.StayOption{
+			Provider:     p.name,
+			Name:         s.Name,
+			Kind:         kind,
+			NightlyPrice: s.NightlyPrice,
+			TotalPrice:   total,
+			Rating:       s.Rating,
+			Deeplink:     s.URL,
+		})
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("stay provider returned no usable stays")
+	}
+	sortStays(options)
+	return options, nil
+}
+
+type fixtureStay struct {
+	Name         string       `json:"name"`
+	Kind         string       `json:"kind"`
+	NightlyPrice money.Amount `json:"nightlyPrice"`
+	// Per is "guest" (a bed, the default) or "room", which sleeps Capacity.
+	Per      string  `json:"per"`
+	Capacity int     `json:"capacity"`
+	Rating   float64 `json:"rating"`
+	Deeplink string  `json:"deeplink"`
+}
+
+// FixtureStayProvider serves stays from a local JSON fixture keyed by city,
+// pricing each for the query's guests and nights. It needs no network and
+// is what development and tests run against.
+type FixtureStayProvider struct {
+	stays map[string][]fixtureStay
+}
+
+func NewFixtureStayProvider(data []byte) (*FixtureStayProvider, error) {
+	var raw map[string][]fixtureStay
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode stay fixture: %w", err)
+	}
+	stays := make(map[string][]fixtureStay, len(raw))
+	for city, list := range raw {
+		stays[strings.ToLower(strings.TrimSpace(city))] = list
+	}
+	return &FixtureStayProvider{stays: stays}, nil
+}
+
+func (p *FixtureStayProvider) SearchStays(ctx context.Context, q StayQuery) ([]domain.StayOption, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	list, ok := p.stays[strings.ToLower(strings.TrimSpace(q.City))]
+	if !ok {
+		return nil, fmt.Errorf("no fixture stays for %s", q.City)
+	}
+	nights, guests := q.Nights(), q.guests()
+	options := make([]domain.StayOption, 0, len(list))
+	for _, s := range list {
+		units := guests
+		if s.Per == "room" {
+			capacity := s.Capacity
+			if capacity <= 0 {
+				capacity = defaultRoomCapacity
+			}
+			units = (guests + capacity - 1) / capacity
+		}
+		options = append(options, domain.StayOption{
+			Provider:     fixtureStayProvider,
+			Name:         s.Name,
+			Kind:         s.Kind,
+			NightlyPrice: s.NightlyPrice,
+			TotalPrice:   s.NightlyPrice.Mul(float64(units * nights)),
+			Rating:       s.Rating,
+			Deeplink:     s.Deeplink,
+		})
+	}
+	sortStays(options)
+	return options, nil
+}
+
+// sortStays puts the cheapest stay for the whole party first.
+func sortStays(options []domain.StayOption) {
+	sort.SliceStable(options, func(i, j int) bool { return options[i].TotalPrice < options[j].TotalPrice })
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func TestFixtureStayProvider_PricesPartyAndNights(t *testing.T) {
+	p, err := NewFixtureStayProvider(defaultStayFixture)
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	stays, err := p.SearchStays(context.Background(), StayQuery{City: "prague", CheckIn: "2026-04-03", CheckOut: "2026-04-06", Guests: 3})
+	if err != nil || len(stays) != 2 {
+		t.Fatalf("expected two Prague stays, got %+v, %v", stays, err)
+	}
+	// Beds: 26 x 3 guests x 3 nights = 234. Rooms for two: 68 x 2 rooms x 3 nights = 408.
+	if stays[0].Kind != "hostel" || stays[0].TotalPrice != money.FromFloat(234) || stays[1].TotalPrice != money.FromFloat(408) {
+		t.Fatalf("unexpected pricing %+v", stays)
+	}
+	if stays[0].Provider != "LocalStays" || stays[0].Name != "Old Town Bunks" {
+		t.Fatalf("unexpected stay %+v", stays[0])
+	}
+	if _, err := p.SearchStays(context.Background(), StayQuery{City: "Atlantis"}); err == nil {
+		t.Fatal("expected an error for an unknown city")
+	}
+}
+
+func TestStayQueryNights(t *testing.T) {
+	cases := map[StayQuery]int{
+		{}: 2,
+		{CheckIn: "2026-03-06", CheckOut: "2026-03-08"}: 2,
+		{CheckIn: "2026-03-06", CheckOut: "2026-03-11"}: 5,
+		{CheckIn: "2026-03-08", CheckOut: "2026-03-06"}: 2,
+	}
+	for q, want := range cases {
+		if got := q.Nights(); got != want {
+			t.Fatalf("%+v.Nights() = %d, want %d", q, got, want)
+		}
+	}
+}
+
+func TestHTTPStayProvider_MapsPayload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/stays" || q.Get("city") != "Krakow" || q.Get("guests") != "2" || q.Get("checkIn") != "2026-03-06" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"stays":[
+			{"name":"Pricey","type":"Hotel","nightlyPrice":90,"totalPrice":300,"currency":"EUR","rating":4.8,"url":"https://x/1"},
+			{"name":"Beds","nightlyPrice":"17.5","rating":4.1,"url":"https://x/2"},
+			{"name":"Zloty","nightlyPrice":80,"currency":"PLN"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	p := NewHTTPStayProvider("HostelAPI", srv.URL+"/", srv.Client())
+	stays, err := p.SearchStays(context.Background(), StayQuery{City: "Krakow", CheckIn: "2026-03-06", CheckOut: "2026-03-08", Guests: 2})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	// PLN offer dropped; Beds is 17.50 x 2 guests x 2 nights = 70 and sorts first.
+	if len(stays) != 2 || stays[0].Name != "Beds" || stays[0].TotalPrice != money.FromFloat(70) || stays[0].Kind != "hostel" {
+		t.Fatalf("unexpected stays %+v", stays)
+	}
+	if stays[1].TotalPrice != money.FromFloat(300) || stays[1].Kind != "hotel" || stays[1].Provider != "HostelAPI" {
+		t.Fatalf("unexpected quoted stay %+v", stays[1])
+	}
+}
+
+func TestHTTPStayProvider_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	if _, err := NewHTTPStayProvider("HostelAPI", srv.URL, srv.Client()).SearchStays(context.Background(), StayQuery{City: "Krakow"}); err == nil {
+		t.Fatal("expected an error for a 502")
+	}
+}
+
+func TestNewAccommodationProviderFromEnv(t *testing.T) {
+	t.Setenv("STAY_PROVIDER", "")
+	if p, err := NewAccommodationProviderFromEnv(); p != nil || err != nil {
+		t.Fatalf("expected no provider, got %v %v", p, err)
+	}
+	t.Setenv("STAY_PROVIDER", "fixture")
+	if p, err := NewAccommodationProviderFromEnv(); err != nil {
+		t.Fatalf("fixture provider: %v", err)
+	} else if _, ok := p.(*FixtureStayProvider); !ok {
+		t.Fatalf("expected a fixture provider, got %T", p)
+	}
+	t.Setenv("STAY_PROVIDER", "http")
+	if _, err := NewAccommodationProviderFromEnv(); err == nil {
+		t.Fatal("expected an error without a base URL")
+	}
+	t.Setenv("STAY_PROVIDER", "fixture")
+	t.Setenv("STAY_FIXTURE_FILE", "does-not-exist.json")
+	if p, err := NewAccommodationProviderFromEnv(); err == nil || p != nil {
+		t.Fatalf("expected a read error and no provider, got %v %v", p, err)
+	}
+}
@@ -4,30 +4,81 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fares"
 )
 
 const (
 	defaultProviderBaseURL = "https://transport.opendata.ch/v1"
 	defaultProviderTimeout = 2500 * time.Millisecond
+	// queryDateLayout is the layout of TransportQuery.Date.
+	queryDateLayout = "2006-01-02"
 )
 
+// TransportQuery is a journey search. Date (YYYY-MM-DD) and Time (HH:MM)
+// are local to the origin, or to the destination when ArriveBy is set; when
+// empty the search is for journeys leaving now.
+type TransportQuery struct {
+	From       string
+	To         string
+	Date       string
+	Time       string
+	ArriveBy   bool
+	Passengers int
+	// Youth and Students are how many of the passengers may travel at a
+	// youth or student discount.
+	Youth    int
+	Students int
+	// Passes are held by the passenger searching.
+	Passes []fares.Pass
+	// CabinBags and CheckedBags are the bags the party brings, priced by
+	// providers that charge for them.
+	CabinBags   int
+	CheckedBags int
+}
+
+func (q TransportQuery) passengers() int { return max(q.Passengers, 1) }
+
+// Party is who q prices for.
+func (q TransportQuery) Party() fares.Party {
+	return fares.Party{Adults: max(q.passengers()-q.Youth-q.Students, 0), Youth: q.Youth, Students: q.Students, Passes: q.Passes}
+}
+
+// openDataCountry is where opendata's timetable is; fares are looked up as
+// Swiss unless the operator has its own.
+const openDataCountry = "CH"
+
+// Fares is the fare model shared by providers without their own prices.
+// Sources registered on it under a provider's name are asked before the
+// fare table in FARE_TABLE_FILE (or the bundled one) is used.
+var Fares = sync.OnceValue(func() *fares.Model {
+	table, err := fares.TableFromEnv()
+	if err != nil {
+		log.Printf("fare table: %v; using the bundled one", err)
+		table = fares.DefaultTable()
+	}
+	return fares.NewModel(table)
+})
+
 type TransportProvider interface {
-	SearchTransport(ctx context.Context, from, to string) ([]domain.TransportOption, error)
+	SearchTransport(ctx context.Context, q TransportQuery) ([]domain.TransportOption, error)
 }
 
 type OpenTransportProvider struct {
 	enabled bool
 	baseURL string
 	client  *http.Client
+	fares   *fares.Model
 }
 
 func NewOpenTransportProviderFromEnv() *OpenTransportProvider {
@@ -48,11 +99,15 @@ func NewOpenTransportProviderFromEnv() *OpenTransportProvider {
 	return &OpenTransportProvider{
 		enabled: enabled,
 		baseURL: baseURL,
-		client:  &http.Client{Timeout: timeout},
+		fares:   Fares(),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: outboundTransport("opendata"),
+		},
 	}
 }
 
-func (p *OpenTransportProvider) SearchTransport(ctx context.Context, from, to string) ([]domain.TransportOption, error) {
+func (p *OpenTransportProvider) SearchTransport(ctx context.Context, tq TransportQuery) ([]domain.TransportOption, error) {
 	if !p.enabled {
 		return nil, fmt.Errorf("provider disabled")
 	}
@@ -63,9 +118,18 @@ func (p *OpenTransportProvider) SearchTransport(ctx context.Context, from, to st
 	}
 
 	q := u.Query()
-	q.Set("to", to)
-	if strings.TrimSpace(from) != "" {
-		q.Set("from", from)
+	q.Set("to", tq.To)
+	if strings.TrimSpace(tq.From) != "" {
+		q.Set("from", tq.From)
+	}
+	if tq.Date != "" {
+		q.Set("date", tq.Date)
+	}
+	if tq.Time != "" {
+		q.Set("time", tq.Time)
+	}
+	if tq.ArriveBy {
+		q.Set("isArrivalTime", "1")
 	}
 	q.Set("limit", "3")
 	u.RawQuery = q.Encode()
@@ -87,15 +151,22 @@ func (p *OpenTransportProvider) SearchTransport(ctx context.Context, from, to st
 
 	var payload struct {
 		Connections []struct {
-			Duration  string   `json:"duration"`
-			Products  []string `json:"products"`
-			Transfers int      `json:"transfers"`
+			From      openStop          `json:"from"`
+			To        openStop          `json:"to"`
+			Duration  string            `json:"duration"`
+			Products  []string          `json:"products"`
+			Transfers int               `json:"transfers"`
+			Sections  []openTransitPart `json:"sections"`
 		} `json:"connections"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 		return nil, fmt.Errorf("decode provider response: %w", err)
 	}
 
+	model := p.fares
+	if model == nil {
+		model = Fares()
+	}
 	options := make([]domain.TransportOption, 0, len(payload.Connections))
 	for _, c := range payload.Connections {
 		hours, ok := parseDurationHours(c.Duration)
@@ -104,14 +175,31 @@ func (p *OpenTransportProvider) SearchTransport(ctx context.Context, from, to st
 		}
 
 		mode := inferMode(c.Products)
-		price := math.Round((18.0+hours*16.0+float64(c.Transfers*5.0))*100) / 100
+		legs := make([]domain.TransportLeg, 0, len(c.Sections))
+		for _, section := range c.Sections {
+			legs = append(legs, section.leg())
+		}
+		// opendata publishes no fares.
+		fare, ok := model.Price(ctx, fares.Journey{
+			Provider: "opendata", Country: openDataCountry, Mode: mode, Hours: hours, Legs: legs,
+		}, tq.Party())
+		if !ok {
+			continue
+		}
+		fare.Annotate(legs)
 
 		options = append(options, domain.TransportOption{
 			Provider:      "OpenTransportData",
 			Mode:          mode,
 			DurationHours: math.Round(hours*10) / 10,
-			Price:         price,
+			Price:         fare.Amount,
+			PriceKind:     fare.Kind,
 			Deeplink:      u.String(),
+			Departure:     parseOpenTime(c.From.Departure),
+			Arrival:       parseOpenTime(c.To.Arrival),
+			Transfers:     c.Transfers,
+			Legs:          legs,
+			Passes:        fare.Passes,
 		})
 	}
 
@@ -121,6 +209,98 @@ func (p *OpenTransportProvider) SearchTransport(ctx context.Context, from, to st
 	return options, nil
 }
 
+type openStation struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Coordinate struct {
+		// X is the latitude and Y the longitude.
+		X *float64 `json:"x"`
+		Y *float64 `json:"y"`
+	} `json:"coordinate"`
+}
+
+func (s openStation) located() bool { return s.Coordinate.X != nil && s.Coordinate.Y != nil }
+
+type openStop struct {
+	Station   openStation `json:"station"`
+	Departure string      `json:"departure"`
+	Arrival   string      `json:"arrival"`
+	Platform  string      `json:"platform"`
+}
+
+// openTransitPart is a connection section: a ride when Journey is set,
+// otherwise a walk between stations.
+type openTransitPart struct {
+	Journey *struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+		Operator string `json:"operator"`
+		To       string `json:"to"`
+	} `json:"journey"`
+	Walk *struct {
+		Duration int `json:"duration"` // seconds
+	} `json:"walk"`
+	Departure openStop `json:"departure"`
+	Arrival   openStop `json:"arrival"`
+}
+
+func (p openTransitPart) leg() domain.TransportLeg {
+	leg := domain.TransportLeg{
+		From: domain.LegStop{
+			Station:   p.Departure.Station.Name,
+			StationID: p.Departure.Station.ID,
+			Platform:  strings.TrimSpace(p.Departure.Platform),
+			Time:      parseOpenTime(p.Departure.Departure),
+		},
+		To: domain.LegStop{
+			Station:   p.Arrival.Station.Name,
+			StationID: p.Arrival.Station.ID,
+			Platform:  strings.TrimSpace(p.Arrival.Platform),
+			Time:      parseOpenTime(p.Arrival.Arrival),
+		},
+	}
+	if p.Journey == nil {
+		leg.Kind, leg.Mode = domain.LegWalk, "walk"
+		if p.Walk != nil {
+			leg.WalkMinutes = (p.Walk.Duration + 59) / 60
+		}
+		return leg
+	}
+	leg.Kind = domain.LegRide
+	if from, to := p.Departure.Station, p.Arrival.Station; from.located() && to.located() {
+		km := fares.StraightLineKm(*from.Coordinate.X, *from.Coordinate.Y, *to.Coordinate.X, *to.Coordinate.Y)
+		leg.DistanceKm = math.Round(km*10) / 10
+	}
+	leg.Mode = legMode(p.Journey.Category)
+	leg.Line = strings.TrimSpace(p.Journey.Name)
+	leg.Operator = p.Journey.Operator
+	leg.Direction = p.Journey.To
+	return leg
+}
+
+// parseOpenTime turns opendata's "2026-03-06T08:02:00+0100" into RFC 3339;
+// unparseable or missing times come back empty.
+func parseOpenTime(raw string) string {
+	t, err := time.Parse("2006-01-02T15:04:05-0700", raw)
+	if err != nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func legMode(category string) string {
+	switch strings.ToUpper(strings.TrimSpace(category)) {
+	case "B", "BUS", "NFB", "EXB":
+		return "bus"
+	case "T", "TRAM", "NFT":
+		return "tram"
+	case "BAT", "FAE":
+		return "ferry"
+	default:
+		return "train"
+	}
+}
+
 func inferMode(products []string) string {
 	for _, product := range products {
 		normalized := strings.ToUpper(strings.TrimSpace(product))
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -56,7 +58,7 @@ func TestSearchTransport_MapsProviderPayload(t *testing.T) {
 		},
 	}
 
-	opts, err := p.SearchTransport(context.Background(), "Berlin", "Prague")
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Prague"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -76,7 +78,76 @@ func TestSearchTransport_MapsProviderPayload(t *testing.T) {
 
 func TestSearchTransport_Disabled(t *testing.T) {
 	p := &OpenTransportProvider{enabled: false}
-	if _, err := p.SearchTransport(context.Background(), "Berlin", "Prague"); err == nil {
+	if _, err := p.SearchTransport(context.Background(), TransportQuery{From: "Berlin", To: "Prague"}); err == nil {
 		t.Fatal("expected disabled error")
 	}
 }
+
+func TestSearchTransport_DatedQueryAndLegs(t *testing.T) {
+	p := &OpenTransportProvider{
+		enabled: true,
+		baseURL: "https://provider.example.test",
+		client: &http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				q := r.URL.Query()
+				if q.Get("date") != "2026-03-06" || q.Get("time") != "17:30" || q.Get("isArrivalTime") != "1" {
+					t.Fatalf("unexpected query %s", r.URL.RawQuery)
+				}
+				body := `{"connections":[{
+					"from":{"station":{"name":"Berlin Hbf"},"departure":"2026-03-06T12:46:00+0100"},
+					"to":{"station":{"name":"Praha hl.n."},"arrival":"2026-03-06T17:12:00+0100"},
+					"duration":"00d04:26:00","products":["EC"],"transfers":1,
+					"sections":[
+						{"journey":{"name":"EC 177","category":"EC","operator":"DB","to":"Praha hl.n."},
+						 "departure":{"station":{"id":"8011160","name":"Berlin Hbf","coordinate":{"x":52.525,"y":13.369}},"departure":"2026-03-06T12:46:00+0100","platform":"13"},
+						 "arrival":{"station":{"id":"8010085","name":"Dresden Hbf","coordinate":{"x":51.040,"y":13.732}},"arrival":"2026-03-06T14:52:00+0100","platform":"17 "}},
+						{"journey":null,"walk":{"duration":300},
+						 "departure":{"station":{"name":"Dresden Hbf"}},"arrival":{"station":{"name":"Dresden Hbf"}}},
+						{"journey":{"name":"Bus 360","category":"B"},
+						 "departure":{"station":{"name":"Dresden Hbf"},"departure":"2026-03-06T15:05:00+0100"},
+						 "arrival":{"station":{"name":"Praha hl.n."},"arrival":"2026-03-06T17:12:00+0100"}}
+					]}]}`
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		},
+	}
+
+	opts, err := p.SearchTransport(context.Background(), TransportQuery{
+		From: "Berlin", To: "Prague", Date: "2026-03-06", Time: "17:30", ArriveBy: true, Passengers: 2, Youth: 1,
+	})
+	if err != nil || len(opts) != 1 {
+		t.Fatalf("expected one option, got %+v, %v", opts, err)
+	}
+	opt := opts[0]
+	if opt.Departure != "2026-03-06T12:46:00+01:00" || opt.Arrival != "2026-03-06T17:12:00+01:00" || opt.Transfers != 1 {
+		t.Fatalf("unexpected times %+v", opt)
+	}
+	// DB train: 5 + 167.0km*0.19 = 36.73; Swiss bus without a distance:
+	// 2.80 + 2.12h*15 = 34.55. 71.28 for the adult, 25% off with DB for
+	// the youth.
+	if opt.Price.String() != "124.74" || opt.PriceKind != domain.PriceEstimated {
+		t.Fatalf("expected the estimated fare for an adult and a youth, got %s %s", opt.Price, opt.PriceKind)
+	}
+	if len(opt.Legs) != 3 {
+		t.Fatalf("expected ride, walk and ride legs, got %+v", opt.Legs)
+	}
+	ride, walk, bus := opt.Legs[0], opt.Legs[1], opt.Legs[2]
+	wantRide := domain.TransportLeg{
+		Kind: domain.LegRide, Mode: "train", Line: "EC 177", Operator: "DB", Direction: "Praha hl.n.", DistanceKm: 167,
+		From: domain.LegStop{Station: "Berlin Hbf", StationID: "8011160", Platform: "13", Time: "2026-03-06T12:46:00+01:00"},
+		To:   domain.LegStop{Station: "Dresden Hbf", StationID: "8010085", Platform: "17", Time: "2026-03-06T14:52:00+01:00"},
+	}
+	if ride != wantRide {
+		t.Fatalf("unexpected ride leg %+v", ride)
+	}
+	if walk.Kind != domain.LegWalk || walk.WalkMinutes != 5 || walk.From.Station != "Dresden Hbf" {
+		t.Fatalf("unexpected walk leg %+v", walk)
+	}
+	if bus.Mode != "bus" || bus.From.Time != "2026-03-06T15:05:00+01:00" {
+		t.Fatalf("unexpected bus leg %+v", bus)
+	}
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *Store) ListAlertRules(userID string) []domain.AlertRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]domain.AlertRule, 0)
+	for _, rule := range s.alertRules {
+		if rule.UserID == userID {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// SaveAlertRule creates rule when it has no ID and replaces the caller's
+// existing rule otherwise.
+func (s *Store) SaveAlertRule(rule domain.AlertRule) (*domain.AlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rule.ID == "" {
+		rule.ID = makeID("ar")
+		s.alertRules = append(s.alertRules, rule)
+		return &rule, nil
+	}
+	i, err := s.ownedAlertRuleIndex(rule.UserID, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.alertRules[i] = rule
+	return &rule, nil
+}
+
+// DeleteAlertRule removes a rule. Alerts it already triggered are kept.
+func (s *Store) DeleteAlertRule(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedAlertRuleIndex(userID, id)
+	if err != nil {
+		return err
+	}
+	s.alertRules = append(s.alertRules[:i], s.alertRules[i+1:]...)
+	return nil
+}
+
+// RecordAlerts stores the alerts whose key has not been recorded for their
+// user yet and returns those.
+func (s *Store) RecordAlerts(alerts []domain.Alert) []domain.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recorded := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if slices.ContainsFunc(s.alerts, func(a domain.Alert) bool {
+			return a.UserID == alert.UserID && a.Key == alert.Key
+		}) {
+			continue
+		}
+		alert.ID = makeID("al")
+		alert.Read = false
+		s.alerts = append(s.alerts, alert)
+		recorded = append(recorded, alert)
+	}
+	return recorded
+}
+
+// ListAlerts returns the user's alerts, newest first.
+func (s *Store) ListAlerts(userID string, unreadOnly bool) []domain.Alert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]domain.Alert, 0)
+	for i := len(s.alerts) - 1; i >= 0; i-- {
+		alert := s.alerts[i]
+		if alert.UserID == userID && !(unreadOnly && alert.Read) {
+			res = append(res, alert)
+		}
+	}
+	return res
+}
+
+// MarkAlertsRead marks the given alerts, or all of the user's alerts when ids
+// is empty, as read and returns how many changed.
+func (s *Store) MarkAlertsRead(userID string, ids []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := 0
+	for i := range s.alerts {
+		alert := &s.alerts[i]
+		if alert.UserID != userID || alert.Read || (len(ids) > 0 && !slices.Contains(ids, alert.ID)) {
+			continue
+		}
+		alert.Read = true
+		changed++
+	}
+	return changed
+}
+
+func (s *Store) ownedAlertRuleIndex(userID, id string) (int, error) {
+	for i := range s.alertRules {
+		if s.alertRules[i].ID != id {
+			continue
+		}
+		if s.alertRules[i].UserID != userID {
+			return -1, fmt.Errorf("%w: alert rule %s belongs to another user", domain.ErrForbidden, id)
+		}
+		return i, nil
+	}
+	return -1, fmt.Errorf("%w: alert rule %s", domain.ErrNotFound, id)
+}
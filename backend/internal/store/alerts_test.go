@@ -0,0 +1,65 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func TestRecordAlerts_DedupesByKey(t *testing.T) {
+	s := New()
+	first := s.RecordAlerts([]domain.Alert{
+		{UserID: "u1", RuleID: "r1", Key: "r1/2026-04-01", TriggeredAt: "2026-04-02T00:00:00Z"},
+		{UserID: "u1", RuleID: "r2", Key: "r2/entry/b1", TriggeredAt: "2026-04-03T00:00:00Z"},
+	})
+	again := s.RecordAlerts([]domain.Alert{
+		{UserID: "u1", RuleID: "r1", Key: "r1/2026-04-01"},
+		{UserID: "u2", RuleID: "r9", Key: "r1/2026-04-01"},
+	})
+	if len(first) != 2 || len(again) != 1 || again[0].UserID != "u2" {
+		t.Fatalf("expected repeated keys to be skipped per user, got %+v then %+v", first, again)
+	}
+
+	listed := s.ListAlerts("u1", false)
+	if len(listed) != 2 || listed[0].RuleID != "r2" {
+		t.Fatalf("expected newest first, got %+v", listed)
+	}
+	if n := s.MarkAlertsRead("u1", []string{listed[0].ID}); n != 1 {
+		t.Fatalf("expected 1 marked, got %d", n)
+	}
+	if unread := s.ListAlerts("u1", true); len(unread) != 1 || unread[0].RuleID != "r1" {
+		t.Fatalf("unexpected unread alerts %+v", unread)
+	}
+	if n := s.MarkAlertsRead("u1", nil); n != 1 {
+		t.Fatalf("expected the remaining alert marked, got %d", n)
+	}
+}
+
+func TestSaveAlertRule_Ownership(t *testing.T) {
+	s := New()
+	rule, _ := s.SaveAlertRule(domain.AlertRule{UserID: "u1", Kind: domain.AlertExpenseOver, Threshold: money.FromFloat(50)})
+	if rule.ID == "" {
+		t.Fatal("expected an ID")
+	}
+	rule.Threshold = money.FromFloat(80)
+	if _, err := s.SaveAlertRule(*rule); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if rules := s.ListAlertRules("u1"); len(rules) != 1 || rules[0].Threshold != money.FromFloat(80) {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+
+	other := *rule
+	other.UserID = "u2"
+	if _, err := s.SaveAlertRule(other); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := s.DeleteAlertRule("u1", "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.DeleteAlertRule("u1", rule.ID); err != nil || len(s.ListAlertRules("u1")) != 0 {
+		t.Fatalf("expected rule deleted, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *Store) CreatePoll(poll domain.Poll) (*domain.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tripIndex(poll.TripID) < 0 {
+		return nil, fmt.Errorf("%w: trip %s", domain.ErrNotFound, poll.TripID)
+	}
+	poll.ID = makeID("poll")
+	poll.Status = domain.PollOpen
+	poll.Votes = []domain.PollVote{}
+	s.polls = append(s.polls, poll)
+	cp := clonePoll(poll)
+	return &cp, nil
+}
+
+func (s *Store) ListPolls(tripID string) []domain.Poll {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]domain.Poll, 0)
+	for i := range s.polls {
+		if s.polls[i].TripID != tripID {
+			continue
+		}
+		s.settlePoll(i, false)
+		res = append(res, clonePoll(s.polls[i]))
+	}
+	return res
+}
+
+func (s *Store) GetPoll(tripID, pollID string) (*domain.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.pollIndex(tripID, pollID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: poll %s", domain.ErrNotFound, pollID)
+	}
+	s.settlePoll(i, false)
+	cp := clonePoll(s.polls[i])
+	return &cp, nil
+}
+
+func (s *Store) VotePoll(tripID, pollID, userID, optionID string) (*domain.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.pollIndex(tripID, pollID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: poll %s", domain.ErrNotFound, pollID)
+	}
+	s.settlePoll(i, false)
+	if s.polls[i].Status != domain.PollOpen {
+		return nil, fmt.Errorf("%w: poll is closed", domain.ErrConflict)
+	}
+	if _, ok := s.polls[i].Option(optionID); !ok {
+		return nil, fmt.Errorf("%w: unknown option %s", domain.ErrInvalid, optionID)
+	}
+	s.polls[i].CastVote(userID, optionID)
+	s.settlePoll(i, false)
+	cp := clonePoll(s.polls[i])
+	return &cp, nil
+}
+
+func (s *Store) ClosePoll(tripID, pollID string) (*domain.Poll, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.pollIndex(tripID, pollID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: poll %s", domain.ErrNotFound, pollID)
+	}
+	s.settlePoll(i, true)
+	cp := clonePoll(s.polls[i])
+	return &cp, nil
+}
+
+// settlePoll closes poll i when its rule is met (or force is set) and applies
+// the winner to the trip for auto-apply polls. Callers must hold s.mu.
+func (s *Store) settlePoll(i int, force bool) {
+	poll := &s.polls[i]
+	if poll.Status != domain.PollOpen {
+		return
+	}
+	t := s.tripIndex(poll.TripID)
+	if t < 0 {
+		return
+	}
+	if !force && !poll.ShouldClose(len(s.trips[t].Members), time.Now()) {
+		return
+	}
+	poll.Close()
+	if poll.AutoApply {
+		poll.Applied = poll.ApplyTo(&s.trips[t])
+	}
+}
+
+func (s *Store) tripIndex(id string) int {
+	for i := range s.trips {
+		if s.trips[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) pollIndex(tripID, pollID string) int {
+	for i := range s.polls {
+		if s.polls[i].ID == pollID && s.polls[i].TripID == tripID {
+			return i
+		}
+	}
+	return -1
+}
+
+func clonePoll(p domain.Poll) domain.Poll {
+	p.Options = append([]domain.PollOption(nil), p.Options...)
+	p.Votes = append([]domain.PollVote{}, p.Votes...)
+	return p
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func newDestinationPoll(t *testing.T, s *Store, rule domain.PollCloseRule, autoApply bool) *domain.Poll {
+	t.Helper()
+	poll, err := s.CreatePoll(domain.Poll{
+		TripID:    "trip-1",
+		CreatedBy: "demo-user",
+		Question:  "Where?",
+		Kind:      domain.PollDestination,
+		Options: []domain.PollOption{
+			{ID: "o1", Label: "Vienna", Destination: "Vienna"},
+			{ID: "o2", Label: "Krakow", Destination: "Krakow"},
+		},
+		CloseRule: rule,
+		AutoApply: autoApply,
+	})
+	if err != nil {
+		t.Fatalf("create poll: %v", err)
+	}
+	return poll
+}
+
+func TestCreatePoll_UnknownTrip(t *testing.T) {
+	s := New()
+	_, err := s.CreatePoll(domain.Poll{TripID: "nonexistent"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestVotePoll_ReplacesEarlierVote(t *testing.T) {
+	s := New()
+	s.ShareTrip("trip-1", []string{"alice", "bob"})
+	poll := newDestinationPoll(t, s, domain.PollCloseManual, false)
+
+	s.VotePoll("trip-1", poll.ID, "alice", "o1")
+	got, err := s.VotePoll("trip-1", poll.ID, "alice", "o2")
+	if err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+	if len(got.Votes) != 1 || got.Votes[0].OptionID != "o2" {
+		t.Fatalf("expected single vote for o2, got %+v", got.Votes)
+	}
+}
+
+func TestVotePoll_UnknownOption(t *testing.T) {
+	s := New()
+	poll := newDestinationPoll(t, s, domain.PollCloseManual, false)
+	if _, err := s.VotePoll("trip-1", poll.ID, "demo-user", "o9"); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestVotePoll_MajorityClosesAndApplies(t *testing.T) {
+	s := New()
+	s.ShareTrip("trip-1", []string{"alice", "bob"})
+	poll := newDestinationPoll(t, s, domain.PollCloseMajority, true)
+
+	s.VotePoll("trip-1", poll.ID, "alice", "o2")
+	got, _ := s.VotePoll("trip-1", poll.ID, "bob", "o2")
+	if got.Status != domain.PollClosed {
+		t.Fatalf("expected closed poll, got %s", got.Status)
+	}
+	if got.WinnerOptionID != "o2" || !got.Applied {
+		t.Fatalf("expected applied winner o2, got %+v", got)
+	}
+	if trip := s.GetTrip("trip-1"); trip.Destination != "Krakow" {
+		t.Fatalf("expected trip destination Krakow, got %s", trip.Destination)
+	}
+	if _, err := s.VotePoll("trip-1", poll.ID, "demo-user", "o1"); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict voting on closed poll, got %v", err)
+	}
+}
+
+func TestVotePoll_AllVoted(t *testing.T) {
+	s := New()
+	s.ShareTrip("trip-1", []string{"alice"})
+	poll := newDestinationPoll(t, s, domain.PollCloseAllVoted, false)
+
+	got, _ := s.VotePoll("trip-1", poll.ID, "alice", "o1")
+	if got.Status != domain.PollOpen {
+		t.Fatal("expected poll to stay open until every member voted")
+	}
+	got, _ = s.VotePoll("trip-1", poll.ID, "demo-user", "o1")
+	if got.Status != domain.PollClosed {
+		t.Fatal("expected poll to close once every member voted")
+	}
+	if trip := s.GetTrip("trip-1"); trip.Destination != "Prague" {
+		t.Fatalf("expected destination untouched without autoApply, got %s", trip.Destination)
+	}
+}
+
+func TestClosePoll_TieHasNoWinner(t *testing.T) {
+	s := New()
+	s.ShareTrip("trip-1", []string{"alice"})
+	poll := newDestinationPoll(t, s, domain.PollCloseManual, true)
+	s.VotePoll("trip-1", poll.ID, "alice", "o1")
+	s.VotePoll("trip-1", poll.ID, "demo-user", "o2")
+
+	got, err := s.ClosePoll("trip-1", poll.ID)
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got.WinnerOptionID != "" || got.Applied {
+		t.Fatalf("expected no winner on tie, got %+v", got)
+	}
+}
+
+func TestGetPoll_DeadlinePassed(t *testing.T) {
+	s := New()
+	poll, _ := s.CreatePoll(domain.Poll{
+		TripID:    "trip-1",
+		Kind:      domain.PollWindow,
+		Options:   []domain.PollOption{{ID: "o1", WindowID: "w-3"}, {ID: "o2", WindowID: "w-2"}},
+		CloseRule: domain.PollCloseManual,
+		ClosesAt:  "2020-01-01",
+		AutoApply: true,
+	})
+	s.mu.Lock()
+	s.polls[len(s.polls)-1].Votes = []domain.PollVote{{UserID: "demo-user", OptionID: "o1"}}
+	s.mu.Unlock()
+
+	got, err := s.GetPoll("trip-1", poll.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != domain.PollClosed {
+		t.Fatal("expected poll past its deadline to be closed")
+	}
+	if trip := s.GetTrip("trip-1"); trip.WindowID != "w-3" {
+		t.Fatalf("expected window w-3 applied, got %s", trip.WindowID)
+	}
+}
+
+func TestListPolls(t *testing.T) {
+	s := New()
+	newDestinationPoll(t, s, domain.PollCloseManual, false)
+	if polls := s.ListPolls("trip-1"); len(polls) != 1 {
+		t.Fatalf("expected 1 poll, got %d", len(polls))
+	}
+	if polls := s.ListPolls("other"); len(polls) != 0 {
+		t.Fatalf("expected 0 polls, got %d", len(polls))
+	}
+}
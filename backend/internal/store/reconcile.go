@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"exchange-travel-planner/backend/internal/budget"
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+)
+
+func (s *Store) ReconcileTrip(tripID, currency string) (*domain.TripReconciliation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	in, err := s.reconcileInput(tripID, currency)
+	if err != nil {
+		return nil, err
+	}
+	report := budget.Reconcile(in)
+	return &report, nil
+}
+
+// FinalizeTripReconciliation records the trip's cost feedback for its
+// destination, replacing any earlier feedback for the same trip.
+func (s *Store) FinalizeTripReconciliation(tripID, currency string) (*domain.TripReconciliation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	in, err := s.reconcileInput(tripID, currency)
+	if err != nil {
+		return nil, err
+	}
+	s.costFeedback[tripID] = budget.Feedback(in)
+	in.Finalized = true
+	report := budget.Reconcile(in)
+	return &report, nil
+}
+
+// reconcileInput gathers a trip and its linked entries. Callers must hold s.mu.
+func (s *Store) reconcileInput(tripID, currency string) (budget.ReconcileInput, error) {
+	i := s.tripIndex(tripID)
+	if i < 0 {
+		return budget.ReconcileInput{}, fmt.Errorf("%w: trip %s", domain.ErrNotFound, tripID)
+	}
+	trip := s.trips[i]
+	in := budget.ReconcileInput{
+		Trip:     trip,
+		Entries:  []domain.BudgetEntry{},
+		Currency: currency,
+		Rates:    fx.NewTable(s.fxRates),
+	}
+	_, in.Finalized = s.costFeedback[tripID]
+	for _, window := range s.travelWindows {
+		if window.ID == trip.WindowID {
+			in.StartDate, in.EndDate = window.StartDate, window.EndDate
+		}
+	}
+	for _, dest := range s.destinations {
+		if strings.EqualFold(dest.City, trip.Destination) {
+			in.TransportBase, in.HostelNight = dest.TransportBase, dest.HostelNightEUR
+		}
+	}
+	for _, entry := range s.budgetEntries {
+		if entry.TripID == tripID {
+			in.Entries = append(in.Entries, entry)
+		}
+	}
+	return in, nil
+}
+
+// costFactors aggregates recorded feedback per destination. Callers must hold s.mu.
+func (s *Store) costFactors() map[string]domain.CostFactors {
+	feedback := make([]domain.CostFeedback, 0, len(s.costFeedback))
+	for _, f := range s.costFeedback {
+		feedback = append(feedback, f)
+	}
+	return budget.CostFactors(feedback)
+}
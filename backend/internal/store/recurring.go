@@ -0,0 +1,121 @@
+package store
+
+import (
+	"fmt"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *Store) CreateRecurringEntry(entry domain.RecurringEntry) domain.RecurringEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.ID = makeID("rec")
+	entry.Overrides = []domain.RecurringOverride{}
+	entry.MaterializedThrough = ""
+	s.recurring = append(s.recurring, entry)
+	return cloneRecurring(entry)
+}
+
+func (s *Store) ListRecurringEntries(userID string) []domain.RecurringEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]domain.RecurringEntry, 0)
+	for _, entry := range s.recurring {
+		if entry.UserID == userID {
+			res = append(res, cloneRecurring(entry))
+		}
+	}
+	return res
+}
+
+// DeleteRecurringEntry stops a series. Entries already generated are kept.
+func (s *Store) DeleteRecurringEntry(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedRecurringIndex(userID, id)
+	if err != nil {
+		return err
+	}
+	s.recurring = append(s.recurring[:i], s.recurring[i+1:]...)
+	return nil
+}
+
+// OverrideRecurringOccurrence records o and, when that occurrence has already
+// been generated, brings its budget entry in line.
+func (s *Store) OverrideRecurringOccurrence(userID, id string, o domain.RecurringOverride) (*domain.RecurringEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedRecurringIndex(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	template := &s.recurring[i]
+	if !template.IsOccurrence(o.Date) {
+		return nil, fmt.Errorf("%w: %s is not a scheduled occurrence", domain.ErrInvalid, o.Date)
+	}
+	template.SetOverride(o)
+
+	if template.MaterializedThrough != "" && o.Date <= template.MaterializedThrough {
+		existing := -1
+		for j, entry := range s.budgetEntries {
+			if entry.RecurringID == id && entry.Date == o.Date {
+				existing = j
+				break
+			}
+		}
+		want, keep := template.EntryOn(o.Date)
+		switch {
+		case !keep && existing >= 0:
+			s.budgetEntries = append(s.budgetEntries[:existing], s.budgetEntries[existing+1:]...)
+		case keep && existing >= 0:
+			want.ID = s.budgetEntries[existing].ID
+			want.TripID = s.budgetEntries[existing].TripID
+			s.budgetEntries[existing] = want
+		case keep:
+			want.ID = makeID("b")
+			s.budgetEntries = append(s.budgetEntries, want)
+		}
+	}
+	cp := cloneRecurring(*template)
+	return &cp, nil
+}
+
+// MaterializeRecurring generates the budget entries every template owes up
+// to and including asOf, and returns how many were created.
+func (s *Store) MaterializeRecurring(asOf string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created := 0
+	for i := range s.recurring {
+		template := &s.recurring[i]
+		if template.MaterializedThrough >= asOf {
+			continue
+		}
+		for _, entry := range template.Pending(asOf) {
+			entry.ID = makeID("b")
+			s.budgetEntries = append(s.budgetEntries, entry)
+			created++
+		}
+		template.MaterializedThrough = asOf
+	}
+	return created
+}
+
+// ownedRecurringIndex finds template id and checks it belongs to userID. Callers must hold s.mu.
+func (s *Store) ownedRecurringIndex(userID, id string) (int, error) {
+	for i := range s.recurring {
+		if s.recurring[i].ID != id {
+			continue
+		}
+		if s.recurring[i].UserID != userID {
+			return -1, fmt.Errorf("%w: recurring entry %s belongs to another user", domain.ErrForbidden, id)
+		}
+		return i, nil
+	}
+	return -1, fmt.Errorf("%w: recurring entry %s", domain.ErrNotFound, id)
+}
+
+func cloneRecurring(entry domain.RecurringEntry) domain.RecurringEntry {
+	entry.Overrides = append([]domain.RecurringOverride{}, entry.Overrides...)
+	return entry
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func newRent(s *Store) domain.RecurringEntry {
+	return s.CreateRecurringEntry(domain.RecurringEntry{
+		UserID: "demo-user", Category: "living", Amount: money.FromFloat(420), Currency: "EUR", Note: "Rent",
+		Frequency: domain.RecurringMonthly, Interval: 1, StartDate: "2026-01-31",
+	})
+}
+
+func recurringEntries(s *Store, id string) []domain.BudgetEntry {
+	var out []domain.BudgetEntry
+	for _, entry := range s.ListBudgetEntries("demo-user") {
+		if entry.RecurringID == id {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func TestRecurringOccurrences(t *testing.T) {
+	monthly := domain.RecurringEntry{Frequency: domain.RecurringMonthly, StartDate: "2026-01-31"}
+	want := []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"}
+	if got := monthly.Occurrences("2026-01-01", "2026-04-30"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("monthly occurrences = %v, want %v", got, want)
+	}
+	weekly := domain.RecurringEntry{Frequency: domain.RecurringWeekly, Interval: 2, StartDate: "2026-03-02", EndDate: "2026-03-31"}
+	want = []string{"2026-03-16", "2026-03-30"}
+	if got := weekly.Occurrences("2026-03-10", ""); !reflect.DeepEqual(got, want) {
+		t.Fatalf("weekly occurrences = %v, want %v", got, want)
+	}
+	if (domain.RecurringEntry{Frequency: domain.RecurringMonthly, StartDate: "2026-01-31"}).Occurrences("2026-01-01", "") != nil {
+		t.Fatalf("open-ended schedule without an end should yield nothing")
+	}
+}
+
+func TestMaterializeRecurring_Idempotent(t *testing.T) {
+	s := New()
+	rent := newRent(s)
+	if n := s.MaterializeRecurring("2026-03-15"); n != 2 {
+		t.Fatalf("expected 2 entries, got %d", n)
+	}
+	if n := s.MaterializeRecurring("2026-03-15"); n != 0 {
+		t.Fatalf("expected no new entries on rerun, got %d", n)
+	}
+	if n := s.MaterializeRecurring("2026-03-31"); n != 1 {
+		t.Fatalf("expected the March occurrence, got %d", n)
+	}
+	entries := recurringEntries(s, rent.ID)
+	if len(entries) != 3 || entries[1].Date != "2026-02-28" || entries[1].Amount != money.FromFloat(420) {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
+
+func TestOverrideRecurringOccurrence(t *testing.T) {
+	s := New()
+	rent := newRent(s)
+	s.MaterializeRecurring("2026-02-28")
+
+	amount := money.FromFloat(380)
+	if _, err := s.OverrideRecurringOccurrence("demo-user", rent.ID, domain.RecurringOverride{Date: "2026-02-28", Amount: &amount}); err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if _, err := s.OverrideRecurringOccurrence("demo-user", rent.ID, domain.RecurringOverride{Date: "2026-03-31", Skip: true}); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	entries := recurringEntries(s, rent.ID)
+	if len(entries) != 2 || entries[1].Amount != money.FromFloat(380) {
+		t.Fatalf("expected generated entry to be updated, got %+v", entries)
+	}
+	s.MaterializeRecurring("2026-04-30")
+	if entries := recurringEntries(s, rent.ID); len(entries) != 3 || entries[2].Date != "2026-04-30" {
+		t.Fatalf("expected March to be skipped, got %+v", entries)
+	}
+
+	// Skipping an occurrence that was already generated removes its entry.
+	if _, err := s.OverrideRecurringOccurrence("demo-user", rent.ID, domain.RecurringOverride{Date: "2026-01-31", Skip: true}); err != nil {
+		t.Fatalf("skip past: %v", err)
+	}
+	if entries := recurringEntries(s, rent.ID); len(entries) != 2 {
+		t.Fatalf("expected January entry removed, got %+v", entries)
+	}
+}
+
+func TestOverrideRecurringOccurrence_Errors(t *testing.T) {
+	s := New()
+	rent := newRent(s)
+	if _, err := s.OverrideRecurringOccurrence("demo-user", rent.ID, domain.RecurringOverride{Date: "2026-03-15"}); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for an unscheduled date, got %v", err)
+	}
+	if _, err := s.OverrideRecurringOccurrence("alice", rent.ID, domain.RecurringOverride{Date: "2026-02-28"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := s.DeleteRecurringEntry("demo-user", "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestForecast_ProjectsRecurring(t *testing.T) {
+	s := New()
+	s.CreateRecurringEntry(domain.RecurringEntry{
+		UserID: "demo-user", Category: "living", Amount: money.FromFloat(30), Currency: "EUR", Note: "Phone plan",
+		Frequency: domain.RecurringMonthly, StartDate: "2026-02-20",
+	})
+	before := New().Forecast("demo-user", "", "2026-02-10")
+	after := s.Forecast("demo-user", "", "2026-02-10")
+	if diff := after.ProjectedMonthlySpend - before.ProjectedMonthlySpend; diff != money.FromFloat(30) {
+		t.Fatalf("expected the phone plan to add 30, got %v", diff)
+	}
+	s.MaterializeRecurring("2026-02-28")
+	if again := s.Forecast("demo-user", "", "2026-02-10"); again.ProjectedMonthlySpend != after.ProjectedMonthlySpend {
+		t.Fatalf("generated occurrences should not be counted twice: %v vs %v", again.ProjectedMonthlySpend, after.ProjectedMonthlySpend)
+	}
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+
+	"exchange-travel-planner/backend/internal/domain"
+)
+
+func (s *Store) CreateSavingsGoal(goal domain.SavingsGoal) domain.SavingsGoal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	goal.ID = makeID("sg")
+	goal.Contributions = []domain.SavingsContribution{}
+	s.savingsGoals = append(s.savingsGoals, goal)
+	return cloneGoal(goal)
+}
+
+func (s *Store) ListSavingsGoals(userID string) []domain.SavingsGoal {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]domain.SavingsGoal, 0)
+	for _, goal := range s.savingsGoals {
+		if goal.UserID == userID {
+			res = append(res, cloneGoal(goal))
+		}
+	}
+	return res
+}
+
+func (s *Store) DeleteSavingsGoal(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedGoalIndex(userID, id)
+	if err != nil {
+		return err
+	}
+	s.savingsGoals = append(s.savingsGoals[:i], s.savingsGoals[i+1:]...)
+	return nil
+}
+
+// AddSavingsContribution records c against the caller's goal and returns the
+// updated goal.
+func (s *Store) AddSavingsContribution(userID, goalID string, c domain.SavingsContribution) (*domain.SavingsGoal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedGoalIndex(userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	goal := &s.savingsGoals[i]
+	if goal.Saved("")+c.Amount < 0 {
+		return nil, fmt.Errorf("%w: cannot withdraw more than was saved", domain.ErrInvalid)
+	}
+	c.ID = makeID("sc")
+	goal.Contributions = append(goal.Contributions, c)
+	cp := cloneGoal(*goal)
+	return &cp, nil
+}
+
+// ownedGoalIndex finds goal id and checks it belongs to userID. Callers must hold s.mu.
+func (s *Store) ownedGoalIndex(userID, id string) (int, error) {
+	for i := range s.savingsGoals {
+		if s.savingsGoals[i].ID != id {
+			continue
+		}
+		if s.savingsGoals[i].UserID != userID {
+			return -1, fmt.Errorf("%w: savings goal %s belongs to another user", domain.ErrForbidden, id)
+		}
+		return i, nil
+	}
+	return -1, fmt.Errorf("%w: savings goal %s", domain.ErrNotFound, id)
+}
+
+func cloneGoal(goal domain.SavingsGoal) domain.SavingsGoal {
+	goal.Contributions = append([]domain.SavingsContribution{}, goal.Contributions...)
+	return goal
+}
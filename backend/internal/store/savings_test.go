@@ -0,0 +1,37 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
+)
+
+func TestSavingsContributions(t *testing.T) {
+	s := New()
+	goal := s.CreateSavingsGoal(domain.SavingsGoal{
+		UserID: "demo-user", TripID: "trip-1", Name: "Prague", Target: money.FromFloat(220), Currency: "EUR", TargetDate: "2026-03-06",
+	})
+	if _, err := s.AddSavingsContribution("demo-user", goal.ID, domain.SavingsContribution{Amount: money.FromFloat(80), Date: "2026-02-01"}); err != nil {
+		t.Fatalf("contribute: %v", err)
+	}
+	updated, err := s.AddSavingsContribution("demo-user", goal.ID, domain.SavingsContribution{Amount: money.FromFloat(-30), Date: "2026-02-10"})
+	if err != nil || updated.Saved("") != money.FromFloat(50) || len(updated.Contributions) != 2 || updated.Contributions[1].ID == "" {
+		t.Fatalf("unexpected goal after withdrawal %+v, %v", updated, err)
+	}
+	if _, err := s.AddSavingsContribution("demo-user", goal.ID, domain.SavingsContribution{Amount: money.FromFloat(-60), Date: "2026-02-11"}); !errors.Is(err, domain.ErrInvalid) {
+		t.Fatalf("expected ErrInvalid when overdrawing, got %v", err)
+	}
+	if _, err := s.AddSavingsContribution("other-user", goal.ID, domain.SavingsContribution{Amount: 1}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+
+	f := s.Forecast("demo-user", "", "2026-02-15")
+	if len(f.Goals) != 1 || f.Goals[0].Saved != money.FromFloat(50) || f.Goals[0].PeriodsLeft != 2 {
+		t.Fatalf("expected the goal in the forecast, got %+v", f.Goals)
+	}
+	if err := s.DeleteSavingsGoal("demo-user", goal.ID); err != nil || len(s.ListSavingsGoals("demo-user")) != 0 {
+		t.Fatalf("delete: %v", err)
+	}
+}
@@ -9,14 +9,17 @@ import (
 	"sync"
 	"time"
 
+	"exchange-travel-planner/backend/internal/budget"
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/fx"
+	"exchange-travel-planner/backend/internal/money"
 )
 
 type destinationSeed struct {
 	City           string
 	BaseTravelHrs  float64
-	TransportBase  float64
-	HostelNightEUR float64
+	TransportBase  money.Amount
+	HostelNightEUR money.Amount
 	Tags           []string
 }
 
@@ -27,8 +30,17 @@ type Store struct {
 	travelWindows  []domain.TravelWindow
 	trips          []domain.Trip
 	budgetEntries  []domain.BudgetEntry
-	monthlyBudget  map[string]float64
+	budgetSettings map[string]domain.BudgetSettings
+	recurring      []domain.RecurringEntry
+	categoryRules  map[string][]domain.CategoryRule
+	costFeedback   map[string]domain.CostFeedback // by trip ID
+	alertRules     []domain.AlertRule
+	alerts         []domain.Alert
+	savingsGoals   []domain.SavingsGoal
 	destinations   []destinationSeed
+	polls          []domain.Poll
+	profiles       map[string]domain.UserProfile
+	fxRates        []domain.FXRate
 }
 
 func New() *Store {
@@ -44,18 +56,36 @@ func New() *Store {
 			{ID: "w-3", StartDate: "2026-04-03", EndDate: "2026-04-06", Score: 95, Conflicts: []string{}},
 		},
 		trips: []domain.Trip{
-			{ID: "trip-1", OwnerID: "demo-user", Destination: "Prague", WindowID: "w-1", Members: []string{"demo-user"}, Itinerary: []string{"Old Town walk", "Charles Bridge sunrise"}, EstimatedCost: 220},
+			{ID: "trip-1", OwnerID: "demo-user", Destination: "Prague", WindowID: "w-1", Members: []string{"demo-user"}, Itinerary: []string{"Old Town walk", "Charles Bridge sunrise"}, EstimatedCost: money.FromFloat(220)},
 		},
 		budgetEntries: []domain.BudgetEntry{
-			{ID: "b-1", UserID: "demo-user", Category: "living", Amount: 420, Currency: "EUR", Date: "2026-02-05", Note: "Rent split"},
-			{ID: "b-2", UserID: "demo-user", Category: "travel", Amount: 60, Currency: "EUR", Date: "2026-02-08", Note: "Train to Vienna"},
+			{ID: "b-1", UserID: "demo-user", Category: "living", Amount: money.FromFloat(420), Currency: "EUR", Date: "2026-02-05", Note: "Rent split"},
+			{ID: "b-2", UserID: "demo-user", Category: "travel", Amount: money.FromFloat(60), Currency: "EUR", Date: "2026-02-08", Note: "Train to Vienna"},
+		},
+		budgetSettings: map[string]domain.BudgetSettings{
+			"demo-user": {
+				UserID: "demo-user", MonthlyBudget: money.FromFloat(900), Currency: "EUR", StartDay: 1,
+				Allocations: map[string]money.Amount{"living": money.FromFloat(500), "travel": money.FromFloat(250), "food": money.FromFloat(150)},
+			},
+		},
+		categoryRules: map[string][]domain.CategoryRule{},
+		costFeedback:  map[string]domain.CostFeedback{},
+		profiles: map[string]domain.UserProfile{
+			"demo-user": {UserID: "demo-user", DisplayName: "Demo User", HomeCity: "Berlin", Style: "culture", MaxTravelHours: 6, HomeCurrency: "EUR"},
+		},
+		fxRates: []domain.FXRate{
+			{Date: "2026-01-02", Currency: "CZK", Rate: 25.2},
+			{Date: "2026-01-02", Currency: "HUF", Rate: 400},
+			{Date: "2026-01-02", Currency: "PLN", Rate: 4.25},
+			{Date: "2026-01-02", Currency: "CHF", Rate: 0.94},
+			{Date: "2026-01-02", Currency: "GBP", Rate: 0.84},
+			{Date: "2026-01-02", Currency: "USD", Rate: 1.08},
 		},
-		monthlyBudget: map[string]float64{"demo-user": 900},
 		destinations: []destinationSeed{
-			{City: "Prague", BaseTravelHrs: 3.8, TransportBase: 55, HostelNightEUR: 28, Tags: []string{"culture", "city"}},
-			{City: "Budapest", BaseTravelHrs: 4.7, TransportBase: 47, HostelNightEUR: 24, Tags: []string{"nightlife", "city"}},
-			{City: "Ljubljana", BaseTravelHrs: 5.2, TransportBase: 41, HostelNightEUR: 30, Tags: []string{"nature", "city"}},
-			{City: "Krakow", BaseTravelHrs: 2.9, TransportBase: 50, HostelNightEUR: 22, Tags: []string{"culture", "city"}},
+			{City: "Prague", BaseTravelHrs: 3.8, TransportBase: money.FromFloat(55), HostelNightEUR: money.FromFloat(28), Tags: []string{"culture", "city"}},
+			{City: "Budapest", BaseTravelHrs: 4.7, TransportBase: money.FromFloat(47), HostelNightEUR: money.FromFloat(24), Tags: []string{"nightlife", "city"}},
+			{City: "Ljubljana", BaseTravelHrs: 5.2, TransportBase: money.FromFloat(41), HostelNightEUR: money.FromFloat(30), Tags: []string{"nature", "city"}},
+			{City: "Krakow", BaseTravelHrs: 2.9, TransportBase: money.FromFloat(50), HostelNightEUR: money.FromFloat(22), Tags: []string{"culture", "city"}},
 		},
 	}
 }
@@ -77,6 +107,19 @@ func (s *Store) ImportAcademicEvents(events []domain.AcademicEvent) []domain.Aca
 	return append([]domain.AcademicEvent(nil), s.academicEvents...)
 }
 
+// ListAcademicEvents returns the shared events and those imported by userID.
+func (s *Store) ListAcademicEvents(userID string) []domain.AcademicEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]domain.AcademicEvent, 0)
+	for _, event := range s.academicEvents {
+		if event.UserID == "" || event.UserID == userID {
+			res = append(res, event)
+		}
+	}
+	return res
+}
+
 func (s *Store) ListTravelWindows(from, to string) []domain.TravelWindow {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -102,8 +145,11 @@ func (s *Store) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 		domain.TripOption
 		Score float64
 	}
+	factors := s.costFactors()
 	items := make([]scored, 0, len(s.destinations))
 	for _, entry := range s.destinations {
+		// Finalised trips adjust seeded costs towards what people really paid.
+		adjust := budget.FactorsFor(factors, entry.City)
 		styleBoost := 0.0
 		for _, tag := range entry.Tags {
 			if tag == c.Style {
@@ -112,12 +158,12 @@ func (s *Store) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 			}
 		}
 		durationPenalty := math.Max(0, entry.BaseTravelHrs-c.MaxTravelHours) * 18
-		transportPrice := entry.TransportBase + float64(c.PartySize*7)
-		stayPrice := entry.HostelNightEUR * 2 * float64(c.PartySize)
-		total := math.Round(transportPrice + stayPrice)
+		transportPrice := (entry.TransportBase + money.Amount(c.PartySize*7*money.Scale)).Mul(adjust.Transport)
+		stayPrice := entry.HostelNightEUR.Mul(2 * float64(c.PartySize) * adjust.Stay)
+		total := (transportPrice + stayPrice).Round()
 		budgetPenalty := 0.0
 		if total > c.BudgetCap {
-			budgetPenalty = (total - c.BudgetCap) / 3
+			budgetPenalty = (total - c.BudgetCap).Float() / 3
 		}
 		score := 100 + styleBoost - durationPenalty - budgetPenalty
 
@@ -136,12 +182,12 @@ func (s *Store) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 		}
 
 		transport := []domain.TransportOption{
-			{Provider: "EuroRail Connect", Mode: "train", DurationHours: round(entry.BaseTravelHrs, 1), Price: math.Round(transportPrice * 0.92), Deeplink: "https://example.com/train"},
-			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: round(entry.BaseTravelHrs*1.3, 1), Price: math.Round(transportPrice * 0.76), Deeplink: "https://example.com/bus"},
+			{Provider: "EuroRail Connect", Mode: "train", DurationHours: budget.Round(entry.BaseTravelHrs, 1), Price: transportPrice.Mul(0.92).Round(), Deeplink: "https://example.com/train", Synthetic: true, PriceKind: domain.PriceEstimated},
+			{Provider: "BudgetBus Europe", Mode: "bus", DurationHours: budget.Round(entry.BaseTravelHrs*1.3, 1), Price: transportPrice.Mul(0.76).Round(), Deeplink: "https://example.com/bus", Synthetic: true, PriceKind: domain.PriceEstimated},
 		}
 		stays := []domain.StayOption{
-			{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR, Rating: 4.3, Deeplink: "https://example.com/hostel"},
-			{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: entry.HostelNightEUR + 16, Rating: 4.0, Deeplink: "https://example.com/hotel"},
+			{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR.Mul(adjust.Stay), Rating: 4.3, Deeplink: "https://example.com/hostel", Synthetic: true},
+			{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: (entry.HostelNightEUR + 16*money.Scale).Mul(adjust.Stay), Rating: 4.0, Deeplink: "https://example.com/hotel", Synthetic: true},
 		}
 
 		risk := domain.SeverityInfo
@@ -155,9 +201,11 @@ func (s *Store) OptimizeTrips(c domain.TripConstraint) []domain.TripOption {
 				Destination:        entry.City,
 				ReasonTags:         reasons,
 				TotalEstimatedCost: total,
+				StayCost:           stayPrice,
 				TransportOptions:   transport,
 				StayOptions:        stays,
 				RiskLevel:          risk,
+				Currency:           fx.Base,
 			},
 			Score: score,
 		})
@@ -219,6 +267,19 @@ func (s *Store) AddBudgetEntry(entry domain.BudgetEntry) domain.BudgetEntry {
 	return entry
 }
 
+// AddBudgetEntries adds a batch of entries, e.g. from a bank import.
+func (s *Store) AddBudgetEntries(entries []domain.BudgetEntry) []domain.BudgetEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]domain.BudgetEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.ID = makeID("b")
+		s.budgetEntries = append(s.budgetEntries, entry)
+		out = append(out, entry)
+	}
+	return out
+}
+
 func (s *Store) ListBudgetEntries(userID string) []domain.BudgetEntry {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -231,44 +292,153 @@ func (s *Store) ListBudgetEntries(userID string) []domain.BudgetEntry {
 	return res
 }
 
-func (s *Store) Forecast(userID, tripID string) domain.ForecastResult {
-	entries := s.ListBudgetEntries(userID)
-	spend := 0.0
-	for _, entry := range entries {
-		spend += entry.Amount
+func (s *Store) QueryBudgetEntries(userID string, filter domain.BudgetEntryFilter) domain.BudgetEntryPage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	matches := make([]domain.BudgetEntry, 0)
+	for _, entry := range s.budgetEntries {
+		if entry.UserID == userID && filter.Matches(entry) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Date < matches[j].Date })
+
+	page := domain.BudgetEntryPage{Total: len(matches), Limit: filter.Limit, Offset: filter.Offset}
+	start := min(max(filter.Offset, 0), len(matches))
+	end := len(matches)
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, len(matches))
+	}
+	page.Entries = matches[start:end]
+	return page
+}
+
+// StreamBudgetEntries calls fn for each matching entry in date order. The
+// matches are copied first so fn runs without holding the lock.
+func (s *Store) StreamBudgetEntries(userID string, filter domain.BudgetEntryFilter, fn func(domain.BudgetEntry) error) error {
+	filter.Limit, filter.Offset = 0, 0
+	for _, entry := range s.QueryBudgetEntries(userID, filter).Entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) UpdateBudgetEntry(userID, id string, patch domain.BudgetEntryPatch) (*domain.BudgetEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedEntryIndex(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	patch.Apply(&s.budgetEntries[i])
+	cp := s.budgetEntries[i]
+	return &cp, nil
+}
+
+func (s *Store) DeleteBudgetEntry(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.ownedEntryIndex(userID, id)
+	if err != nil {
+		return err
+	}
+	s.budgetEntries = append(s.budgetEntries[:i], s.budgetEntries[i+1:]...)
+	return nil
+}
+
+// ownedEntryIndex finds entry id and checks it belongs to userID. Callers must hold s.mu.
+func (s *Store) ownedEntryIndex(userID, id string) (int, error) {
+	for i := range s.budgetEntries {
+		if s.budgetEntries[i].ID != id {
+			continue
+		}
+		if s.budgetEntries[i].UserID != userID {
+			return -1, fmt.Errorf("%w: entry %s belongs to another user", domain.ErrForbidden, id)
+		}
+		return i, nil
 	}
+	return -1, fmt.Errorf("%w: entry %s", domain.ErrNotFound, id)
+}
+
+func (s *Store) Forecast(userID, tripID, asOf string) domain.ForecastResult {
+	entries := s.ListBudgetEntries(userID)
+	profile := s.GetProfile(userID)
+	settings := s.GetBudgetSettings(userID)
+	templates := s.ListRecurringEntries(userID)
+	goals := s.ListSavingsGoals(userID)
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	budget := s.monthlyBudget[userID]
-	if budget == 0 {
-		budget = 900
+	in := budget.ForecastInput{
+		Entries:   entries,
+		Settings:  settings,
+		Templates: templates,
+		Goals:     goals,
+		TripID:    tripID,
+		AsOf:      asOf,
+		Currency:  profile.HomeCurrency,
+		Rates:     fx.NewTable(s.fxRates),
 	}
-	tripCost := 0.0
-	if tripID != "" {
-		for _, trip := range s.trips {
-			if trip.ID == tripID {
-				tripCost = trip.EstimatedCost
-				break
+	if i := s.tripIndex(tripID); tripID != "" && i >= 0 {
+		in.TripCost = s.trips[i].EstimatedCost
+		for _, window := range s.travelWindows {
+			if window.ID == s.trips[i].WindowID {
+				in.TripDate = window.StartDate
 			}
 		}
 	}
-	projected := spend + tripCost
-	remaining := budget - projected
-	affordability := "green"
-	if remaining < 0 {
-		affordability = "red"
-	} else if remaining < 200 {
-		affordability = "amber"
+	return budget.Forecast(in)
+}
+
+func (s *Store) GetBudgetSettings(userID string) domain.BudgetSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	settings, ok := s.budgetSettings[userID]
+	if !ok {
+		return budget.DefaultSettings(userID)
 	}
-	return domain.ForecastResult{
-		ProjectedMonthlySpend: round(projected, 2),
-		RemainingBudget:       round(remaining, 2),
-		Affordability:         affordability,
+	allocations := make(map[string]money.Amount, len(settings.Allocations))
+	for category, amount := range settings.Allocations {
+		allocations[category] = amount
 	}
+	settings.Allocations = allocations
+	return settings
+}
+
+func (s *Store) SaveBudgetSettings(settings domain.BudgetSettings) domain.BudgetSettings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budgetSettings[settings.UserID] = settings
+	return settings
+}
+
+func (s *Store) GetCategoryRules(userID string) []domain.CategoryRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.CategoryRule{}, s.categoryRules[userID]...)
+}
+
+func (s *Store) SaveCategoryRules(userID string, rules []domain.CategoryRule) []domain.CategoryRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.categoryRules[userID] = append([]domain.CategoryRule{}, rules...)
+	return rules
 }
 
 func (s *Store) EvaluateConflicts(windowID string) []domain.ConflictAlert {
+	return s.evaluateConflicts(windowID, func(domain.AcademicEvent) bool { return true })
+}
+
+// EvaluateMemberConflicts only considers shared events and events imported by userID.
+func (s *Store) EvaluateMemberConflicts(userID, windowID string) []domain.ConflictAlert {
+	return s.evaluateConflicts(windowID, func(event domain.AcademicEvent) bool {
+		return event.UserID == "" || event.UserID == userID
+	})
+}
+
+func (s *Store) evaluateConflicts(windowID string, include func(domain.AcademicEvent) bool) []domain.ConflictAlert {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	var target *domain.TravelWindow
@@ -286,6 +456,9 @@ func (s *Store) EvaluateConflicts(windowID string) []domain.ConflictAlert {
 	end, _ := time.Parse("2006-01-02", target.EndDate)
 	alerts := make([]domain.ConflictAlert, 0)
 	for _, event := range s.academicEvents {
+		if !include(event) {
+			continue
+		}
 		eventDate, err := time.Parse("2006-01-02", event.Start)
 		if err != nil {
 			continue
@@ -314,8 +487,8 @@ func (s *Store) SearchTransport(_from, to string) []domain.TransportOption {
 	for _, entry := range s.destinations {
 		if strings.EqualFold(entry.City, to) {
 			return []domain.TransportOption{
-				{Provider: "EuroRail Connect", Mode: "train", DurationHours: entry.BaseTravelHrs, Price: math.Round(entry.TransportBase * 0.9), Deeplink: "https://example.com/train"},
-				{Provider: "SkySaver", Mode: "flight", DurationHours: round(entry.BaseTravelHrs*0.65, 1), Price: math.Round(entry.TransportBase * 1.18), Deeplink: "https://example.com/flight"},
+				{Provider: "EuroRail Connect", Mode: "train", DurationHours: entry.BaseTravelHrs, Price: money.FromFloat(math.Round(entry.TransportBase.Float() * 0.9)), Deeplink: "https://example.com/train", Synthetic: true, PriceKind: domain.PriceEstimated},
+				{Provider: "SkySaver", Mode: "flight", DurationHours: budget.Round(entry.BaseTravelHrs*0.65, 1), Price: money.FromFloat(math.Round(entry.TransportBase.Float() * 1.18)), Deeplink: "https://example.com/flight", Synthetic: true, PriceKind: domain.PriceEstimated},
 			}
 		}
 	}
@@ -328,17 +501,54 @@ func (s *Store) SearchStays(city string) []domain.StayOption {
 	for _, entry := range s.destinations {
 		if strings.EqualFold(entry.City, city) {
 			return []domain.StayOption{
-				{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR, Rating: 4.2, Deeplink: "https://example.com/hostel"},
-				{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: entry.HostelNightEUR + 14, Rating: 4.0, Deeplink: "https://example.com/hotel"},
+				{Provider: "HostelGraph", Kind: "hostel", NightlyPrice: entry.HostelNightEUR, Rating: 4.2, Deeplink: "https://example.com/hostel", Synthetic: true},
+				{Provider: "StudentStay", Kind: "budget-hotel", NightlyPrice: entry.HostelNightEUR + 14*money.Scale, Rating: 4.0, Deeplink: "https://example.com/hotel", Synthetic: true},
 			}
 		}
 	}
 	return []domain.StayOption{}
 }
 
-func (s *Store) Close() error { return nil }
+// SaveFXRates stores rates, replacing any existing rate for the same date and currency.
+func (s *Store) SaveFXRates(rates []domain.FXRate) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index := make(map[[2]string]int, len(s.fxRates))
+	for i, r := range s.fxRates {
+		index[[2]string{r.Date, r.Currency}] = i
+	}
+	for _, r := range rates {
+		key := [2]string{r.Date, r.Currency}
+		if i, ok := index[key]; ok {
+			s.fxRates[i] = r
+			continue
+		}
+		index[key] = len(s.fxRates)
+		s.fxRates = append(s.fxRates, r)
+	}
+	return len(rates)
+}
+
+func (s *Store) ListFXRates() []domain.FXRate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.FXRate(nil), s.fxRates...)
+}
+
+func (s *Store) GetProfile(userID string) domain.UserProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if profile, ok := s.profiles[userID]; ok {
+		return profile
+	}
+	return domain.UserProfile{UserID: userID}
+}
 
-func round(value float64, precision int) float64 {
-	factor := math.Pow10(precision)
-	return math.Round(value*factor) / factor
+func (s *Store) SaveProfile(profile domain.UserProfile) domain.UserProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[profile.UserID] = profile
+	return profile
 }
+
+func (s *Store) Close() error { return nil }
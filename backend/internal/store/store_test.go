@@ -1,9 +1,11 @@
 package store
 
 import (
+	"errors"
 	"testing"
 
 	"exchange-travel-planner/backend/internal/domain"
+	"exchange-travel-planner/backend/internal/money"
 )
 
 func TestListTravelWindows_All(t *testing.T) {
@@ -36,7 +38,7 @@ func TestListTravelWindows_FromOnly(t *testing.T) {
 func TestOptimizeTrips(t *testing.T) {
 	s := New()
 	opts := s.OptimizeTrips(domain.TripConstraint{
-		BudgetCap:      300,
+		BudgetCap:      money.FromFloat(300),
 		MaxTravelHours: 6,
 		PartySize:      1,
 		Style:          "culture",
@@ -50,7 +52,7 @@ func TestOptimizeTrips(t *testing.T) {
 		if o.Destination == "" {
 			t.Fatal("empty destination")
 		}
-		if o.TotalEstimatedCost <= 0 {
+		if o.TotalEstimatedCost <= money.FromFloat(0) {
 			t.Fatalf("cost should be positive for %s", o.Destination)
 		}
 		if len(o.TransportOptions) != 2 {
@@ -65,13 +67,13 @@ func TestOptimizeTrips(t *testing.T) {
 func TestOptimizeTrips_OverBudget(t *testing.T) {
 	s := New()
 	opts := s.OptimizeTrips(domain.TripConstraint{
-		BudgetCap:      10,
+		BudgetCap:      money.FromFloat(10),
 		MaxTravelHours: 6,
 		PartySize:      1,
 		Style:          "city",
 	})
 	for _, o := range opts {
-		if o.TotalEstimatedCost <= 10 {
+		if o.TotalEstimatedCost <= money.FromFloat(10) {
 			continue
 		}
 		if o.RiskLevel != domain.SeverityWarning {
@@ -146,15 +148,15 @@ func TestAddBudgetEntry(t *testing.T) {
 	entry := s.AddBudgetEntry(domain.BudgetEntry{
 		UserID:   "demo-user",
 		Category: "travel",
-		Amount:   50,
+		Amount:   money.FromFloat(50),
 		Currency: "EUR",
 		Date:     "2026-03-01",
 	})
 	if entry.ID == "" {
 		t.Fatal("expected generated ID")
 	}
-	if entry.Amount != 50 {
-		t.Fatalf("expected 50, got %f", entry.Amount)
+	if entry.Amount != money.FromFloat(50) {
+		t.Fatalf("expected 50, got %s", entry.Amount)
 	}
 }
 
@@ -176,12 +178,12 @@ func TestListBudgetEntries_Empty(t *testing.T) {
 
 func TestForecast_WithoutTrip(t *testing.T) {
 	s := New()
-	f := s.Forecast("demo-user", "")
-	if f.ProjectedMonthlySpend != 480 {
-		t.Fatalf("expected 480, got %f", f.ProjectedMonthlySpend)
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	if f.ProjectedMonthlySpend != money.FromFloat(480) {
+		t.Fatalf("expected 480, got %s", f.ProjectedMonthlySpend)
 	}
-	if f.RemainingBudget != 420 {
-		t.Fatalf("expected 420, got %f", f.RemainingBudget)
+	if f.RemainingBudget != money.FromFloat(420) {
+		t.Fatalf("expected 420, got %s", f.RemainingBudget)
 	}
 	if f.Affordability != "green" {
 		t.Fatalf("expected green, got %s", f.Affordability)
@@ -190,10 +192,10 @@ func TestForecast_WithoutTrip(t *testing.T) {
 
 func TestForecast_WithTrip(t *testing.T) {
 	s := New()
-	f := s.Forecast("demo-user", "trip-1")
+	f := s.Forecast("demo-user", "trip-1", "2026-02-28")
 	// 480 + 220 = 700, remaining = 200, which is not < 200 so green
-	if f.ProjectedMonthlySpend != 700 {
-		t.Fatalf("expected 700, got %f", f.ProjectedMonthlySpend)
+	if f.ProjectedMonthlySpend != money.FromFloat(700) {
+		t.Fatalf("expected 700, got %s", f.ProjectedMonthlySpend)
 	}
 	if f.Affordability != "green" {
 		t.Fatalf("expected green, got %s", f.Affordability)
@@ -203,9 +205,9 @@ func TestForecast_WithTrip(t *testing.T) {
 func TestForecast_Amber(t *testing.T) {
 	s := New()
 	s.AddBudgetEntry(domain.BudgetEntry{
-		UserID: "demo-user", Category: "living", Amount: 1, Currency: "EUR", Date: "2026-02-10",
+		UserID: "demo-user", Category: "living", Amount: money.FromFloat(1), Currency: "EUR", Date: "2026-02-10",
 	})
-	f := s.Forecast("demo-user", "trip-1")
+	f := s.Forecast("demo-user", "trip-1", "2026-02-28")
 	// 481 + 220 = 701, remaining = 199 < 200
 	if f.Affordability != "amber" {
 		t.Fatalf("expected amber, got %s", f.Affordability)
@@ -216,9 +218,9 @@ func TestForecast_Red(t *testing.T) {
 	s := New()
 	// Add a big entry to push over budget
 	s.AddBudgetEntry(domain.BudgetEntry{
-		UserID: "demo-user", Category: "living", Amount: 500, Currency: "EUR", Date: "2026-02-10",
+		UserID: "demo-user", Category: "living", Amount: money.FromFloat(500), Currency: "EUR", Date: "2026-02-10",
 	})
-	f := s.Forecast("demo-user", "trip-1")
+	f := s.Forecast("demo-user", "trip-1", "2026-02-28")
 	if f.Affordability != "red" {
 		t.Fatalf("expected red, got %s", f.Affordability)
 	}
@@ -226,9 +228,9 @@ func TestForecast_Red(t *testing.T) {
 
 func TestForecast_UnknownUser(t *testing.T) {
 	s := New()
-	f := s.Forecast("nobody", "")
+	f := s.Forecast("nobody", "", "2026-02-28")
 	if f.ProjectedMonthlySpend != 0 {
-		t.Fatalf("expected 0, got %f", f.ProjectedMonthlySpend)
+		t.Fatalf("expected 0, got %s", f.ProjectedMonthlySpend)
 	}
 	if f.Affordability != "green" {
 		t.Fatalf("expected green, got %s", f.Affordability)
@@ -324,3 +326,236 @@ func TestImportAcademicEvents_WithID(t *testing.T) {
 		t.Fatalf("expected custom-id, got %s", last.ID)
 	}
 }
+
+func TestEvaluateMemberConflicts_OnlyOwnAndSharedEvents(t *testing.T) {
+	s := New()
+	s.ImportAcademicEvents([]domain.AcademicEvent{
+		{UserID: "alice", Type: domain.AcademicExam, Title: "Alice Exam", Start: "2026-03-21", End: "2026-03-21", Priority: 5},
+	})
+	if alerts := s.EvaluateMemberConflicts("bob", "w-2"); len(alerts) != 0 {
+		t.Fatalf("expected 0 alerts for bob, got %d", len(alerts))
+	}
+	if alerts := s.EvaluateMemberConflicts("alice", "w-2"); len(alerts) != 1 {
+		t.Fatalf("expected 1 alert for alice, got %d", len(alerts))
+	}
+}
+
+func TestGetProfile_DefaultsToEmpty(t *testing.T) {
+	s := New()
+	p := s.GetProfile("nobody")
+	if p.UserID != "nobody" || p.HomeCity != "" {
+		t.Fatalf("unexpected profile %+v", p)
+	}
+}
+
+func TestForecast_ConvertsForeignCurrency(t *testing.T) {
+	s := New()
+	s.AddBudgetEntry(domain.BudgetEntry{
+		UserID: "demo-user", Category: "food", Amount: money.FromFloat(2520), Currency: "CZK", Date: "2026-02-12",
+	})
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	// 480 EUR seed + CZK 2520 at 25.2 = 100 EUR
+	if f.ProjectedMonthlySpend != money.FromFloat(580) {
+		t.Fatalf("expected 580, got %s", f.ProjectedMonthlySpend)
+	}
+	if f.Currency != "EUR" {
+		t.Fatalf("expected EUR, got %s", f.Currency)
+	}
+}
+
+func TestForecast_HomeCurrency(t *testing.T) {
+	s := New()
+	s.SaveProfile(domain.UserProfile{UserID: "demo-user", HomeCurrency: "PLN"})
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	// 480 EUR at 4.25 = 2040 PLN; budget 900 EUR = 3825 PLN
+	if f.Currency != "PLN" || f.ProjectedMonthlySpend != money.FromFloat(2040) || f.RemainingBudget != money.FromFloat(1785) {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+}
+
+func TestForecast_UnknownCurrencyWarns(t *testing.T) {
+	s := New()
+	s.AddBudgetEntry(domain.BudgetEntry{
+		UserID: "demo-user", Category: "food", Amount: money.FromFloat(10), Currency: "XYZ", Date: "2026-02-12",
+	})
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	if f.ProjectedMonthlySpend != money.FromFloat(480) || len(f.Warnings) != 1 {
+		t.Fatalf("expected skipped entry with warning, got %+v", f)
+	}
+}
+
+func TestSaveFXRates_Upserts(t *testing.T) {
+	s := New()
+	before := len(s.ListFXRates())
+	s.SaveFXRates([]domain.FXRate{
+		{Date: "2026-01-02", Currency: "CZK", Rate: 25},
+		{Date: "2026-02-02", Currency: "CZK", Rate: 24.9},
+	})
+	if got := len(s.ListFXRates()); got != before+1 {
+		t.Fatalf("expected %d rates, got %d", before+1, got)
+	}
+}
+
+func TestGetBudgetSettings_Default(t *testing.T) {
+	s := New()
+	settings := s.GetBudgetSettings("nobody")
+	if settings.MonthlyBudget != money.FromFloat(900) || settings.Currency != "EUR" || settings.StartDay != 1 {
+		t.Fatalf("unexpected defaults %+v", settings)
+	}
+}
+
+func TestForecast_UsesBudgetSettings(t *testing.T) {
+	s := New()
+	s.SaveBudgetSettings(domain.BudgetSettings{
+		UserID: "demo-user", MonthlyBudget: money.FromFloat(600), Currency: "EUR", StartDay: 1,
+		Allocations: map[string]money.Amount{"living": money.FromFloat(400), "food": money.FromFloat(100)},
+	})
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	if f.MonthlyBudget != money.FromFloat(600) || f.RemainingBudget != money.FromFloat(120) || f.Affordability != "amber" {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+	byCategory := map[string]domain.CategorySpend{}
+	for _, c := range f.Categories {
+		byCategory[c.Category] = c
+	}
+	if got := byCategory["living"]; got.Spent != money.FromFloat(420) || got.Remaining != money.FromFloat(-20) {
+		t.Fatalf("unexpected living spend %+v", got)
+	}
+	if got := byCategory["travel"]; got.Spent != money.FromFloat(60) || got.Allocation != 0 {
+		t.Fatalf("unexpected travel spend %+v", got)
+	}
+	if got := byCategory["food"]; got.Allocation != money.FromFloat(100) || got.Remaining != money.FromFloat(100) {
+		t.Fatalf("unexpected food spend %+v", got)
+	}
+}
+
+func TestForecast_ConvertsBudgetCurrency(t *testing.T) {
+	s := New()
+	s.SaveBudgetSettings(domain.BudgetSettings{UserID: "demo-user", MonthlyBudget: money.FromFloat(4250), Currency: "PLN", StartDay: 1})
+	f := s.Forecast("demo-user", "", "2026-02-28")
+	// PLN 4250 at 4.25 = 1000 EUR
+	if f.MonthlyBudget != money.FromFloat(1000) || f.RemainingBudget != money.FromFloat(520) {
+		t.Fatalf("unexpected forecast %+v", f)
+	}
+}
+
+func TestQueryBudgetEntries_Filters(t *testing.T) {
+	s := New()
+	s.AddBudgetEntry(domain.BudgetEntry{UserID: "demo-user", Category: "food", Amount: money.FromFloat(12.5), Currency: "CZK", Date: "2026-02-20", Note: "Pizza near Old Town", TripID: "trip-1"})
+	s.AddBudgetEntry(domain.BudgetEntry{UserID: "alice", Category: "food", Amount: money.FromFloat(9), Currency: "EUR", Date: "2026-02-20", Note: "pizza"})
+
+	min, max := money.FromFloat(10), money.FromFloat(100)
+	cases := []struct {
+		name   string
+		filter domain.BudgetEntryFilter
+		want   int
+	}{
+		{"all", domain.BudgetEntryFilter{}, 3},
+		{"date range", domain.BudgetEntryFilter{From: "2026-02-06", To: "2026-02-20"}, 2},
+		{"category", domain.BudgetEntryFilter{Category: "FOOD"}, 1},
+		{"trip", domain.BudgetEntryFilter{TripID: "trip-1"}, 1},
+		{"currency", domain.BudgetEntryFilter{Currency: "czk"}, 1},
+		{"amount range", domain.BudgetEntryFilter{MinAmount: &min, MaxAmount: &max}, 2},
+		{"text search", domain.BudgetEntryFilter{Query: "PIZZA"}, 1},
+	}
+	for _, tc := range cases {
+		if got := s.QueryBudgetEntries("demo-user", tc.filter); got.Total != tc.want || len(got.Entries) != tc.want {
+			t.Fatalf("%s: expected %d entries, got total=%d len=%d", tc.name, tc.want, got.Total, len(got.Entries))
+		}
+	}
+}
+
+func TestQueryBudgetEntries_Pagination(t *testing.T) {
+	s := New()
+	page := s.QueryBudgetEntries("demo-user", domain.BudgetEntryFilter{Limit: 1, Offset: 1})
+	if page.Total != 2 || len(page.Entries) != 1 || page.Entries[0].ID != "b-2" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	page = s.QueryBudgetEntries("demo-user", domain.BudgetEntryFilter{Limit: 5, Offset: 10})
+	if page.Total != 2 || len(page.Entries) != 0 {
+		t.Fatalf("expected empty page past the end, got %+v", page)
+	}
+}
+
+func TestUpdateBudgetEntry(t *testing.T) {
+	s := New()
+	amount, note := money.FromFloat(430), "Rent split (corrected)"
+	entry, err := s.UpdateBudgetEntry("demo-user", "b-1", domain.BudgetEntryPatch{Amount: &amount, Note: &note})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.Amount != money.FromFloat(430) || entry.Note != note || entry.Category != "living" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if _, err := s.UpdateBudgetEntry("alice", "b-1", domain.BudgetEntryPatch{Amount: &amount}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := s.UpdateBudgetEntry("demo-user", "missing", domain.BudgetEntryPatch{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDeleteBudgetEntry(t *testing.T) {
+	s := New()
+	if err := s.DeleteBudgetEntry("alice", "b-1"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := s.DeleteBudgetEntry("demo-user", "b-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := s.ListBudgetEntries("demo-user"); len(entries) != 1 {
+		t.Fatalf("expected 1 entry left, got %d", len(entries))
+	}
+}
+
+func TestStreamBudgetEntries_StopsOnError(t *testing.T) {
+	s := New()
+	stop := errors.New("stop")
+	seen := 0
+	err := s.StreamBudgetEntries("demo-user", domain.BudgetEntryFilter{Limit: 1}, func(domain.BudgetEntry) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) || seen != 1 {
+		t.Fatalf("expected to stop after the first entry, got err=%v seen=%d", err, seen)
+	}
+	seen = 0
+	s.StreamBudgetEntries("demo-user", domain.BudgetEntryFilter{Limit: 1}, func(domain.BudgetEntry) error {
+		seen++
+		return nil
+	})
+	if seen != 2 {
+		t.Fatalf("pagination should be ignored when streaming, saw %d", seen)
+	}
+}
+
+func TestFinalizeTripReconciliation_AdjustsOptimizer(t *testing.T) {
+	s := New()
+	prague := func() domain.TripOption {
+		for _, opt := range s.OptimizeTrips(domain.TripConstraint{BudgetCap: money.FromFloat(500), MaxTravelHours: 6, PartySize: 1}) {
+			if opt.Destination == "Prague" {
+				return opt
+			}
+		}
+		t.Fatalf("Prague missing from optimizer results")
+		return domain.TripOption{}
+	}
+	before := prague().TotalEstimatedCost
+
+	s.AddBudgetEntry(domain.BudgetEntry{UserID: "demo-user", Category: "travel", Amount: money.FromFloat(93), Currency: "EUR", Date: "2026-03-06", TripID: "trip-1"})
+	report, err := s.FinalizeTripReconciliation("trip-1", "EUR")
+	if err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if !report.Finalized || report.Lines[0].Actual != money.FromFloat(93) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	// Transport came in at 1.5x the estimate; with the prior that is a 1.25 factor.
+	if after := prague().TotalEstimatedCost; after != before+money.FromFloat(16) {
+		t.Fatalf("expected Prague estimate to rise from %v by 16, got %v", before, after)
+	}
+
+	if _, err := s.ReconcileTrip("missing", "EUR"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
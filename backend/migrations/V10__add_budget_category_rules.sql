@@ -0,0 +1,5 @@
+-- Per-user keyword -> category rules applied to bank statement imports
+CREATE TABLE IF NOT EXISTS budget_category_rules (
+    user_id TEXT PRIMARY KEY,
+    rules   JSONB NOT NULL DEFAULT '[]'
+);
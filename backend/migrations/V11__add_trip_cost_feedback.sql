@@ -0,0 +1,12 @@
+-- Actual/estimated cost ratios recorded when a trip's reconciliation is finalised;
+-- averaged per destination to adjust future optimizer estimates
+CREATE TABLE IF NOT EXISTS trip_cost_feedback (
+    trip_id         TEXT PRIMARY KEY,
+    destination     TEXT NOT NULL,
+    transport_ratio DOUBLE PRECISION NOT NULL DEFAULT 0,
+    stay_ratio      DOUBLE PRECISION NOT NULL DEFAULT 0,
+    daily_ratio     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    finalized_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_cost_feedback_destination ON trip_cost_feedback(LOWER(destination));
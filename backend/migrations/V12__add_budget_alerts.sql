@@ -0,0 +1,39 @@
+-- User-defined budget alert rules and the alerts they triggered
+CREATE TABLE IF NOT EXISTS budget_alert_rules (
+    id        TEXT PRIMARY KEY,
+    user_id   TEXT NOT NULL,
+    kind      TEXT NOT NULL
+        CHECK (kind IN ('category-over', 'remaining-under', 'expense-over', 'trip-into-red')),
+    category  TEXT NOT NULL DEFAULT '',
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    currency  TEXT NOT NULL DEFAULT '',
+    disabled  BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_alert_rules_user_id ON budget_alert_rules(user_id);
+
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    rule_id      TEXT NOT NULL,
+    kind         TEXT NOT NULL,
+    severity     TEXT NOT NULL,
+    message      TEXT NOT NULL,
+    value        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    threshold    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    currency     TEXT NOT NULL DEFAULT '',
+    category     TEXT NOT NULL DEFAULT '',
+    entry_id     TEXT NOT NULL DEFAULT '',
+    trip_id      TEXT NOT NULL DEFAULT '',
+    period_start TEXT NOT NULL DEFAULT '',
+    alert_key    TEXT NOT NULL,
+    read         BOOLEAN NOT NULL DEFAULT FALSE,
+    triggered_at TEXT NOT NULL
+);
+
+-- A condition is reported once per user however often rules are re-evaluated
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budget_alerts_user_key ON budget_alerts(user_id, alert_key);
+CREATE INDEX IF NOT EXISTS idx_budget_alerts_user_triggered ON budget_alerts(user_id, triggered_at DESC);
+
+-- Remaining budget below which forecasts turn amber; 0 keeps the €200 default
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS amber_threshold DOUBLE PRECISION NOT NULL DEFAULT 0;
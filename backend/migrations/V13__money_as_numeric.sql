@@ -0,0 +1,12 @@
+-- Money columns become exact decimals; the application handles them as integer cents
+ALTER TABLE budget_entries ALTER COLUMN amount TYPE NUMERIC(14, 2) USING ROUND(amount::numeric, 2);
+ALTER TABLE recurring_budget_entries ALTER COLUMN amount TYPE NUMERIC(14, 2) USING ROUND(amount::numeric, 2);
+ALTER TABLE trips ALTER COLUMN estimated_cost TYPE NUMERIC(14, 2) USING ROUND(estimated_cost::numeric, 2);
+ALTER TABLE monthly_budgets ALTER COLUMN budget TYPE NUMERIC(14, 2) USING ROUND(budget::numeric, 2);
+ALTER TABLE monthly_budgets ALTER COLUMN amber_threshold TYPE NUMERIC(14, 2) USING ROUND(amber_threshold::numeric, 2);
+-- Category-over thresholds are percentages, kept to two decimals like amounts
+ALTER TABLE budget_alert_rules ALTER COLUMN threshold TYPE NUMERIC(14, 2) USING ROUND(threshold::numeric, 2);
+ALTER TABLE budget_alerts ALTER COLUMN value TYPE NUMERIC(14, 2) USING ROUND(value::numeric, 2);
+ALTER TABLE budget_alerts ALTER COLUMN threshold TYPE NUMERIC(14, 2) USING ROUND(threshold::numeric, 2);
+ALTER TABLE destinations ALTER COLUMN transport_base TYPE NUMERIC(14, 2) USING ROUND(transport_base::numeric, 2);
+ALTER TABLE destinations ALTER COLUMN hostel_night_eur TYPE NUMERIC(14, 2) USING ROUND(hostel_night_eur::numeric, 2);
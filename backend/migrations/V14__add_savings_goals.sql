@@ -0,0 +1,22 @@
+-- Savings goals towards planned trips and the contributions made to them
+CREATE TABLE IF NOT EXISTS savings_goals (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    trip_id     TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    target      NUMERIC(14, 2) NOT NULL CHECK (target > 0),
+    currency    TEXT NOT NULL,
+    target_date TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_savings_goals_user_id ON savings_goals(user_id);
+
+CREATE TABLE IF NOT EXISTS savings_contributions (
+    id      TEXT PRIMARY KEY,
+    goal_id TEXT NOT NULL REFERENCES savings_goals(id) ON DELETE CASCADE,
+    amount  NUMERIC(14, 2) NOT NULL,
+    date    TEXT NOT NULL,
+    note    TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_savings_contributions_goal_id ON savings_contributions(goal_id);
@@ -0,0 +1,9 @@
+-- Cached provider search answers, keyed by provider and normalised query
+CREATE TABLE IF NOT EXISTS provider_search_cache (
+    key        TEXT PRIMARY KEY,
+    options    JSONB NOT NULL DEFAULT '[]',
+    stored_at  TIMESTAMPTZ NOT NULL,
+    keep_until TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_provider_search_cache_keep_until ON provider_search_cache(keep_until);
@@ -0,0 +1,2 @@
+-- Rail passes and discount cards held by each user, priced into searches
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS passes JSONB NOT NULL DEFAULT '[]';
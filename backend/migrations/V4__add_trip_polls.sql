@@ -0,0 +1,25 @@
+-- Destination / travel-window polls on a trip
+CREATE TABLE IF NOT EXISTS trip_polls (
+    id               TEXT PRIMARY KEY,
+    trip_id          TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    created_by       TEXT NOT NULL,
+    question         TEXT NOT NULL,
+    kind             TEXT NOT NULL,
+    options          JSONB NOT NULL DEFAULT '[]',
+    close_rule       TEXT NOT NULL DEFAULT 'manual',
+    closes_at        TEXT NOT NULL DEFAULT '',
+    auto_apply       BOOLEAN NOT NULL DEFAULT FALSE,
+    status           TEXT NOT NULL DEFAULT 'open',
+    winner_option_id TEXT NOT NULL DEFAULT '',
+    applied          BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_polls_trip_id ON trip_polls(trip_id);
+
+-- One vote per member per poll
+CREATE TABLE IF NOT EXISTS trip_poll_votes (
+    poll_id   TEXT NOT NULL REFERENCES trip_polls(id) ON DELETE CASCADE,
+    user_id   TEXT NOT NULL,
+    option_id TEXT NOT NULL,
+    PRIMARY KEY (poll_id, user_id)
+);
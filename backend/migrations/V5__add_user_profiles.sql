@@ -0,0 +1,16 @@
+-- Per-user travel preferences used by group optimization
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id          TEXT PRIMARY KEY,
+    display_name     TEXT NOT NULL DEFAULT '',
+    home_city        TEXT NOT NULL DEFAULT '',
+    style            TEXT NOT NULL DEFAULT '',
+    max_travel_hours DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+INSERT INTO user_profiles (user_id, display_name, home_city, style, max_travel_hours) VALUES
+    ('demo-user', 'Demo User', 'Berlin', 'culture', 6)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- Academic events imported by a user only affect that user; '' means shared
+ALTER TABLE academic_events ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_academic_events_user_id ON academic_events(user_id);
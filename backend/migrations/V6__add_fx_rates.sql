@@ -0,0 +1,19 @@
+-- ECB-style reference rates: units of currency per 1 EUR
+CREATE TABLE IF NOT EXISTS fx_rates (
+    date     TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    rate     DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (date, currency)
+);
+
+INSERT INTO fx_rates (date, currency, rate) VALUES
+    ('2026-01-02', 'CZK', 25.2),
+    ('2026-01-02', 'HUF', 400),
+    ('2026-01-02', 'PLN', 4.25),
+    ('2026-01-02', 'CHF', 0.94),
+    ('2026-01-02', 'GBP', 0.84),
+    ('2026-01-02', 'USD', 1.08)
+ON CONFLICT (date, currency) DO NOTHING;
+
+-- Currency forecasts and totals are reported in
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS home_currency TEXT NOT NULL DEFAULT 'EUR';
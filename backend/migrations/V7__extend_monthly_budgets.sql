@@ -0,0 +1,9 @@
+-- Per-user budget settings: category allocations, currency and period start day
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS allocations JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'EUR';
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS start_day INT NOT NULL DEFAULT 1
+    CHECK (start_day BETWEEN 1 AND 28);
+
+UPDATE monthly_budgets
+SET allocations = '{"living": 500, "travel": 250, "food": 150}'
+WHERE user_id = 'demo-user' AND allocations = '{}';
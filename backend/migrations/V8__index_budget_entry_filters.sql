@@ -0,0 +1,9 @@
+-- Indexes backing the filtered /api/budget/entries listing
+CREATE INDEX IF NOT EXISTS idx_budget_entries_user_date ON budget_entries(user_id, date, id);
+CREATE INDEX IF NOT EXISTS idx_budget_entries_user_category ON budget_entries(user_id, LOWER(category));
+CREATE INDEX IF NOT EXISTS idx_budget_entries_trip_id ON budget_entries(trip_id) WHERE trip_id <> '';
+CREATE INDEX IF NOT EXISTS idx_budget_entries_user_amount ON budget_entries(user_id, amount);
+
+-- Trigram index for ILIKE search in notes
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_budget_entries_note_trgm ON budget_entries USING GIN (note gin_trgm_ops);
@@ -0,0 +1,22 @@
+-- Recurring budget entry templates (rent, phone plans, transit passes)
+CREATE TABLE IF NOT EXISTS recurring_budget_entries (
+    id                   TEXT PRIMARY KEY,
+    user_id              TEXT NOT NULL,
+    category             TEXT NOT NULL,
+    amount               DOUBLE PRECISION NOT NULL CHECK (amount > 0),
+    currency             TEXT NOT NULL DEFAULT 'EUR',
+    note                 TEXT NOT NULL DEFAULT '',
+    frequency            TEXT NOT NULL CHECK (frequency IN ('monthly', 'weekly')),
+    interval_count       INT  NOT NULL DEFAULT 1 CHECK (interval_count >= 1),
+    start_date           TEXT NOT NULL,
+    end_date             TEXT NOT NULL DEFAULT '',
+    overrides            JSONB NOT NULL DEFAULT '[]',
+    materialized_through TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_budget_entries_user_id ON recurring_budget_entries(user_id);
+
+-- Generated entries point back at their template; one entry per occurrence
+ALTER TABLE budget_entries ADD COLUMN IF NOT EXISTS recurring_id TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budget_entries_recurring_date
+    ON budget_entries(recurring_id, date) WHERE recurring_id <> '';